@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	k3sConfigPath  = "/etc/rancher/k3s/config.yaml"
+	rke2ConfigPath = "/etc/rancher/rke2/config.yaml"
+
+	k3sInstallURL  = "https://get.k3s.io"
+	rke2InstallURL = "https://get.rke2.io"
+)
+
+// AgentJoinConfig give element to join a k3s or RKE2 server
+type AgentJoinConfig struct {
+	ServerURL string `json:"server-url"`        // Mandatory, ie: https://192.168.1.10:6443 for k3s, https://192.168.1.10:9345 for RKE2
+	Token     string `json:"token"`             // Mandatory, the server node token
+	Version   string `json:"version,omitempty"` // Optional, version installed, the stable channel by default
+}
+
+func (c *AgentJoinConfig) validate(name string) error {
+	if c == nil || len(c.ServerURL) == 0 || len(c.Token) == 0 {
+		return newError(kindInvalidArgument, errBootstrapperNotConfigured, name)
+	}
+
+	return nil
+}
+
+// agentBootstrapper join the VMs as k3s or RKE2 agents, both read their flags from a config file
+type agentBootstrapper struct {
+	name        string
+	configPath  string
+	installURL  string
+	installEnv  string
+	versionEnv  string
+	postInstall []string
+	config      *AgentJoinConfig
+}
+
+func newK3SBootstrapper(config *AgentJoinConfig) *agentBootstrapper {
+	return &agentBootstrapper{
+		name:       bootstrapperK3S,
+		configPath: k3sConfigPath,
+		installURL: k3sInstallURL,
+		installEnv: "INSTALL_K3S_EXEC=agent",
+		versionEnv: "INSTALL_K3S_VERSION",
+		config:     config,
+	}
+}
+
+func newRKE2Bootstrapper(config *AgentJoinConfig) *agentBootstrapper {
+	return &agentBootstrapper{
+		name:        bootstrapperRKE2,
+		configPath:  rke2ConfigPath,
+		installURL:  rke2InstallURL,
+		installEnv:  "INSTALL_RKE2_TYPE=agent",
+		versionEnv:  "INSTALL_RKE2_VERSION",
+		postInstall: []string{"systemctl enable --now rke2-agent.service"},
+		config:      config,
+	}
+}
+
+// installCommand returns the shell command installing and starting the agent
+func (b *agentBootstrapper) installCommand() string {
+	env := []string{b.installEnv}
+
+	if len(b.config.Version) > 0 {
+		env = append(env, fmt.Sprintf("%s=%s", b.versionEnv, b.config.Version))
+	}
+
+	commands := []string{fmt.Sprintf("curl -sfL %s | %s sh -", b.installURL, strings.Join(env, " "))}
+
+	return strings.Join(append(commands, b.postInstall...), " && ")
+}
+
+// agentConfig returns the agent config file content
+func (b *agentBootstrapper) agentConfig(vm *MultipassNode, extras *nodeCreationExtra) ([]byte, error) {
+	taints := make([]string, 0)
+
+	for _, taint := range extras.bootstrap.nodeTaints(extras.nodegroupID) {
+		taints = append(taints, taint.ToString())
+	}
+
+	return yaml.Marshal(map[string]interface{}{
+		"server":      b.config.ServerURL,
+		"token":       b.config.Token,
+		"node-name":   vm.NodeName,
+		"kubelet-arg": []string{"provider-id=" + vm.ProviderID},
+		"node-label":  vm.nodeLabelList(extras),
+		"node-taint":  taints,
+	})
+}
+
+func (b *agentBootstrapper) ConfigureKubelet(ctx context.Context, vm *MultipassNode, extras *nodeCreationExtra) error {
+	var out string
+
+	config, err := b.agentConfig(vm, extras)
+
+	if err != nil {
+		return wrapError(kindInternal, err, errKubeletNotConfigured, vm.NodeName, out, err)
+	}
+
+	backend, err := vm.backend(extras)
+
+	if err != nil {
+		return err
+	}
+
+	srcName := fmt.Sprintf("%s/%s-config-%s.yaml", extras.cacheDir, b.name, vm.NodeName)
+	dstName := fmt.Sprintf("/tmp/%s-config-%s.yaml", b.name, vm.NodeName)
+
+	if err = ioutil.WriteFile(srcName, config, 0600); err != nil {
+		return wrapError(kindVM, err, errKubeletNotConfigured, vm.NodeName, out, err)
+	}
+
+	defer os.Remove(srcName)
+
+	if err = backend.CopyFile(ctx, vm.NodeName, srcName, dstName); err != nil {
+		return wrapError(kindVM, err, errKubeletNotConfigured, vm.NodeName, out, err)
+	}
+
+	install := fmt.Sprintf("mkdir -p %s && install -m 0600 %s %s && rm -f %s", path.Dir(b.configPath), dstName, b.configPath, dstName)
+
+	if out, err = backend.Exec(ctx, vm.NodeName, sudoArgument, "sh", "-c", install); err != nil {
+		return wrapError(kindVM, err, errKubeletNotConfigured, vm.NodeName, out, err)
+	}
+
+	return nil
+}
+
+func (b *agentBootstrapper) Join(ctx context.Context, vm *MultipassNode, extras *nodeCreationExtra) error {
+	backend, err := vm.backend(extras)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = backend.Exec(ctx, vm.NodeName, sudoArgument, "sh", "-c", b.installCommand()); err != nil {
+		return wrapError(kindKubernetes, err, errAgentJoinFailed, b.name, vm.NodeName, err)
+	}
+
+	return nil
+}
+
+func (b *agentBootstrapper) CloudInit(vm *MultipassNode, extras *nodeCreationExtra) (map[string]interface{}, error) {
+	config, err := b.agentConfig(vm, extras)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		cloudInitWriteFiles: []interface{}{
+			map[string]interface{}{
+				"path":        b.configPath,
+				"permissions": "0600",
+				"content":     string(config),
+			},
+		},
+		cloudInitRunCmd: []interface{}{
+			[]interface{}{"sh", "-c", b.installCommand()},
+		},
+	}, nil
+}
+
+func (b *agentBootstrapper) WaitReady(ctx context.Context, vm *MultipassNode, extras *nodeCreationExtra) error {
+	return vm.waitReady(ctx, extras.kubeConfig, extras.timeouts)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"runtime"
+
+	"github.com/golang/glog"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	backendMultipass = "multipass"
+)
+
+// VMLaunchSpec describe the VM to launch
+type VMLaunchSpec struct {
+	Name      string                 // VM name, also the kubernetes node name
+	Memory    int                    // Memory size in megabytes
+	CPU       int                    // Number of cpus
+	Disk      int                    // Disk size in megabytes
+	Image     string                 // Optional, image name or URL, the backend default when empty
+	Arch      string                 // Optional, kubernetes architecture name, ie: arm64, the host one when empty
+	CloudInit map[string]interface{} // Optional, cloud-init user data
+	Scripts   []*CloudInitScript     // Optional, shell scripts run by cloud-init, the user data is then multipart
+}
+
+// CloudInitScript is a shell script part of the multipart user data
+type CloudInitScript struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// hasUserData tells if the VM is launched with a cloud-init user data
+func (spec *VMLaunchSpec) hasUserData() bool {
+	return len(spec.CloudInit) > 0 || len(spec.Scripts) > 0
+}
+
+// userData returns the cloud-config, or a MIME multipart with the cloud-config and the shell scripts
+func (spec *VMLaunchSpec) userData() ([]byte, error) {
+	cloudConfig, err := yaml.Marshal(spec.CloudInit)
+
+	if err != nil {
+		return nil, wrapError(kindInternal, err, errCloudInitMarshallError, err)
+	}
+
+	cloudConfig = append([]byte("#cloud-config\n"), cloudConfig...)
+
+	if len(spec.Scripts) == 0 {
+		return cloudConfig, nil
+	}
+
+	var body bytes.Buffer
+
+	writer := multipart.NewWriter(&body)
+
+	addPart := func(contentType, fileName string, content []byte) error {
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {contentType + "; charset=\"us-ascii\""},
+			"Mime-Version":              {"1.0"},
+			"Content-Transfer-Encoding": {"7bit"},
+			"Content-Disposition":       {fmt.Sprintf("attachment; filename=\"%s\"", fileName)},
+		})
+
+		if err == nil {
+			_, err = part.Write(content)
+		}
+
+		return err
+	}
+
+	if err = addPart("text/cloud-config", "cloud-config.yaml", cloudConfig); err != nil {
+		return nil, wrapError(kindInternal, err, errCloudInitMarshallError, err)
+	}
+
+	for _, script := range spec.Scripts {
+		if err = addPart("text/x-shellscript", script.Name, []byte(script.Content)); err != nil {
+			return nil, wrapError(kindInternal, err, errCloudInitMarshallError, err)
+		}
+	}
+
+	if err = writer.Close(); err != nil {
+		return nil, wrapError(kindInternal, err, errCloudInitMarshallError, err)
+	}
+
+	header := fmt.Sprintf("Content-Type: multipart/mixed; boundary=\"%s\"\nMIME-Version: 1.0\n\n", writer.Boundary())
+
+	return append([]byte(header), body.Bytes()...), nil
+}
+
+// VMStatus describe the VM as seen by the hypervisor
+type VMStatus struct {
+	State     MultipassNodeState
+	Addresses []string
+}
+
+// VMBackend is the hypervisor driving the VMs of the node groups
+type VMBackend interface {
+	// Launch create and start the VM, it returns when the VM is running
+	Launch(ctx context.Context, spec *VMLaunchSpec) error
+	// Start a stopped VM
+	Start(ctx context.Context, name string) error
+	// Stop a running VM
+	Stop(ctx context.Context, name string) error
+	// Delete the VM and release its resources
+	Delete(ctx context.Context, name string) error
+	// Status returns the state and addresses of the VM
+	Status(ctx context.Context, name string) (*VMStatus, error)
+	// Exec run the command inside the VM and returns its output
+	Exec(ctx context.Context, name string, args ...string) (string, error)
+	// CopyFile copy a host file into the VM
+	CopyFile(ctx context.Context, name, src, dst string) error
+	// Mount share a host directory with the VM
+	Mount(ctx context.Context, name, hostPath, guestPath string) error
+	// List returns the name of all VMs known by the hypervisor
+	List(ctx context.Context) ([]string, error)
+}
+
+// newVMBackend returns the backend declared in config, multipass by default
+func newVMBackend(config *MultipassServerConfig, cacheDir string) (VMBackend, error) {
+	switch config.Backend {
+	case "", backendMultipass:
+		return newMultipassBackend(cacheDir), nil
+	case backendMultipassd:
+		return newMultipassdBackend(config.Multipassd, cacheDir), nil
+	case backendLXD:
+		return newLXDBackend(config.LXD), nil
+	case backendLibvirt:
+		return newLibvirtBackend(config.Libvirt, cacheDir), nil
+	case backendVSphere:
+		return newVSphereBackend(config.VSphere), nil
+	}
+
+	return nil, newError(kindInvalidArgument, errUnknownBackend, config.Backend)
+}
+
+// vmBackend returns the server backend, multipass if not set
+func (s *MultipassServer) vmBackend() VMBackend {
+	if s.Backend == nil {
+		backend, err := newVMBackend(&s.Configuration, s.CacheDir)
+
+		if err != nil {
+			glog.Errorf("%v, fallback to %s", err, backendMultipass)
+
+			backend = newMultipassBackend(s.CacheDir)
+		}
+
+		s.Backend = backend
+	}
+
+	return s.Backend
+}
+
+// kernelArchs map the kubernetes architecture names to the kernel ones used by LXD and libvirt
+var kernelArchs = map[string]string{
+	"amd64": "x86_64",
+	"386":   "i686",
+	"arm64": "aarch64",
+	"arm":   "armv7l",
+}
+
+// kernelArch returns the kernel architecture of the kubernetes one, the host architecture when empty
+func kernelArch(arch string) string {
+	if len(arch) == 0 {
+		arch = runtime.GOARCH
+	}
+
+	if name, found := kernelArchs[arch]; found {
+		return name
+	}
+
+	// ppc64le, s390x, riscv64 have the same name
+	return arch
+}
+
+// writeCloudInitFile write the cloud-init user data of the VM in dir, the caller must remove the file
+func writeCloudInitFile(dir string, spec *VMLaunchSpec) (string, error) {
+	fName := fmt.Sprintf("%s/cloud-init-%s.yaml", dir, spec.Name)
+
+	glog.Infof("Create cloud file: %s", fName)
+
+	b, err := spec.userData()
+
+	if err != nil {
+		return "", err
+	}
+
+	if err = ioutil.WriteFile(fName, b, 0644); err != nil {
+		os.Remove(fName)
+
+		return "", wrapError(kindInternal, err, errCloudInitWriteError, err)
+	}
+
+	return fName, nil
+}
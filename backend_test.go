@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+// fakeBackend is an in memory VMBackend
+type fakeBackend struct {
+	sync.Mutex
+	vms       map[string]*VMStatus
+	launchErr error
+	startErr  error
+	calls     []string
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		vms: make(map[string]*VMStatus),
+	}
+}
+
+func (b *fakeBackend) call(name string) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.calls = append(b.calls, name)
+}
+
+func (b *fakeBackend) vm(name string) (*VMStatus, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	if vm := b.vms[name]; vm != nil {
+		return vm, nil
+	}
+
+	return nil, newError(kindNotFound, errMultiPassInfoNotFound, name)
+}
+
+func (b *fakeBackend) Launch(ctx context.Context, spec *VMLaunchSpec) error {
+	b.call("launch")
+
+	if b.launchErr != nil {
+		return b.launchErr
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	b.vms[spec.Name] = &VMStatus{
+		State:     MultipassNodeStateRunning,
+		Addresses: []string{"10.0.0.1"},
+	}
+
+	return nil
+}
+
+func (b *fakeBackend) Start(ctx context.Context, name string) error {
+	b.call("start")
+
+	if b.startErr != nil {
+		return b.startErr
+	}
+
+	vm, err := b.vm(name)
+
+	if err == nil {
+		vm.State = MultipassNodeStateRunning
+	}
+
+	return err
+}
+
+func (b *fakeBackend) Stop(ctx context.Context, name string) error {
+	b.call("stop")
+
+	vm, err := b.vm(name)
+
+	if err == nil {
+		vm.State = MultipassNodeStateStopped
+	}
+
+	return err
+}
+
+func (b *fakeBackend) Delete(ctx context.Context, name string) error {
+	b.call("delete")
+
+	if _, err := b.vm(name); err != nil {
+		return err
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	delete(b.vms, name)
+
+	return nil
+}
+
+func (b *fakeBackend) Status(ctx context.Context, name string) (*VMStatus, error) {
+	b.call("status")
+
+	return b.vm(name)
+}
+
+func (b *fakeBackend) Exec(ctx context.Context, name string, args ...string) (string, error) {
+	b.call("exec")
+
+	_, err := b.vm(name)
+
+	return "", err
+}
+
+func (b *fakeBackend) CopyFile(ctx context.Context, name, src, dst string) error {
+	b.call("copy")
+
+	_, err := b.vm(name)
+
+	return err
+}
+
+func (b *fakeBackend) Mount(ctx context.Context, name, hostPath, guestPath string) error {
+	b.call("mount")
+
+	_, err := b.vm(name)
+
+	return err
+}
+
+func (b *fakeBackend) List(ctx context.Context) ([]string, error) {
+	b.call("list")
+
+	b.Lock()
+	defer b.Unlock()
+
+	names := make([]string, 0, len(b.vms))
+
+	for name := range b.vms {
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+func newFakeExtras(backend VMBackend) *nodeCreationExtra {
+	return &nodeCreationExtra{
+		kubeConfig:  kubeconfig,
+		nodegroupID: testGroupID,
+		cacheDir:    ".",
+		backend:     backend,
+	}
+}
+
+func Test_newVMBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+		wantErr bool
+	}{
+		{
+			name:    "Default",
+			backend: "",
+		},
+		{
+			name:    "Multipass",
+			backend: backendMultipass,
+		},
+		{
+			name:    "Unknown",
+			backend: "hyperv",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newVMBackend(&MultipassServerConfig{Backend: tt.backend}, ".")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, internalError, errorClass(err))
+			} else if assert.NoError(t, err) {
+				assert.IsType(t, &multipassBackend{}, got)
+			}
+		})
+	}
+}
+
+func Test_multipassNode_backendLifecycle(t *testing.T) {
+	backend := newFakeBackend()
+	extras := newFakeExtras(backend)
+
+	vm := &MultipassNode{
+		ProviderID:       testProviderID,
+		NodeName:         testNodeName,
+		Memory:           2048,
+		CPU:              2,
+		Disk:             5120,
+		AutoProvisionned: true,
+	}
+
+	if assert.NoError(t, vm.launchVM(context.Background(), extras)) {
+		assert.Equal(t, MultipassNodeStateRunning, vm.State)
+		assert.Equal(t, []string{"10.0.0.1"}, vm.Addresses)
+	}
+
+	assert.Error(t, vm.launchVM(context.Background(), extras), "launch twice")
+
+	if assert.NoError(t, vm.stopVM(context.Background(), extras)) {
+		state, err := vm.statusVM(context.Background(), extras)
+
+		assert.NoError(t, err)
+		assert.Equal(t, MultipassNodeStateStopped, state)
+	}
+
+	if assert.NoError(t, vm.deleteVM(context.Background(), extras)) {
+		assert.Equal(t, MultipassNodeStateDeleted, vm.State)
+	}
+
+	_, err := vm.statusVM(context.Background(), extras)
+
+	assert.Equal(t, cloudProviderError, errorClass(err))
+	assert.Contains(t, backend.calls, "delete")
+}
+
+func Test_multipassNode_backendStart(t *testing.T) {
+	backend := newFakeBackend()
+	extras := newFakeExtras(backend)
+
+	vm := &MultipassNode{
+		NodeName:         testNodeName,
+		AutoProvisionned: true,
+	}
+
+	backend.vms[testNodeName] = &VMStatus{State: MultipassNodeStateStopped}
+
+	// A stopped VM is started
+	if assert.NoError(t, vm.startVM(context.Background(), extras)) {
+		assert.Equal(t, MultipassNodeStateRunning, vm.State)
+		assert.Contains(t, backend.calls, "start")
+	}
+
+	// A running VM is left as is
+	backend.calls = nil
+
+	if assert.NoError(t, vm.startVM(context.Background(), extras)) {
+		assert.NotContains(t, backend.calls, "start")
+	}
+
+	// The start failure is reported
+	backend.vms[testNodeName].State = MultipassNodeStateStopped
+	backend.startErr = newError(kindTransient, "instance is busy")
+	vm.State = MultipassNodeStateStopped
+
+	if err := vm.startVM(context.Background(), extras); assert.Error(t, err) {
+		assert.Equal(t, transientError, errorClass(err))
+		assert.Equal(t, MultipassNodeStateStopped, vm.State)
+	}
+
+	vm.AutoProvisionned = false
+
+	assert.Equal(t, codes.FailedPrecondition, kindOf(vm.startVM(context.Background(), extras)).code)
+}
+
+func Test_multipassNode_backendLaunchFailed(t *testing.T) {
+	backend := newFakeBackend()
+	backend.launchErr = newError(kindTransient, "instance is busy")
+
+	vm := &MultipassNode{
+		NodeName:         testNodeName,
+		AutoProvisionned: true,
+	}
+
+	err := vm.launchVM(context.Background(), newFakeExtras(backend))
+
+	if assert.Error(t, err) {
+		assert.Equal(t, transientError, errorClass(err))
+		assert.True(t, errors.Is(err, backend.launchErr))
+	}
+}
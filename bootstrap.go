@@ -0,0 +1,302 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+const (
+	bootstrapExec      = "exec"
+	bootstrapCloudInit = "cloud-init"
+
+	kubeadmJoinConfigPath = "/etc/kubernetes/kubeadm-join.yaml"
+
+	cloudInitWriteFiles = "write_files"
+	cloudInitRunCmd     = "runcmd"
+)
+
+// NodeTaint declare a taint registered by the kubelet when the node join the cluster
+type NodeTaint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Effect string `json:"effect"`
+}
+
+// BootstrapConfig declare how the new VMs join the cluster
+type BootstrapConfig struct {
+	Mode         string                  `json:"mode"`         // Optional, exec or cloud-init, exec by default
+	Taints       map[string][]*NodeTaint `json:"taints"`       // Optional, per node group, taints registered by the kubelet
+	Bootstrapper string                  `json:"bootstrapper"` // Optional, kubeadm, k3s or rke2, kubeadm by default
+	NodeGroups   map[string]string       `json:"node-groups"`  // Optional, per node group, bootstrapper overriding the default one
+	K3S          *AgentJoinConfig        `json:"k3s"`          // Optional, k3s server joined by the k3s agents
+	RKE2         *AgentJoinConfig        `json:"rke2"`         // Optional, RKE2 server joined by the RKE2 agents
+}
+
+func (c *BootstrapConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+
+	switch c.Mode {
+	case "", bootstrapExec, bootstrapCloudInit:
+	default:
+		return newError(kindInvalidArgument, errUnknownBootstrapMode, c.Mode)
+	}
+
+	for _, name := range append([]string{c.Bootstrapper}, c.nodeGroupBootstrappers()...) {
+		switch name {
+		case "", bootstrapperKubeadm:
+		case bootstrapperK3S:
+			if err := c.K3S.validate(name); err != nil {
+				return err
+			}
+		case bootstrapperRKE2:
+			if err := c.RKE2.validate(name); err != nil {
+				return err
+			}
+		default:
+			return newError(kindInvalidArgument, errUnknownBootstrapper, name)
+		}
+	}
+
+	for nodeGroupID, taints := range c.Taints {
+		for _, taint := range taints {
+			switch apiv1.TaintEffect(taint.Effect) {
+			case apiv1.TaintEffectNoSchedule, apiv1.TaintEffectPreferNoSchedule, apiv1.TaintEffectNoExecute:
+				if len(taint.Key) > 0 {
+					continue
+				}
+			}
+
+			return newError(kindInvalidArgument, errInvalidNodeTaint, taint.Key, taint.Effect, nodeGroupID)
+		}
+	}
+
+	return nil
+}
+
+// cloudInit tells if the nodes join the cluster on first boot
+func (c *BootstrapConfig) cloudInit() bool {
+	return c != nil && c.Mode == bootstrapCloudInit
+}
+
+func (c *BootstrapConfig) nodeGroupBootstrappers() []string {
+	names := make([]string, 0, len(c.NodeGroups))
+
+	for _, name := range c.NodeGroups {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// bootstrapperName returns the bootstrapper of the node group, kubeadm by default
+func (c *BootstrapConfig) bootstrapperName(nodeGroupID string) string {
+	if c == nil {
+		return bootstrapperKubeadm
+	}
+
+	if name := c.NodeGroups[nodeGroupID]; len(name) > 0 {
+		return name
+	}
+
+	if len(c.Bootstrapper) > 0 {
+		return c.Bootstrapper
+	}
+
+	return bootstrapperKubeadm
+}
+
+// newBootstrapper returns the bootstrapper joining the node group VMs
+func (c *BootstrapConfig) newBootstrapper(nodeGroupID string) Bootstrapper {
+	switch c.bootstrapperName(nodeGroupID) {
+	case bootstrapperK3S:
+		return newK3SBootstrapper(c.K3S)
+	case bootstrapperRKE2:
+		return newRKE2Bootstrapper(c.RKE2)
+	}
+
+	return &kubeadmBootstrapper{}
+}
+
+// nodeTaints returns the taints of the node group nodes, kubeadm register them only with cloud-init bootstrap
+func (c *BootstrapConfig) nodeTaints(nodeGroupID string) []apiv1.Taint {
+	if !c.cloudInit() && c.bootstrapperName(nodeGroupID) == bootstrapperKubeadm {
+		return nil
+	}
+
+	taints := make([]apiv1.Taint, 0, len(c.Taints[nodeGroupID]))
+
+	for _, taint := range c.Taints[nodeGroupID] {
+		taints = append(taints, apiv1.Taint{
+			Key:    taint.Key,
+			Value:  taint.Value,
+			Effect: apiv1.TaintEffect(taint.Effect),
+		})
+	}
+
+	return taints
+}
+
+// nodeLabelList returns the node labels as sorted key=value
+func (vm *MultipassNode) nodeLabelList(extras *nodeCreationExtra) []string {
+	labels := make([]string, 0, len(extras.nodeLabels)+len(extras.systemLabels)+1)
+
+	for k, v := range extras.nodeLabels {
+		labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	for k, v := range extras.systemLabels {
+		labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	if len(vm.Host) > 0 {
+		labels = append(labels, fmt.Sprintf("%s=%s", nodeLabelZone, vm.Host))
+	}
+
+	sort.Strings(labels)
+
+	return labels
+}
+
+// kubeletLabels returns the node labels, as expected by the kubelet --node-labels flag
+func (vm *MultipassNode) kubeletLabels(extras *nodeCreationExtra) string {
+	return strings.Join(vm.nodeLabelList(extras), ",")
+}
+
+// joinConfiguration returns the kubeadm JoinConfiguration of the node
+func (vm *MultipassNode) joinConfiguration(extras *nodeCreationExtra) map[string]interface{} {
+	kubeletExtraArgs := map[string]interface{}{
+		"provider-id": vm.ProviderID,
+	}
+
+	if labels := vm.kubeletLabels(extras); len(labels) > 0 {
+		kubeletExtraArgs["node-labels"] = labels
+	}
+
+	for name, value := range extras.kubelet.configurationFlags() {
+		kubeletExtraArgs[name] = value
+	}
+
+	taints := make([]interface{}, 0)
+
+	nodeTaints := append(extras.bootstrap.nodeTaints(extras.nodegroupID), extras.kubelet.registerTaints()...)
+
+	// Explicit taints replace the kubeadm control plane taint
+	if extras.controlPlane {
+		nodeTaints = append(nodeTaints, controlPlaneTaints()...)
+	}
+
+	for _, taint := range nodeTaints {
+		taints = append(taints, map[string]interface{}{
+			"key":    taint.Key,
+			"value":  taint.Value,
+			"effect": string(taint.Effect),
+		})
+	}
+
+	config := map[string]interface{}{
+		"apiVersion": "kubeadm.k8s.io/v1beta2",
+		"kind":       "JoinConfiguration",
+		"discovery": map[string]interface{}{
+			"bootstrapToken": map[string]interface{}{
+				"apiServerEndpoint": extras.kubeHost,
+				"token":             extras.kubeToken,
+				"caCertHashes":      []interface{}{extras.kubeCACert},
+			},
+		},
+		"nodeRegistration": map[string]interface{}{
+			"name":             vm.NodeName,
+			"kubeletExtraArgs": kubeletExtraArgs,
+			"taints":           taints,
+		},
+	}
+
+	if extras.controlPlane {
+		config["controlPlane"] = map[string]interface{}{
+			"certificateKey": extras.certificateKey,
+		}
+	}
+
+	return config
+}
+
+// userData returns the composed cloud-init of the VM, with the cluster join on first boot in cloud-init bootstrap
+func (vm *MultipassNode) userData(extras *nodeCreationExtra) (map[string]interface{}, error) {
+	cloudInit, err := vm.composeCloudInit(extras)
+
+	if err != nil || !extras.vmprovision || !extras.bootstrap.cloudInit() {
+		return cloudInit, err
+	}
+
+	// The bootstrapper snippet is the last layer
+	snippet, err := extras.clusterBootstrapper().CloudInit(vm, extras)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cloudInit == nil {
+		cloudInit = make(map[string]interface{})
+	}
+
+	if err = mergeCloudInit(cloudInit, snippet); err != nil {
+		return nil, err
+	}
+
+	return cloudInit, nil
+}
+
+// waitJoined poll the node until it's registered or the context is done
+func (vm *MultipassNode) waitJoined(ctx context.Context, extras *nodeCreationExtra) error {
+	glog.V(5).Infof("multipassNode::waitJoined, node:%s", vm.NodeName)
+
+	args := []string{
+		kubectlCommandLine,
+		getArgument,
+		nodesArgument,
+		vm.NodeName,
+		kubeConfigArgument,
+		extras.kubeConfig,
+	}
+
+	for {
+		infoCtx, cancel := context.WithTimeout(ctx, extras.timeouts.info())
+
+		_, err := pipe(infoCtx, args...)
+
+		cancel()
+
+		if err == nil {
+			glog.Infof("The kubernetes node %s joined the cluster", vm.NodeName)
+			return nil
+		}
+
+		glog.Infof("The kubernetes node:%s is not joined", vm.NodeName)
+
+		select {
+		case <-ctx.Done():
+			return newError(kindKubernetes, errNodeNotJoined, vm.NodeName)
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// waitBootstrap wait the node joined by cloud-init is ready, then annotate it
+func (vm *MultipassNode) waitBootstrap(ctx context.Context, extras *nodeCreationExtra) error {
+	if err := vm.waitJoined(ctx, extras); err != nil {
+		return err
+	}
+
+	if err := extras.clusterBootstrapper().WaitReady(ctx, vm, extras); err != nil {
+		return err
+	}
+
+	return vm.annotateNode(ctx, extras)
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"gopkg.in/yaml.v2"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+func newTestBootstrapExtras(config *BootstrapConfig) *nodeCreationExtra {
+	extras := newFakeExtras(newFakeBackend())
+
+	extras.kubeHost = "192.168.1.10:6443"
+	extras.kubeToken = "abcdef.0123456789abcdef"
+	extras.kubeCACert = "sha256:1234"
+	extras.vmprovision = true
+	extras.bootstrap = config
+	extras.nodeLabels = map[string]string{"team": "infra"}
+	extras.systemLabels = map[string]string{nodeLabelInstanceType: "standard"}
+	extras.cloudInit = map[string]interface{}{
+		"package_update": true,
+		"runcmd":         []interface{}{"echo hello"},
+	}
+
+	return extras
+}
+
+func Test_bootstrapConfig_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *BootstrapConfig
+		wantErr bool
+	}{
+		{
+			name: "Nil",
+		},
+		{
+			name:   "Exec",
+			config: &BootstrapConfig{Mode: bootstrapExec},
+		},
+		{
+			name: "CloudInit",
+			config: &BootstrapConfig{
+				Mode: bootstrapCloudInit,
+				Taints: map[string][]*NodeTaint{
+					testGroupID: {{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}},
+				},
+			},
+		},
+		{
+			name: "K3S",
+			config: &BootstrapConfig{
+				Bootstrapper: bootstrapperK3S,
+				K3S:          &AgentJoinConfig{ServerURL: "https://192.168.1.10:6443", Token: "secret"},
+			},
+		},
+		{
+			name: "NodeGroupRKE2",
+			config: &BootstrapConfig{
+				NodeGroups: map[string]string{testGroupID: bootstrapperRKE2},
+				RKE2:       &AgentJoinConfig{ServerURL: "https://192.168.1.10:9345", Token: "secret"},
+			},
+		},
+		{
+			name:    "K3SNotConfigured",
+			config:  &BootstrapConfig{Bootstrapper: bootstrapperK3S},
+			wantErr: true,
+		},
+		{
+			name: "RKE2WithoutToken",
+			config: &BootstrapConfig{
+				NodeGroups: map[string]string{testGroupID: bootstrapperRKE2},
+				RKE2:       &AgentJoinConfig{ServerURL: "https://192.168.1.10:9345"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "UnknownBootstrapper",
+			config:  &BootstrapConfig{Bootstrapper: "microk8s"},
+			wantErr: true,
+		},
+		{
+			name:    "UnknownMode",
+			config:  &BootstrapConfig{Mode: "ssh"},
+			wantErr: true,
+		},
+		{
+			name: "WrongEffect",
+			config: &BootstrapConfig{
+				Taints: map[string][]*NodeTaint{
+					testGroupID: {{Key: "dedicated", Effect: "Never"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "MissingKey",
+			config: &BootstrapConfig{
+				Taints: map[string][]*NodeTaint{
+					testGroupID: {{Effect: "NoExecute"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.validate()
+
+			if test.wantErr {
+				assert.Equal(t, codes.InvalidArgument, kindOf(err).code)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_multipassNode_userData(t *testing.T) {
+	vm := &MultipassNode{
+		ProviderID: testProviderID,
+		NodeName:   testNodeName,
+		Host:       "host-1",
+	}
+
+	// Exec bootstrap keeps the configured cloud-init
+	extras := newTestBootstrapExtras(&BootstrapConfig{Mode: bootstrapExec})
+
+	if cloudInit, err := vm.userData(extras); assert.NoError(t, err) {
+		assert.Equal(t, extras.cloudInit, cloudInit)
+	}
+
+	extras = newTestBootstrapExtras(&BootstrapConfig{
+		Mode: bootstrapCloudInit,
+		Taints: map[string][]*NodeTaint{
+			testGroupID: {{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}},
+		},
+	})
+
+	cloudInit, err := vm.userData(extras)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, true, cloudInit["package_update"])
+	assert.Equal(t, []interface{}{"echo hello"}, extras.cloudInit["runcmd"], "the configuration is not modified")
+	assert.Equal(t, []interface{}{
+		"echo hello",
+		[]interface{}{kubeadmArgument, joinArgument, "--config", kubeadmJoinConfigPath},
+	}, cloudInit["runcmd"])
+
+	writeFiles := cloudInit["write_files"].([]interface{})
+
+	if !assert.Len(t, writeFiles, 1) {
+		return
+	}
+
+	writeFile := writeFiles[0].(map[string]interface{})
+
+	assert.Equal(t, kubeadmJoinConfigPath, writeFile["path"])
+
+	var joinConfig struct {
+		Kind      string `yaml:"kind"`
+		Discovery struct {
+			BootstrapToken struct {
+				APIServerEndpoint string   `yaml:"apiServerEndpoint"`
+				Token             string   `yaml:"token"`
+				CACertHashes      []string `yaml:"caCertHashes"`
+			} `yaml:"bootstrapToken"`
+		} `yaml:"discovery"`
+		NodeRegistration struct {
+			Name             string            `yaml:"name"`
+			KubeletExtraArgs map[string]string `yaml:"kubeletExtraArgs"`
+			Taints           []NodeTaint       `yaml:"taints"`
+		} `yaml:"nodeRegistration"`
+	}
+
+	if assert.NoError(t, yaml.Unmarshal([]byte(writeFile["content"].(string)), &joinConfig)) {
+		assert.Equal(t, "JoinConfiguration", joinConfig.Kind)
+		assert.Equal(t, extras.kubeHost, joinConfig.Discovery.BootstrapToken.APIServerEndpoint)
+		assert.Equal(t, extras.kubeToken, joinConfig.Discovery.BootstrapToken.Token)
+		assert.Equal(t, []string{extras.kubeCACert}, joinConfig.Discovery.BootstrapToken.CACertHashes)
+		assert.Equal(t, testNodeName, joinConfig.NodeRegistration.Name)
+		assert.Equal(t, testProviderID, joinConfig.NodeRegistration.KubeletExtraArgs["provider-id"])
+		assert.Equal(t, "node.kubernetes.io/instance-type=standard,team=infra,topology.kubernetes.io/zone=host-1", joinConfig.NodeRegistration.KubeletExtraArgs["node-labels"])
+		assert.Equal(t, []NodeTaint{{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}}, joinConfig.NodeRegistration.Taints)
+	}
+
+	// A cloud-init list of the configuration must stay a list
+	extras.cloudInit = map[string]interface{}{"runcmd": "echo hello"}
+
+	_, err = vm.userData(extras)
+
+	assert.Error(t, err)
+}
+
+func Test_multipassNode_userDataKubelet(t *testing.T) {
+	vm := &MultipassNode{
+		ProviderID: testProviderID,
+		NodeName:   testNodeName,
+	}
+
+	extras := newTestBootstrapExtras(&BootstrapConfig{Mode: bootstrapCloudInit})
+	extras.kubelet = &KubeletConfig{MaxPods: 60, NodeIP: "192.168.2.0/24"}
+
+	cloudInit, err := vm.userData(extras)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// The node IP is set before the join, the kubelet isn't configured after
+	assert.Equal(t, []interface{}{
+		"echo hello",
+		extras.kubelet.nodeIPCommand(kubeadmJoinConfigPath),
+		[]interface{}{kubeadmArgument, joinArgument, "--config", kubeadmJoinConfigPath},
+	}, cloudInit["runcmd"])
+
+	var joinConfig struct {
+		NodeRegistration struct {
+			KubeletExtraArgs map[string]string `yaml:"kubeletExtraArgs"`
+		} `yaml:"nodeRegistration"`
+	}
+
+	writeFile := cloudInit["write_files"].([]interface{})[0].(map[string]interface{})
+
+	if assert.NoError(t, yaml.Unmarshal([]byte(writeFile["content"].(string)), &joinConfig)) {
+		assert.Equal(t, "60", joinConfig.NodeRegistration.KubeletExtraArgs["max-pods"])
+		assert.Equal(t, kubeletNodeIP, joinConfig.NodeRegistration.KubeletExtraArgs["node-ip"])
+		assert.Equal(t, testProviderID, joinConfig.NodeRegistration.KubeletExtraArgs["provider-id"])
+	}
+}
+
+func Test_bootstrapConfig_nodeTaints(t *testing.T) {
+	taints := map[string][]*NodeTaint{
+		testGroupID: {{Key: "dedicated", Effect: "NoExecute"}},
+	}
+
+	assert.Len(t, (&BootstrapConfig{Mode: bootstrapExec, Taints: taints}).nodeTaints(testGroupID), 0, "exec bootstrap don't register taints")
+	assert.Equal(t, []apiv1.Taint{{Key: "dedicated", Effect: apiv1.TaintEffectNoExecute}},
+		(&BootstrapConfig{Mode: bootstrapCloudInit, Taints: taints}).nodeTaints(testGroupID))
+}
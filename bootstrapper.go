@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	bootstrapperKubeadm = "kubeadm"
+	bootstrapperK3S     = "k3s"
+	bootstrapperRKE2    = "rke2"
+)
+
+// Bootstrapper join the VMs to the kubernetes cluster
+type Bootstrapper interface {
+	// ConfigureKubelet set the kubelet provider-id in the running VM, before joining
+	ConfigureKubelet(ctx context.Context, vm *MultipassNode, extras *nodeCreationExtra) error
+	// Join the running VM to the cluster
+	Join(ctx context.Context, vm *MultipassNode, extras *nodeCreationExtra) error
+	// CloudInit returns the cloud-init layer configuring the kubelet and joining the cluster on first boot
+	CloudInit(vm *MultipassNode, extras *nodeCreationExtra) (map[string]interface{}, error)
+	// WaitReady wait until the joined node is ready
+	WaitReady(ctx context.Context, vm *MultipassNode, extras *nodeCreationExtra) error
+}
+
+// kubeadmBootstrapper join the VMs with kubeadm
+type kubeadmBootstrapper struct {
+}
+
+func (b *kubeadmBootstrapper) ConfigureKubelet(ctx context.Context, vm *MultipassNode, extras *nodeCreationExtra) error {
+	return vm.prepareKubelet(ctx, extras)
+}
+
+func (b *kubeadmBootstrapper) Join(ctx context.Context, vm *MultipassNode, extras *nodeCreationExtra) error {
+	if err := vm.kubeAdmJoin(ctx, extras); err != nil {
+		return err
+	}
+
+	return vm.configureKubelet(ctx, extras)
+}
+
+func (b *kubeadmBootstrapper) CloudInit(vm *MultipassNode, extras *nodeCreationExtra) (map[string]interface{}, error) {
+	joinConfig, err := yaml.Marshal(vm.joinConfiguration(extras))
+
+	if err != nil {
+		return nil, err
+	}
+
+	runCmd := make([]interface{}, 0, 2)
+
+	// The VM address is unknown when the cloud-init is rendered
+	if command := extras.kubelet.nodeIPCommand(kubeadmJoinConfigPath); command != nil {
+		runCmd = append(runCmd, command)
+	}
+
+	return map[string]interface{}{
+		cloudInitWriteFiles: []interface{}{
+			map[string]interface{}{
+				"path":        kubeadmJoinConfigPath,
+				"permissions": "0600",
+				"content":     string(joinConfig),
+			},
+		},
+		cloudInitRunCmd: append(runCmd, []interface{}{kubeadmArgument, joinArgument, "--config", kubeadmJoinConfigPath}),
+	}, nil
+}
+
+func (b *kubeadmBootstrapper) WaitReady(ctx context.Context, vm *MultipassNode, extras *nodeCreationExtra) error {
+	return vm.waitReady(ctx, extras.kubeConfig, extras.timeouts)
+}
+
+// clusterBootstrapper returns the bootstrapper of the node group, kubeadm by default
+func (extras *nodeCreationExtra) clusterBootstrapper() Bootstrapper {
+	if extras.bootstrapper == nil {
+		return &kubeadmBootstrapper{}
+	}
+
+	return extras.bootstrapper
+}
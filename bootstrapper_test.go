@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func Test_bootstrapConfig_newBootstrapper(t *testing.T) {
+	k3s := &AgentJoinConfig{ServerURL: "https://192.168.1.10:6443", Token: "secret"}
+	config := &BootstrapConfig{
+		Bootstrapper: bootstrapperK3S,
+		NodeGroups:   map[string]string{"ng-kubeadm": bootstrapperKubeadm, "ng-rke2": bootstrapperRKE2},
+		K3S:          k3s,
+		RKE2:         &AgentJoinConfig{ServerURL: "https://192.168.1.10:9345", Token: "secret"},
+	}
+
+	assert.IsType(t, &kubeadmBootstrapper{}, (*BootstrapConfig)(nil).newBootstrapper(testGroupID))
+	assert.IsType(t, &kubeadmBootstrapper{}, config.newBootstrapper("ng-kubeadm"))
+
+	if bootstrapper, ok := config.newBootstrapper(testGroupID).(*agentBootstrapper); assert.True(t, ok) {
+		assert.Equal(t, bootstrapperK3S, bootstrapper.name)
+		assert.Equal(t, k3s, bootstrapper.config)
+	}
+
+	if bootstrapper, ok := config.newBootstrapper("ng-rke2").(*agentBootstrapper); assert.True(t, ok) {
+		assert.Equal(t, bootstrapperRKE2, bootstrapper.name)
+	}
+
+	assert.Nil(t, (&BootstrapConfig{Mode: bootstrapExec}).nodeTaints(testGroupID), "kubeadm register taints only with cloud-init")
+	assert.Len(t, (&BootstrapConfig{
+		Bootstrapper: bootstrapperK3S,
+		Taints:       map[string][]*NodeTaint{testGroupID: {{Key: "dedicated", Effect: "NoSchedule"}}},
+	}).nodeTaints(testGroupID), 1, "agents always register taints")
+}
+
+func Test_agentBootstrapper_installCommand(t *testing.T) {
+	tests := []struct {
+		name         string
+		bootstrapper *agentBootstrapper
+		want         string
+	}{
+		{
+			name:         "K3S",
+			bootstrapper: newK3SBootstrapper(&AgentJoinConfig{}),
+			want:         "curl -sfL https://get.k3s.io | INSTALL_K3S_EXEC=agent sh -",
+		},
+		{
+			name:         "K3SVersion",
+			bootstrapper: newK3SBootstrapper(&AgentJoinConfig{Version: "v1.20.4+k3s1"}),
+			want:         "curl -sfL https://get.k3s.io | INSTALL_K3S_EXEC=agent INSTALL_K3S_VERSION=v1.20.4+k3s1 sh -",
+		},
+		{
+			name:         "RKE2",
+			bootstrapper: newRKE2Bootstrapper(&AgentJoinConfig{}),
+			want:         "curl -sfL https://get.rke2.io | INSTALL_RKE2_TYPE=agent sh - && systemctl enable --now rke2-agent.service",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.bootstrapper.installCommand())
+		})
+	}
+}
+
+func Test_agentBootstrapper_cloudInit(t *testing.T) {
+	vm := &MultipassNode{
+		ProviderID: testProviderID,
+		NodeName:   testNodeName,
+		Host:       "host-1",
+	}
+
+	extras := newTestBootstrapExtras(&BootstrapConfig{
+		Mode:         bootstrapCloudInit,
+		Bootstrapper: bootstrapperRKE2,
+		RKE2:         &AgentJoinConfig{ServerURL: "https://192.168.1.10:9345", Token: "secret"},
+		Taints: map[string][]*NodeTaint{
+			testGroupID: {{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}},
+		},
+	})
+
+	extras.bootstrapper = extras.bootstrap.newBootstrapper(testGroupID)
+
+	cloudInit, err := vm.userData(extras)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []interface{}{
+		"echo hello",
+		[]interface{}{"sh", "-c", "curl -sfL https://get.rke2.io | INSTALL_RKE2_TYPE=agent sh - && systemctl enable --now rke2-agent.service"},
+	}, cloudInit["runcmd"])
+
+	writeFile := cloudInit["write_files"].([]interface{})[0].(map[string]interface{})
+
+	assert.Equal(t, rke2ConfigPath, writeFile["path"])
+
+	var config struct {
+		Server     string   `yaml:"server"`
+		Token      string   `yaml:"token"`
+		NodeName   string   `yaml:"node-name"`
+		KubeletArg []string `yaml:"kubelet-arg"`
+		NodeLabel  []string `yaml:"node-label"`
+		NodeTaint  []string `yaml:"node-taint"`
+	}
+
+	if assert.NoError(t, yaml.Unmarshal([]byte(writeFile["content"].(string)), &config)) {
+		assert.Equal(t, "https://192.168.1.10:9345", config.Server)
+		assert.Equal(t, "secret", config.Token)
+		assert.Equal(t, testNodeName, config.NodeName)
+		assert.Equal(t, []string{"provider-id=" + testProviderID}, config.KubeletArg)
+		assert.Equal(t, []string{"node.kubernetes.io/instance-type=standard", "team=infra", "topology.kubernetes.io/zone=host-1"}, config.NodeLabel)
+		assert.Equal(t, []string{"dedicated=gpu:NoSchedule"}, config.NodeTaint)
+	}
+}
+
+func Test_agentBootstrapper_join(t *testing.T) {
+	ctx := context.Background()
+	backend := newFakeBackend()
+	extras := newFakeExtras(backend)
+	bootstrapper := newK3SBootstrapper(&AgentJoinConfig{ServerURL: "https://192.168.1.10:6443", Token: "secret"})
+	vm := &MultipassNode{
+		ProviderID:       testProviderID,
+		NodeName:         testNodeName,
+		AutoProvisionned: true,
+	}
+
+	// The VM doesn't exist
+	assert.Error(t, bootstrapper.ConfigureKubelet(ctx, vm, extras))
+
+	backend.Launch(ctx, &VMLaunchSpec{Name: testNodeName})
+	backend.calls = nil
+
+	if assert.NoError(t, bootstrapper.ConfigureKubelet(ctx, vm, extras)) && assert.NoError(t, bootstrapper.Join(ctx, vm, extras)) {
+		assert.Equal(t, []string{"copy", "exec", "exec"}, backend.calls)
+	}
+}
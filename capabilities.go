@@ -0,0 +1,109 @@
+package main
+
+import (
+	"github.com/golang/glog"
+)
+
+const (
+	capabilityPricing                  = "pricing"
+	capabilityGetAvailableMachineTypes = "getAvailableMachineTypes"
+	capabilityNewNodeGroup             = "newNodeGroup"
+	capabilityTemplateNodeInfo         = "templateNodeInfo"
+	capabilityCreate                   = "create"
+	capabilityDelete                   = "delete"
+)
+
+// MultipassServerCapabilities declare which optional RPC are served, a disabled RPC returns codes.Unimplemented
+type MultipassServerCapabilities struct {
+	Pricing                  bool `json:"pricing"`
+	GetAvailableMachineTypes bool `json:"getAvailableMachineTypes"`
+	NewNodeGroup             bool `json:"newNodeGroup"`
+	TemplateNodeInfo         bool `json:"templateNodeInfo"`
+	Create                   bool `json:"create"`
+	Delete                   bool `json:"delete"`
+}
+
+// MultipassServerOptionals is deprecated, a true value disable the RPC. Use MultipassServerCapabilities
+type MultipassServerOptionals struct {
+	Pricing                  bool `json:"pricing"`
+	GetAvailableMachineTypes bool `json:"getAvailableMachineTypes"`
+	NewNodeGroup             bool `json:"newNodeGroup"`
+	TemplateNodeInfo         bool `json:"templateNodeInfo"`
+	Create                   bool `json:"create"`
+	Delete                   bool `json:"delete"`
+}
+
+// defaultCapabilities returns capabilities with all optional RPC enabled
+func defaultCapabilities() *MultipassServerCapabilities {
+	return &MultipassServerCapabilities{
+		Pricing:                  true,
+		GetAvailableMachineTypes: true,
+		NewNodeGroup:             true,
+		TemplateNodeInfo:         true,
+		Create:                   true,
+		Delete:                   true,
+	}
+}
+
+// applyOptionals disable the capabilities declared as optional by the deprecated optionals block
+func (c *MultipassServerCapabilities) applyOptionals(optionals *MultipassServerOptionals) {
+	if optionals != nil {
+		glog.Warning("The optionals config block is deprecated, use capabilities")
+
+		c.Pricing = c.Pricing && !optionals.Pricing
+		c.GetAvailableMachineTypes = c.GetAvailableMachineTypes && !optionals.GetAvailableMachineTypes
+		c.NewNodeGroup = c.NewNodeGroup && !optionals.NewNodeGroup
+		c.TemplateNodeInfo = c.TemplateNodeInfo && !optionals.TemplateNodeInfo
+		c.Create = c.Create && !optionals.Create
+		c.Delete = c.Delete && !optionals.Delete
+	}
+}
+
+func (c *MultipassServerCapabilities) toMap() map[string]bool {
+	return map[string]bool{
+		capabilityPricing:                  c.Pricing,
+		capabilityGetAvailableMachineTypes: c.GetAvailableMachineTypes,
+		capabilityNewNodeGroup:             c.NewNodeGroup,
+		capabilityTemplateNodeInfo:         c.TemplateNodeInfo,
+		capabilityCreate:                   c.Create,
+		capabilityDelete:                   c.Delete,
+	}
+}
+
+// log tells operators which optional RPC the autoscaler will see
+func (c *MultipassServerCapabilities) log() {
+	for _, name := range []string{
+		capabilityPricing,
+		capabilityGetAvailableMachineTypes,
+		capabilityNewNodeGroup,
+		capabilityTemplateNodeInfo,
+		capabilityCreate,
+		capabilityDelete,
+	} {
+		if c.toMap()[name] {
+			glog.Infof("Capability %s is enabled", name)
+		} else {
+			glog.Infof("Capability %s is disabled", name)
+		}
+	}
+}
+
+// capabilities returns the declared capabilities, without capabilities block everything is enabled
+func (s *MultipassServer) capabilities() *MultipassServerCapabilities {
+	if s.Configuration.Capabilities == nil {
+		return defaultCapabilities()
+	}
+
+	return s.Configuration.Capabilities
+}
+
+// checkCapability returns codes.Unimplemented if the capability is disabled
+func (s *MultipassServer) checkCapability(name string) error {
+	if s.capabilities().toMap()[name] {
+		return nil
+	}
+
+	glog.V(5).Infof(errCapabilityDisabled, name)
+
+	return toStatusError(newError(kindUnimplemented, errCapabilityDisabled, name))
+}
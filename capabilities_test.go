@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	apigrpc "github.com/Fred78290/kubernetes-multipass-autoscaler/grpc"
+)
+
+func TestMultipassServer_DisabledCapabilities(t *testing.T) {
+	config := MultipassServerConfig{
+		ProviderID: testProviderID,
+		Machines:   testMachines,
+		Capabilities: &MultipassServerCapabilities{
+			Pricing:                  false,
+			GetAvailableMachineTypes: false,
+			NewNodeGroup:             false,
+			TemplateNodeInfo:         false,
+			Create:                   false,
+			Delete:                   false,
+		},
+	}
+
+	cloudProviderRequest := &apigrpc.CloudProviderServiceRequest{
+		ProviderID: testProviderID,
+	}
+
+	nodeGroupRequest := &apigrpc.NodeGroupServiceRequest{
+		ProviderID:  testProviderID,
+		NodeGroupID: testGroupID,
+	}
+
+	s, ctx, err := newTestServerWithConfig(&testNodeGroup, config)
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{
+			name: "Pricing",
+			call: func() error {
+				_, err := s.Pricing(ctx, cloudProviderRequest)
+				return err
+			},
+		},
+		{
+			name: "NodePrice",
+			call: func() error {
+				_, err := s.NodePrice(ctx, &apigrpc.NodePriceRequest{ProviderID: testProviderID})
+				return err
+			},
+		},
+		{
+			name: "PodPrice",
+			call: func() error {
+				_, err := s.PodPrice(ctx, &apigrpc.PodPriceRequest{ProviderID: testProviderID})
+				return err
+			},
+		},
+		{
+			name: "GetAvailableMachineTypes",
+			call: func() error {
+				_, err := s.GetAvailableMachineTypes(ctx, cloudProviderRequest)
+				return err
+			},
+		},
+		{
+			name: "NewNodeGroup",
+			call: func() error {
+				_, err := s.NewNodeGroup(ctx, &apigrpc.NewNodeGroupRequest{
+					ProviderID:  testProviderID,
+					MachineType: "tiny",
+				})
+				return err
+			},
+		},
+		{
+			name: "TemplateNodeInfo",
+			call: func() error {
+				_, err := s.TemplateNodeInfo(ctx, nodeGroupRequest)
+				return err
+			},
+		},
+		{
+			name: "Create",
+			call: func() error {
+				_, err := s.Create(ctx, nodeGroupRequest)
+				return err
+			},
+		},
+		{
+			name: "Delete",
+			call: func() error {
+				_, err := s.Delete(ctx, nodeGroupRequest)
+				return err
+			},
+		},
+	}
+
+	if assert.NoError(t, err) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if err := tt.call(); status.Code(err) != codes.Unimplemented {
+					t.Errorf("MultipassServer.%s() error = %v, want codes.Unimplemented", tt.name, err)
+				}
+			})
+		}
+	}
+}
+
+func TestMultipassServerCapabilities_Decode(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   *MultipassServerCapabilities
+	}{
+		{
+			name:   "Defaults",
+			config: `{}`,
+			want:   defaultCapabilities(),
+		},
+		{
+			name:   "PartialCapabilities",
+			config: `{"capabilities": {"pricing": false}}`,
+			want: &MultipassServerCapabilities{
+				Pricing:                  false,
+				GetAvailableMachineTypes: true,
+				NewNodeGroup:             true,
+				TemplateNodeInfo:         true,
+				Create:                   true,
+				Delete:                   true,
+			},
+		},
+		{
+			name:   "LegacyOptionals",
+			config: `{"optionals": {"pricing": false, "create": true}}`,
+			want: &MultipassServerCapabilities{
+				Pricing:                  true,
+				GetAvailableMachineTypes: true,
+				NewNodeGroup:             true,
+				TemplateNodeInfo:         true,
+				Create:                   false,
+				Delete:                   true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := MultipassServerConfig{
+				Capabilities: defaultCapabilities(),
+			}
+
+			if assert.NoError(t, json.Unmarshal([]byte(tt.config), &config)) {
+				config.Capabilities.applyOptionals(config.Optionals)
+
+				assert.Equal(t, tt.want, config.Capabilities)
+			}
+		})
+	}
+}
+
+func TestMultipassServer_EnabledCapabilities(t *testing.T) {
+	s, _, err := newTestServerWithConfig(&testNodeGroup, MultipassServerConfig{
+		ProviderID: testProviderID,
+		Machines:   testMachines,
+	})
+
+	if assert.NoError(t, err) {
+		got, err := s.GetAvailableMachineTypes(context.Background(), &apigrpc.CloudProviderServiceRequest{
+			ProviderID: testProviderID,
+		})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"tiny", "medium", "large", "extra-large"}, got.GetAvailableMachineTypes().GetMachineType())
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+// cloudInitData is given to the cloud-init templates
+type cloudInitData struct {
+	NodeName      string
+	NodeIndex     int
+	NodeGroupID   string
+	ProviderID    string
+	MachineType   string
+	Host          string
+	Memory        int
+	CPU           int
+	Disk          int
+	Labels        map[string]string
+	KubeHost      string
+	KubeToken     string
+	KubeCACert    string
+	KubeExtraArgs []string
+	Variables     map[string]string
+}
+
+var cloudInitFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// parseCloudInitTemplate parse a cloud-init template, missing keys are errors
+func parseCloudInitTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(cloudInitFuncs).Option("missingkey=error").Parse(text)
+}
+
+// validateCloudInitTemplate checks the cloud-init template file could be parsed
+func validateCloudInitTemplate(fileName string) error {
+	if len(fileName) == 0 {
+		return nil
+	}
+
+	text, err := ioutil.ReadFile(fileName)
+
+	if err == nil {
+		_, err = parseCloudInitTemplate(fileName, string(text))
+	}
+
+	if err != nil {
+		return wrapError(kindInvalidArgument, err, errCloudInitTemplate, fileName, err)
+	}
+
+	return nil
+}
+
+// cloudInitData returns the values given to the cloud-init templates of the VM
+func (vm *MultipassNode) cloudInitData(extras *nodeCreationExtra) *cloudInitData {
+	labels := make(map[string]string)
+
+	for k, v := range extras.nodeLabels {
+		labels[k] = v
+	}
+
+	for k, v := range extras.systemLabels {
+		labels[k] = v
+	}
+
+	if len(vm.Host) > 0 {
+		labels[nodeLabelZone] = vm.Host
+	}
+
+	variables := extras.cloudInitVariables
+
+	if variables == nil {
+		variables = make(map[string]string)
+	}
+
+	return &cloudInitData{
+		NodeName:      vm.NodeName,
+		NodeIndex:     vm.NodeIndex,
+		NodeGroupID:   extras.nodegroupID,
+		ProviderID:    vm.ProviderID,
+		MachineType:   extras.machineType,
+		Host:          vm.Host,
+		Memory:        vm.Memory,
+		CPU:           vm.CPU,
+		Disk:          vm.Disk,
+		Labels:        labels,
+		KubeHost:      extras.kubeHost,
+		KubeToken:     extras.kubeToken,
+		KubeCACert:    extras.kubeCACert,
+		KubeExtraArgs: extras.kubeExtraArgs,
+		Variables:     variables,
+	}
+}
+
+func executeCloudInitTemplate(name, text string, data *cloudInitData) (string, error) {
+	var out bytes.Buffer
+
+	tmpl, err := parseCloudInitTemplate(name, text)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err = tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+
+	return out.String(), nil
+}
+
+// renderCloudInitValue render the strings found in a cloud-init value, the structure is kept
+func renderCloudInitValue(value interface{}, data *cloudInitData) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if !strings.Contains(v, "{{") {
+			return v, nil
+		}
+
+		return executeCloudInitTemplate("cloud-init", v, data)
+	case []interface{}:
+		list := make([]interface{}, 0, len(v))
+
+		for _, item := range v {
+			rendered, err := renderCloudInitValue(item, data)
+
+			if err != nil {
+				return nil, err
+			}
+
+			list = append(list, rendered)
+		}
+
+		return list, nil
+	case map[string]interface{}:
+		dict := make(map[string]interface{}, len(v))
+
+		for key, item := range v {
+			rendered, err := renderCloudInitValue(item, data)
+
+			if err != nil {
+				return nil, err
+			}
+
+			dict[key] = rendered
+		}
+
+		return dict, nil
+	case map[interface{}]interface{}:
+		dict := make(map[interface{}]interface{}, len(v))
+
+		for key, item := range v {
+			rendered, err := renderCloudInitValue(item, data)
+
+			if err != nil {
+				return nil, err
+			}
+
+			dict[key] = rendered
+		}
+
+		return dict, nil
+	}
+
+	return value, nil
+}
+
+// renderCloudInit returns the cloud-init of the VM, rendered from the template file or from the cloud-init section
+func (vm *MultipassNode) renderCloudInit(extras *nodeCreationExtra) (map[string]interface{}, error) {
+	data := vm.cloudInitData(extras)
+
+	if len(extras.cloudInitTemplate) == 0 {
+		if extras.cloudInit == nil {
+			return nil, nil
+		}
+
+		rendered, err := renderCloudInitValue(extras.cloudInit, data)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return rendered.(map[string]interface{}), nil
+	}
+
+	text, err := ioutil.ReadFile(extras.cloudInitTemplate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := executeCloudInitTemplate(extras.cloudInitTemplate, string(text), data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var cloudInit map[string]interface{}
+
+	// The rendered template must be a valid YAML document
+	if err = yaml.Unmarshal([]byte(out), &cloudInit); err != nil {
+		return nil, wrapError(kindInvalidArgument, err, errCloudInitNotYAML, extras.cloudInitTemplate, err)
+	}
+
+	return normalizeCloudInit(cloudInit).(map[string]interface{}), nil
+}
+
+// cloudInitAppendKeys are the cloud-config lists appended when layers are merged, the other lists are replaced
+var cloudInitAppendKeys = map[string]bool{
+	"bootcmd":             true,
+	"groups":              true,
+	"mounts":              true,
+	"packages":            true,
+	cloudInitRunCmd:       true,
+	"ssh_authorized_keys": true,
+	"users":               true,
+	cloudInitWriteFiles:   true,
+}
+
+// normalizeCloudInit convert the maps decoded from YAML to string keyed maps
+func normalizeCloudInit(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		list := make([]interface{}, 0, len(v))
+
+		for _, item := range v {
+			list = append(list, normalizeCloudInit(item))
+		}
+
+		return list
+	case map[string]interface{}:
+		dict := make(map[string]interface{}, len(v))
+
+		for key, item := range v {
+			dict[key] = normalizeCloudInit(item)
+		}
+
+		return dict
+	case map[interface{}]interface{}:
+		dict := make(map[string]interface{}, len(v))
+
+		for key, item := range v {
+			dict[fmt.Sprint(key)] = normalizeCloudInit(item)
+		}
+
+		return dict
+	}
+
+	return value
+}
+
+// mergeCloudInit deep merge src into dst. Maps are merged, the append lists are appended,
+// scalars and other lists of src replace the ones of dst
+func mergeCloudInit(dst, src map[string]interface{}) error {
+	for key, value := range src {
+		value = normalizeCloudInit(value)
+		current, found := dst[key]
+
+		if !found || current == nil {
+			dst[key] = value
+			continue
+		}
+
+		if cloudInitAppendKeys[key] {
+			currentList, ok := current.([]interface{})
+			list, isList := value.([]interface{})
+
+			if !ok || !isList {
+				return newError(kindInvalidArgument, errCloudInitNotList, key)
+			}
+
+			merged := make([]interface{}, 0, len(currentList)+len(list))
+
+			dst[key] = append(append(merged, currentList...), list...)
+			continue
+		}
+
+		currentDict, ok := current.(map[string]interface{})
+		dict, isDict := value.(map[string]interface{})
+
+		if ok && isDict {
+			if err := mergeCloudInit(currentDict, dict); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		dst[key] = value
+	}
+
+	return nil
+}
+
+// composeCloudInit returns the rendered cloud-init layers merged in order: base, machine type then node group
+func (vm *MultipassNode) composeCloudInit(extras *nodeCreationExtra) (map[string]interface{}, error) {
+	cloudInit, err := vm.renderCloudInit(extras)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data := vm.cloudInitData(extras)
+
+	for _, fragment := range []map[string]interface{}{extras.machineCloudInit, extras.nodeGroupCloudInit} {
+		if len(fragment) == 0 {
+			continue
+		}
+
+		rendered, err := renderCloudInitValue(fragment, data)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if cloudInit == nil {
+			cloudInit = make(map[string]interface{})
+		}
+
+		if err = mergeCloudInit(cloudInit, rendered.(map[string]interface{})); err != nil {
+			return nil, err
+		}
+	}
+
+	return cloudInit, nil
+}
+
+// userScripts returns the rendered shell scripts parts of the user data
+func (vm *MultipassNode) userScripts(extras *nodeCreationExtra) ([]*CloudInitScript, error) {
+	if len(extras.cloudInitScripts) == 0 {
+		return nil, nil
+	}
+
+	data := vm.cloudInitData(extras)
+	scripts := make([]*CloudInitScript, 0, len(extras.cloudInitScripts))
+
+	for _, script := range extras.cloudInitScripts {
+		content, err := executeCloudInitTemplate(script.Name, script.Content, data)
+
+		if err != nil {
+			return nil, err
+		}
+
+		scripts = append(scripts, &CloudInitScript{
+			Name:    script.Name,
+			Content: content,
+		})
+	}
+
+	return scripts, nil
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func newTestCloudInitNode() *MultipassNode {
+	return &MultipassNode{
+		ProviderID: testProviderID,
+		NodeName:   testNodeName,
+		NodeIndex:  3,
+		Memory:     2048,
+		CPU:        2,
+		Host:       "host-1",
+	}
+}
+
+func newTestCloudInitExtras() *nodeCreationExtra {
+	extras := newFakeExtras(newFakeBackend())
+
+	extras.kubeHost = "192.168.1.10:6443"
+	extras.kubeToken = "abcdef.0123456789abcdef"
+	extras.kubeExtraArgs = []string{"--ignore-preflight-errors=All", "--v=2"}
+	extras.machineType = "standard"
+	extras.nodeLabels = map[string]string{"team": "infra"}
+	extras.cloudInitVariables = map[string]string{"proxy": "http://proxy:3128"}
+
+	return extras
+}
+
+func writeTestTemplate(t *testing.T, text string) string {
+	file, err := ioutil.TempFile("", "cloud-init-*.tmpl")
+
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	defer file.Close()
+
+	file.WriteString(text)
+
+	return file.Name()
+}
+
+func Test_multipassNode_renderCloudInit(t *testing.T) {
+	vm := newTestCloudInitNode()
+	extras := newTestCloudInitExtras()
+
+	extras.cloudInit = map[string]interface{}{
+		"hostname":       "{{ .NodeName }}",
+		"package_update": true,
+		"runcmd": []interface{}{
+			"echo {{ .NodeGroupID }}/{{ .NodeIndex }} {{ .MachineType }} {{ .Memory }}M > /etc/node",
+			[]interface{}{"kubeadm", "join", "{{ .KubeHost }}", "{{ join .KubeExtraArgs \" \" }}"},
+		},
+		"write_files": []interface{}{
+			map[string]interface{}{
+				"path":    "/etc/environment",
+				"content": "http_proxy={{ .Variables.proxy }}\nzone={{ index .Labels \"topology.kubernetes.io/zone\" }}\n",
+			},
+		},
+	}
+
+	cloudInit, err := vm.renderCloudInit(extras)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, testNodeName, cloudInit["hostname"])
+	assert.Equal(t, true, cloudInit["package_update"])
+	assert.Equal(t, []interface{}{
+		"echo " + testGroupID + "/3 standard 2048M > /etc/node",
+		[]interface{}{"kubeadm", "join", "192.168.1.10:6443", "--ignore-preflight-errors=All --v=2"},
+	}, cloudInit["runcmd"])
+	assert.Equal(t, "http_proxy=http://proxy:3128\nzone=host-1\n", cloudInit["write_files"].([]interface{})[0].(map[string]interface{})["content"])
+	assert.Equal(t, "{{ .NodeName }}", extras.cloudInit["hostname"], "the configuration is not modified")
+
+	// Unknown variables are errors
+	extras.cloudInit = map[string]interface{}{"hostname": "{{ .Variables.unknown }}"}
+
+	_, err = vm.renderCloudInit(extras)
+
+	assert.Error(t, err)
+
+	extras.cloudInit = nil
+
+	if cloudInit, err = vm.renderCloudInit(extras); assert.NoError(t, err) {
+		assert.Nil(t, cloudInit)
+	}
+}
+
+func Test_multipassNode_renderCloudInitTemplate(t *testing.T) {
+	vm := newTestCloudInitNode()
+	extras := newTestCloudInitExtras()
+
+	tests := []struct {
+		name     string
+		template string
+		want     map[string]interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "Rendered",
+			template: "#cloud-config\nhostname: {{ .NodeName }}\nruncmd:\n{{- range $k, $v := .Labels }}\n  - echo {{ $k }}={{ $v }}\n{{- end }}\n",
+			want: map[string]interface{}{
+				"hostname": testNodeName,
+				"runcmd": []interface{}{
+					"echo team=infra",
+					"echo topology.kubernetes.io/zone=host-1",
+				},
+			},
+		},
+		{
+			name:     "InvalidYAML",
+			template: "hostname: {{ .NodeName }}\n  runcmd: [\n",
+			wantErr:  true,
+		},
+		{
+			name:     "MissingVariable",
+			template: "hostname: {{ .Variables.hostname }}\n",
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			extras.cloudInitTemplate = writeTestTemplate(t, test.template)
+
+			defer os.Remove(extras.cloudInitTemplate)
+
+			cloudInit, err := vm.renderCloudInit(extras)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, test.want, cloudInit)
+			}
+		})
+	}
+}
+
+func Test_validateCloudInitTemplate(t *testing.T) {
+	assert.NoError(t, validateCloudInitTemplate(""))
+
+	fileName := writeTestTemplate(t, "hostname: {{ .NodeName }\n")
+
+	defer os.Remove(fileName)
+
+	assert.Equal(t, codes.InvalidArgument, kindOf(validateCloudInitTemplate(fileName)).code)
+	assert.Equal(t, codes.InvalidArgument, kindOf(validateCloudInitTemplate("/nonexistent/cloud-init.tmpl")).code)
+}
+
+func Test_mergeCloudInit(t *testing.T) {
+	tests := []struct {
+		name    string
+		dst     map[string]interface{}
+		src     map[string]interface{}
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "NewKeys",
+			dst:  map[string]interface{}{"hostname": "node-1"},
+			src:  map[string]interface{}{"timezone": "UTC"},
+			want: map[string]interface{}{"hostname": "node-1", "timezone": "UTC"},
+		},
+		{
+			name: "ScalarReplaced",
+			dst:  map[string]interface{}{"package_update": false, "timezone": "UTC"},
+			src:  map[string]interface{}{"package_update": true},
+			want: map[string]interface{}{"package_update": true, "timezone": "UTC"},
+		},
+		{
+			name: "AppendLists",
+			dst: map[string]interface{}{
+				"packages": []interface{}{"curl"},
+				"runcmd":   []interface{}{"echo base"},
+			},
+			src: map[string]interface{}{
+				"packages": []interface{}{"jq", "curl"},
+				"runcmd":   []interface{}{[]interface{}{"echo", "fragment"}},
+			},
+			want: map[string]interface{}{
+				"packages": []interface{}{"curl", "jq", "curl"},
+				"runcmd":   []interface{}{"echo base", []interface{}{"echo", "fragment"}},
+			},
+		},
+		{
+			name: "OtherListsReplaced",
+			dst:  map[string]interface{}{"ntp": map[string]interface{}{"servers": []interface{}{"a"}}},
+			src:  map[string]interface{}{"ntp": map[string]interface{}{"servers": []interface{}{"b"}}},
+			want: map[string]interface{}{"ntp": map[string]interface{}{"servers": []interface{}{"b"}}},
+		},
+		{
+			name: "DeepMerge",
+			dst: map[string]interface{}{
+				"apt": map[string]interface{}{
+					"preserve_sources_list": true,
+					"sources":               map[string]interface{}{"docker": map[string]interface{}{"source": "deb docker"}},
+				},
+			},
+			src: map[string]interface{}{
+				"apt": map[interface{}]interface{}{
+					"sources": map[interface{}]interface{}{"k8s": map[interface{}]interface{}{"source": "deb k8s"}},
+				},
+			},
+			want: map[string]interface{}{
+				"apt": map[string]interface{}{
+					"preserve_sources_list": true,
+					"sources": map[string]interface{}{
+						"docker": map[string]interface{}{"source": "deb docker"},
+						"k8s":    map[string]interface{}{"source": "deb k8s"},
+					},
+				},
+			},
+		},
+		{
+			name: "MapReplacedByScalar",
+			dst:  map[string]interface{}{"power_state": map[string]interface{}{"mode": "reboot"}},
+			src:  map[string]interface{}{"power_state": "none"},
+			want: map[string]interface{}{"power_state": "none"},
+		},
+		{
+			name:    "AppendNotList",
+			dst:     map[string]interface{}{"runcmd": "echo base"},
+			src:     map[string]interface{}{"runcmd": []interface{}{"echo fragment"}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := mergeCloudInit(test.dst, test.src)
+
+			if test.wantErr {
+				if assert.Error(t, err) {
+					assert.Equal(t, codes.InvalidArgument, kindOf(err).code)
+				}
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, test.want, test.dst)
+			}
+		})
+	}
+}
+
+func Test_multipassNode_composeCloudInit(t *testing.T) {
+	vm := newTestCloudInitNode()
+	extras := newTestCloudInitExtras()
+
+	extras.cloudInit = map[string]interface{}{
+		"packages": []interface{}{"curl"},
+		"timezone": "UTC",
+	}
+	extras.machineCloudInit = map[string]interface{}{
+		"packages": []interface{}{"nvidia-driver"},
+		"timezone": "Europe/Paris",
+	}
+	extras.nodeGroupCloudInit = map[string]interface{}{
+		"runcmd": []interface{}{"echo {{ .NodeGroupID }}"},
+	}
+
+	cloudInit, err := vm.composeCloudInit(extras)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]interface{}{
+			"packages": []interface{}{"curl", "nvidia-driver"},
+			"timezone": "Europe/Paris",
+			"runcmd":   []interface{}{"echo " + testGroupID},
+		}, cloudInit)
+
+		assert.Equal(t, []interface{}{"curl"}, extras.cloudInit["packages"], "the configuration is not modified")
+	}
+
+	// Fragments without base
+	extras.cloudInit = nil
+
+	if cloudInit, err = vm.composeCloudInit(extras); assert.NoError(t, err) {
+		assert.Equal(t, []interface{}{"nvidia-driver"}, cloudInit["packages"])
+	}
+}
+
+func Test_vmLaunchSpec_userData(t *testing.T) {
+	vm := newTestCloudInitNode()
+	extras := newTestCloudInitExtras()
+	spec := &VMLaunchSpec{
+		Name:      testNodeName,
+		CloudInit: map[string]interface{}{"package_update": true},
+	}
+
+	// Cloud-config only
+	if userData, err := spec.userData(); assert.NoError(t, err) {
+		assert.Equal(t, "#cloud-config\npackage_update: true\n", string(userData))
+	}
+
+	extras.cloudInitScripts = []*CloudInitScript{
+		{Name: "hostname.sh", Content: "#!/bin/sh\nhostname {{ .NodeName }}\n"},
+	}
+
+	scripts, err := vm.userScripts(extras)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	spec.Scripts = scripts
+
+	userData, err := spec.userData()
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	message, err := mail.ReadMessage(bytes.NewReader(userData))
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+
+	if assert.NoError(t, err) && assert.Equal(t, "multipart/mixed", mediaType) {
+		reader := multipart.NewReader(message.Body, params["boundary"])
+		parts := make(map[string]string)
+
+		for {
+			part, err := reader.NextPart()
+
+			if err != nil {
+				break
+			}
+
+			content, _ := ioutil.ReadAll(part)
+
+			parts[strings.Split(part.Header.Get("Content-Type"), ";")[0]] = string(content)
+		}
+
+		assert.Equal(t, map[string]string{
+			"text/cloud-config":  "#cloud-config\npackage_update: true\n",
+			"text/x-shellscript": "#!/bin/sh\nhostname " + testNodeName + "\n",
+		}, parts)
+	}
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+const (
+	// kubeadm before 1.24 use the master role, then the control-plane role
+	nodeLabelMaster       = "node-role.kubernetes.io/master"
+	nodeLabelControlPlane = "node-role.kubernetes.io/control-plane"
+
+	// kubeadm run etcd as the static pod etcd-<node> of kube-system, etcdctl is only in its image
+	etcdPodPrefix          = "etcd-"
+	etcdNamespace          = "kube-system"
+	etcdLocalEndpoint      = "https://127.0.0.1:2379"
+	etcdctlCommandLine     = "etcdctl"
+	endpointsArgument      = "--endpoints"
+	etcdctlTimeoutArgument = "--command-timeout=5s"
+	etcdctlOutputArgument  = "-w"
+
+	// kubeadm delete the uploaded certificates after 2 hours
+	certificateKeyTTL         = 2 * time.Hour
+	certificateKeyRenewBefore = 15 * time.Minute
+
+	controlPlaneArgument   string = "--control-plane"
+	certificateKeyArgument string = "--certificate-key"
+	namespaceArgument      string = "--namespace"
+)
+
+// etcdMemberList is the output of etcdctl member list -w json
+type etcdMemberList struct {
+	Members []struct {
+		ID         uint64   `json:"ID"`
+		Name       string   `json:"name"`
+		ClientURLs []string `json:"clientURLs"`
+	} `json:"members"`
+}
+
+// etcdctlCertificates are the kubeadm healthcheck client certificate, mounted in the etcd pod
+var etcdctlCertificates = []string{
+	"--cacert", "/etc/kubernetes/pki/etcd/ca.crt",
+	"--cert", "/etc/kubernetes/pki/etcd/healthcheck-client.crt",
+	"--key", "/etc/kubernetes/pki/etcd/healthcheck-client.key",
+}
+
+// etcdEndpointHealth is an item of etcdctl endpoint health -w json
+type etcdEndpointHealth struct {
+	Endpoint string `json:"endpoint"`
+	Health   bool   `json:"health"`
+}
+
+// ControlPlaneConfig declare the node groups joining the cluster as control plane members
+type ControlPlaneConfig struct {
+	NodeGroups []string `json:"node-groups"`    // Mandatory, node groups joining as control plane
+	Master     string   `json:"master"`         // Mandatory, VM of a control plane member, the certificates are uploaded from it
+	Host       string   `json:"host,omitempty"` // Optional, hypervisor host of the master VM
+}
+
+func (c *ControlPlaneConfig) validate(config *MultipassServerConfig) error {
+	if c == nil || len(c.NodeGroups) == 0 {
+		return nil
+	}
+
+	if len(c.Master) == 0 {
+		return newError(kindInvalidArgument, errControlPlaneMasterNotDefined)
+	}
+
+	for _, nodeGroupID := range c.NodeGroups {
+		if config.Bootstrap.bootstrapperName(nodeGroupID) != bootstrapperKubeadm {
+			return newError(kindInvalidArgument, errControlPlaneNotSupported, nodeGroupID, "only kubeadm join control plane")
+		}
+
+		// A stopped member is a failed etcd member
+		if config.WarmPool[nodeGroupID] > 0 || config.ScaleDown[nodeGroupID].hibernate() {
+			return newError(kindInvalidArgument, errControlPlaneNotSupported, nodeGroupID, "warm pool and hibernation stop members")
+		}
+	}
+
+	return nil
+}
+
+// controlPlane tells if the node group join as control plane
+func (c *ControlPlaneConfig) controlPlane(nodeGroupID string) bool {
+	if c == nil {
+		return false
+	}
+
+	for _, name := range c.NodeGroups {
+		if name == nodeGroupID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// generateCertificateKey returns a key like kubeadm certs certificate-key
+func generateCertificateKey() (string, error) {
+	key := make([]byte, 32)
+
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(key), nil
+}
+
+// certificateKeyRenewalDue tells if the certificates must be uploaded again
+func (s *MultipassServer) certificateKeyRenewalDue(now time.Time) bool {
+	if c := s.Configuration.ControlPlane; c == nil || len(c.NodeGroups) == 0 {
+		return false
+	}
+
+	return s.CertificateKeyExpiration == nil || now.Add(certificateKeyRenewBefore).After(*s.CertificateKeyExpiration)
+}
+
+// uploadCertificates encrypt and upload the control plane certificates from the master with a new certificate key
+func (s *MultipassServer) uploadCertificates(ctx context.Context) error {
+	s.tokenLock.Lock()
+	defer s.tokenLock.Unlock()
+
+	now := time.Now()
+
+	if !s.certificateKeyRenewalDue(now) {
+		return nil
+	}
+
+	glog.Infof("Upload the control plane certificates")
+
+	key, err := generateCertificateKey()
+
+	if err != nil {
+		return wrapError(kindInternal, err, errUploadCertificatesFailed, err)
+	}
+
+	master := s.controlPlaneMaster()
+
+	backend, err := master.backend(&nodeCreationExtra{
+		backend: s.vmBackend(),
+		hosts:   s.hypervisorHosts(),
+	})
+
+	if err != nil {
+		return wrapError(kindInternal, err, errUploadCertificatesFailed, err)
+	}
+
+	if _, err = backend.Exec(ctx, master.NodeName, sudoArgument, kubeadmArgument, "init", "phase", "upload-certs", "--upload-certs", certificateKeyArgument, key); err != nil {
+		return wrapError(kindKubernetes, err, errUploadCertificatesFailed, err)
+	}
+
+	expiration := now.Add(certificateKeyTTL)
+
+	s.CertificateKey = key
+	s.CertificateKeyExpiration = &expiration
+
+	return nil
+}
+
+// controlPlaneMaster returns the master VM declared in the control plane section
+func (s *MultipassServer) controlPlaneMaster() *MultipassNode {
+	if s.Configuration.ControlPlane == nil || len(s.Configuration.ControlPlane.Master) == 0 {
+		return nil
+	}
+
+	return &MultipassNode{
+		NodeName: s.Configuration.ControlPlane.Master,
+		Host:     s.Configuration.ControlPlane.Host,
+	}
+}
+
+// refreshJoinParameters renew the kubeadm token and the certificate key before they expire
+func (s *MultipassServer) refreshJoinParameters(ctx context.Context) {
+	if err := s.rotateKubeAdmToken(ctx); err != nil {
+		glog.Error(err.Error())
+	}
+
+	if err := s.uploadCertificates(ctx); err != nil {
+		glog.Error(err.Error())
+	}
+}
+
+// removeEtcdMember remove the stacked etcd member of the control plane VM
+func (vm *MultipassNode) removeEtcdMember(ctx context.Context, extras *nodeCreationExtra) error {
+	glog.Infof("Remove the etcd member of VM:%s", vm.NodeName)
+
+	backend, err := vm.backend(extras)
+
+	if err != nil {
+		return err
+	}
+
+	deleteCtx, cancel := context.WithTimeout(ctx, extras.timeouts.delete())
+
+	defer cancel()
+
+	if _, err = backend.Exec(deleteCtx, vm.NodeName, sudoArgument, kubeadmArgument, "reset", "phase", "remove-etcd-member"); err != nil {
+		return wrapError(kindKubernetes, err, errRemoveEtcdMemberFailed, vm.NodeName, err)
+	}
+
+	return nil
+}
+
+// quorumSafe tells if removing members keeps the etcd quorum. The removal needs the current quorum
+// and the remaining ready members must reach the quorum of the smaller cluster
+func quorumSafe(members, ready, removed, removedReady int) bool {
+	remaining := members - removed
+
+	if remaining < 1 {
+		return false
+	}
+
+	return ready >= members/2+1 && ready-removedReady >= remaining/2+1
+}
+
+// etcdctl returns the kubectl command line running etcdctl in the etcd pod of the VM, a control plane member
+func (vm *MultipassNode) etcdctl(extras *nodeCreationExtra, endpoint string, args ...string) []string {
+	command := []string{
+		kubectlCommandLine,
+		execArgument,
+		namespaceArgument,
+		etcdNamespace,
+		etcdPodPrefix + vm.NodeName,
+		kubeConfigArgument,
+		extras.kubeConfig,
+		dashDashArgument,
+		etcdctlCommandLine,
+		endpointsArgument,
+		endpoint,
+		etcdctlTimeoutArgument,
+	}
+
+	command = append(command, etcdctlCertificates...)
+
+	return append(command, args...)
+}
+
+// etcdEndpointHealthy tells if the etcd member serving the endpoint is healthy
+func (vm *MultipassNode) etcdEndpointHealthy(ctx context.Context, extras *nodeCreationExtra, endpoint string) bool {
+	var endpoints []etcdEndpointHealth
+
+	// etcdctl fails when the member is unhealthy, the probe is not retried
+	out, _, err := runCommand(ctx, vm.etcdctl(extras, endpoint, "endpoint", "health", etcdctlOutputArgument, jsonArgument)...)
+
+	if err != nil {
+		glog.Warningf("etcd endpoint: %s is unhealthy, reason: %v", endpoint, err)
+
+		return false
+	}
+
+	if err = json.Unmarshal([]byte(out), &endpoints); err != nil {
+		return false
+	}
+
+	for _, health := range endpoints {
+		if health.Health {
+			return true
+		}
+	}
+
+	return false
+}
+
+// etcdMembers returns the health of the etcd members, the VM is a control plane member
+func (vm *MultipassNode) etcdMembers(ctx context.Context, extras *nodeCreationExtra) (map[string]bool, error) {
+	var memberList etcdMemberList
+
+	infoCtx, cancel := context.WithTimeout(ctx, extras.timeouts.info())
+
+	defer cancel()
+
+	out, err := pipe(infoCtx, vm.etcdctl(extras, etcdLocalEndpoint, "member", "list", etcdctlOutputArgument, jsonArgument)...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal([]byte(out), &memberList); err != nil {
+		return nil, err
+	}
+
+	members := make(map[string]bool)
+
+	for _, member := range memberList.Members {
+		name := member.Name
+
+		// A member added but not started yet has no name
+		if len(name) == 0 {
+			name = strconv.FormatUint(member.ID, 16)
+		}
+
+		members[name] = false
+
+		for _, url := range member.ClientURLs {
+			if vm.etcdEndpointHealthy(infoCtx, extras, url) {
+				members[name] = true
+				break
+			}
+		}
+	}
+
+	return members, nil
+}
+
+// checkQuorum returns an error if removing the nodes of a control plane node group would break the etcd quorum
+func (s *MultipassServer) checkQuorum(ctx context.Context, nodeGroup *MultipassNodeGroup, nodes []*MultipassNode) error {
+	var ready, removed, removedReady int
+
+	if !nodeGroup.ControlPlane || len(nodes) == 0 {
+		return nil
+	}
+
+	master := s.controlPlaneMaster()
+
+	if master == nil {
+		return newError(kindFailedPrecondition, errUnableToCheckQuorum, nodeGroup.NodeGroupIdentifier, errControlPlaneMasterNotDefined)
+	}
+
+	// The stacked etcd members are named like their node
+	members, err := master.etcdMembers(ctx, s.newNodeCreationExtra(nodeGroup))
+
+	if err != nil {
+		return wrapError(kindKubernetes, err, errUnableToCheckQuorum, nodeGroup.NodeGroupIdentifier, err)
+	}
+
+	for _, isReady := range members {
+		if isReady {
+			ready++
+		}
+	}
+
+	for _, node := range nodes {
+		if isReady, found := members[node.NodeName]; found {
+			removed++
+
+			if isReady {
+				removedReady++
+			}
+		}
+	}
+
+	if !quorumSafe(len(members), ready, removed, removedReady) {
+		glog.Errorf(errEtcdQuorumWouldBreak, removed, nodeGroup.NodeGroupIdentifier, len(members), ready)
+
+		return newError(kindFailedPrecondition, errEtcdQuorumWouldBreak, removed, nodeGroup.NodeGroupIdentifier, len(members), ready)
+	}
+
+	return nil
+}
+
+// controlPlaneTaints are the taints kubeadm 1.24 set on control plane members, newer releases keep the control-plane one
+func controlPlaneTaints() []apiv1.Taint {
+	return []apiv1.Taint{
+		{
+			Key:    nodeLabelMaster,
+			Effect: apiv1.TaintEffectNoSchedule,
+		},
+		{
+			Key:    nodeLabelControlPlane,
+			Effect: apiv1.TaintEffectNoSchedule,
+		},
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func Test_controlPlaneConfig_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *ControlPlaneConfig
+		server  *MultipassServerConfig
+		wantErr bool
+	}{
+		{
+			name:   "Nil",
+			server: &MultipassServerConfig{},
+		},
+		{
+			name:   "Kubeadm",
+			config: &ControlPlaneConfig{NodeGroups: []string{testGroupID}, Master: "master-01"},
+			server: &MultipassServerConfig{},
+		},
+		{
+			name:    "Missing master",
+			config:  &ControlPlaneConfig{NodeGroups: []string{testGroupID}},
+			server:  &MultipassServerConfig{},
+			wantErr: true,
+		},
+		{
+			name:   "K3S",
+			config: &ControlPlaneConfig{NodeGroups: []string{testGroupID}, Master: "master-01"},
+			server: &MultipassServerConfig{
+				Bootstrap: &BootstrapConfig{Bootstrapper: bootstrapperK3S},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "Warm pool",
+			config: &ControlPlaneConfig{NodeGroups: []string{testGroupID}, Master: "master-01"},
+			server: &MultipassServerConfig{
+				WarmPool: map[string]int{testGroupID: 1},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "Hibernate",
+			config: &ControlPlaneConfig{NodeGroups: []string{testGroupID}, Master: "master-01"},
+			server: &MultipassServerConfig{
+				ScaleDown: map[string]*ScaleDownPolicy{testGroupID: {Mode: scaleDownHibernate}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validate(tt.server)
+
+			if tt.wantErr {
+				if assert.Error(t, err) {
+					assert.Equal(t, codes.InvalidArgument, kindOf(err).code)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_quorumSafe(t *testing.T) {
+	tests := []struct {
+		name         string
+		members      int
+		ready        int
+		removed      int
+		removedReady int
+		want         bool
+	}{
+		{name: "Three ready, remove one", members: 3, ready: 3, removed: 1, removedReady: 1, want: true},
+		{name: "Three ready, remove two", members: 3, ready: 3, removed: 2, removedReady: 2, want: true},
+		{name: "Remove all", members: 3, ready: 3, removed: 3, removedReady: 3, want: false},
+		{name: "One down, remove a ready one", members: 3, ready: 2, removed: 1, removedReady: 1, want: false},
+		{name: "One down, remove the down one", members: 3, ready: 2, removed: 1, removedReady: 0, want: true},
+		{name: "Quorum lost", members: 3, ready: 1, removed: 1, removedReady: 0, want: false},
+		{name: "Five ready, remove two", members: 5, ready: 5, removed: 2, removedReady: 2, want: true},
+		{name: "Five, two down, remove one ready", members: 5, ready: 3, removed: 1, removedReady: 1, want: false},
+		{name: "Nothing removed", members: 1, ready: 1, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, quorumSafe(tt.members, tt.ready, tt.removed, tt.removedReady))
+		})
+	}
+}
+
+func Test_multipassServer_certificateKeyRenewalDue(t *testing.T) {
+	now := time.Now()
+	expiration := now.Add(time.Hour)
+	s := &MultipassServer{}
+
+	assert.False(t, s.certificateKeyRenewalDue(now), "no control plane node group")
+
+	s.Configuration.ControlPlane = &ControlPlaneConfig{NodeGroups: []string{testGroupID}, Master: "master-01"}
+
+	assert.True(t, s.certificateKeyRenewalDue(now), "never uploaded")
+
+	s.CertificateKeyExpiration = &expiration
+
+	assert.False(t, s.certificateKeyRenewalDue(now))
+	assert.True(t, s.certificateKeyRenewalDue(now.Add(50*time.Minute)))
+
+	key, err := generateCertificateKey()
+
+	if assert.NoError(t, err) {
+		assert.Len(t, key, 64)
+	}
+}
+
+func Test_multipassNode_controlPlaneJoin(t *testing.T) {
+	vm := &MultipassNode{
+		ProviderID: testProviderID,
+		NodeName:   testNodeName,
+	}
+
+	extras := newTestBootstrapExtras(&BootstrapConfig{Mode: bootstrapCloudInit})
+
+	config := vm.joinConfiguration(extras)
+
+	assert.Nil(t, config["controlPlane"])
+	assert.Empty(t, config["nodeRegistration"].(map[string]interface{})["taints"])
+
+	extras.controlPlane = true
+	extras.certificateKey = "0123456789abcdef"
+
+	config = vm.joinConfiguration(extras)
+
+	assert.Equal(t, map[string]interface{}{"certificateKey": "0123456789abcdef"}, config["controlPlane"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"key": nodeLabelMaster, "value": "", "effect": "NoSchedule"},
+		map[string]interface{}{"key": nodeLabelControlPlane, "value": "", "effect": "NoSchedule"},
+	}, config["nodeRegistration"].(map[string]interface{})["taints"])
+}
+
+func Test_multipassNode_removeEtcdMember(t *testing.T) {
+	backend := newFakeBackend()
+	extras := newFakeExtras(backend)
+	vm := &MultipassNode{NodeName: testNodeName}
+
+	err := vm.removeEtcdMember(context.Background(), extras)
+
+	if assert.Error(t, err, "the VM doesn't exist") {
+		assert.Equal(t, []string{"exec"}, backend.calls)
+	}
+
+	backend.vms[testNodeName] = &VMStatus{State: MultipassNodeStateRunning}
+
+	assert.NoError(t, vm.removeEtcdMember(context.Background(), extras))
+}
+
+func Test_multipassNodeGroup_lastNodes(t *testing.T) {
+	ng := newTestNodeGroup("")
+
+	// The indexes 2 and 4 are taken by warm or hibernated VMs
+	for _, index := range []int{1, 3, 5, 10} {
+		ng.Nodes[ng.nodeName(index)] = &MultipassNode{NodeName: ng.nodeName(index), NodeIndex: index}
+	}
+
+	nodes := ng.lastNodes(-2)
+
+	if assert.Len(t, nodes, 2) {
+		assert.Equal(t, ng.nodeName(10), nodes[0].NodeName)
+		assert.Equal(t, ng.nodeName(5), nodes[1].NodeName)
+	}
+
+	assert.Len(t, ng.lastNodes(-6), 4)
+}
+
+// fakeEtcdKubectl is a kubectl exec in the etcd pod of a 3 members cluster, the third member is down
+const fakeEtcdKubectl = `#!/bin/sh
+echo "$@" >> "$(dirname "$0")/commands"
+case "$*" in
+*"member list"*)
+	echo '{"members":[
+		{"ID":1,"name":"master-01","clientURLs":["https://10.0.0.1:2379"]},
+		{"ID":2,"name":"master-02","clientURLs":["https://10.0.0.2:2379"]},
+		{"ID":3,"name":"master-03","clientURLs":["https://10.0.0.3:2379"]},
+		{"ID":10,"name":"","clientURLs":[]}]}'
+	;;
+*10.0.0.3*)
+	echo "context deadline exceeded" >&2
+	exit 1
+	;;
+*)
+	echo '[{"endpoint":"https://10.0.0.1:2379","health":true}]'
+	;;
+esac
+`
+
+func newFakeKubectl(t *testing.T, script string) string {
+	dir := t.TempDir()
+
+	if err := ioutil.WriteFile(filepath.Join(dir, kubectlCommandLine), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return dir
+}
+
+func Test_multipassNode_etcdMembers(t *testing.T) {
+	dir := newFakeKubectl(t, fakeEtcdKubectl)
+
+	// etcdctl is not installed on the master VM, only in the etcd pod
+	backend := newFakeBackend()
+	extras := newFakeExtras(backend)
+	master := &MultipassNode{NodeName: "master-01"}
+
+	members, err := master.etcdMembers(context.Background(), extras)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]bool{"master-01": true, "master-02": true, "master-03": false, "a": false}, members)
+		assert.Empty(t, backend.calls, "etcdctl is not run on the VM")
+	}
+
+	commands, _ := ioutil.ReadFile(filepath.Join(dir, "commands"))
+
+	assert.True(t, strings.HasPrefix(string(commands), "exec --namespace kube-system etcd-master-01 --kubeconfig "))
+	assert.Contains(t, string(commands), "-- etcdctl --endpoints https://127.0.0.1:2379 ")
+
+	// The etcd pod doesn't exist
+	newFakeKubectl(t, "#!/bin/sh\necho 'Error from server (NotFound): pods \"etcd-master-01\" not found' >&2\nexit 1\n")
+
+	_, err = master.etcdMembers(context.Background(), extras)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	apigrpc "github.com/Fred78290/kubernetes-multipass-autoscaler/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorKind is a failure mode, it gives the autoscaler error class and the grpc status code
+type errorKind struct {
+	class string
+	code  codes.Code
+}
+
+var (
+	// kindMismatchingProvider the client secret doesn't match
+	kindMismatchingProvider = &errorKind{internalError, codes.PermissionDenied}
+
+	// kindInvalidArgument the request is malformed
+	kindInvalidArgument = &errorKind{internalError, codes.InvalidArgument}
+
+	// kindNotFound the node group, node, VM or machine type doesn't exist
+	kindNotFound = &errorKind{cloudProviderError, codes.NotFound}
+
+	// kindAlreadyExists the node group or VM already exists
+	kindAlreadyExists = &errorKind{cloudProviderError, codes.AlreadyExists}
+
+	// kindFailedPrecondition the operation is refused by sizes or limits
+	kindFailedPrecondition = &errorKind{cloudProviderError, codes.FailedPrecondition}
+
+	// kindUnimplemented the RPC is disabled
+	kindUnimplemented = &errorKind{internalError, codes.Unimplemented}
+
+	// kindVM a multipass operation failed
+	kindVM = &errorKind{cloudProviderError, codes.Internal}
+
+	// kindKubernetes a kubectl or kubeadm operation failed
+	kindKubernetes = &errorKind{apiCallError, codes.Unavailable}
+
+	// kindNotReady the node joined but failed its readiness gates
+	kindNotReady = &errorKind{cloudProviderError, codes.Unavailable}
+
+	// kindTransient a command kept failing with a transient error, the next loop could succeed
+	kindTransient = &errorKind{transientError, codes.Unavailable}
+
+	// kindTimeout a command was killed because the deadline expired or the request was canceled
+	kindTimeout = &errorKind{transientError, codes.DeadlineExceeded}
+
+	// kindInternal a failure inside the cloud provider
+	kindInternal = &errorKind{internalError, codes.Internal}
+)
+
+// MultipassError is an error with its failure mode and the wrapped cause
+type MultipassError struct {
+	kind   *errorKind
+	reason string
+	cause  error
+}
+
+func (e *MultipassError) Error() string {
+	return e.reason
+}
+
+// Unwrap returns the cause
+func (e *MultipassError) Unwrap() error {
+	return e.cause
+}
+
+// newError returns an error of kind with the formatted reason
+func newError(kind *errorKind, format string, args ...interface{}) error {
+	return &MultipassError{
+		kind:   kind,
+		reason: fmt.Sprintf(format, args...),
+	}
+}
+
+// wrapError returns an error wrapping cause, the kind of a classified cause is kept
+func wrapError(kind *errorKind, cause error, format string, args ...interface{}) error {
+	if causeKind := kindOf(cause); causeKind != nil {
+		kind = causeKind
+	}
+
+	return &MultipassError{
+		kind:   kind,
+		reason: fmt.Sprintf(format, args...),
+		cause:  cause,
+	}
+}
+
+// kindOf returns the first failure mode found in the error chain, nil if not classified
+func kindOf(err error) *errorKind {
+	var e *MultipassError
+
+	for err != nil && errors.As(err, &e) {
+		if e.kind != nil {
+			return e.kind
+		}
+
+		err = e.cause
+	}
+
+	return nil
+}
+
+// errorClass returns the autoscaler error class, unclassified errors are cloud provider errors
+func errorClass(err error) string {
+	if kind := kindOf(err); kind != nil {
+		return kind.class
+	}
+
+	return cloudProviderError
+}
+
+// toAPIError convert the error to the autoscaler error reply
+func toAPIError(err error) *apigrpc.Error {
+	if err == nil {
+		return nil
+	}
+
+	return &apigrpc.Error{
+		Code:   errorClass(err),
+		Reason: err.Error(),
+	}
+}
+
+// toStatusError convert the error to a grpc status error
+func toStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if kind := kindOf(err); kind != nil {
+		return status.Error(kind.code, err.Error())
+	}
+
+	return status.Error(codes.Unknown, err.Error())
+}
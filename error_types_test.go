@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apigrpc "github.com/Fred78290/kubernetes-multipass-autoscaler/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestErrorServer() (*MultipassServer, context.Context, error) {
+	return newTestServerWithConfig(newTestNodeGroup("medium", withMaxNodeSize(2)), MultipassServerConfig{
+		ProviderID: testProviderID,
+		Machines:   testMachines,
+	})
+}
+
+func Test_errorClass(t *testing.T) {
+	cause := errors.New("exit status 2, instance is busy")
+	transient := wrapError(kindTransient, cause, errCommandRetryFailed, "multipass launch", 3, "")
+	launch := wrapError(kindVM, transient, errUnableToLaunchVM, testNodeName, transient)
+
+	assert.Equal(t, transientError, errorClass(launch))
+	assert.True(t, errors.Is(launch, cause))
+	assert.Equal(t, codes.Unavailable, status.Code(toStatusError(launch)))
+
+	launch = wrapError(kindVM, cause, errUnableToLaunchVM, testNodeName, cause)
+
+	assert.Equal(t, cloudProviderError, errorClass(launch))
+	assert.Equal(t, codes.Internal, status.Code(toStatusError(launch)))
+
+	assert.Equal(t, cloudProviderError, errorClass(cause))
+	assert.Equal(t, codes.Unknown, status.Code(toStatusError(cause)))
+
+	assert.Equal(t, transientError, errorClass(wrapError(nil, transient, errCommandRetryFailed, "multipass launch", 4, "")))
+	assert.Nil(t, toAPIError(nil))
+}
+
+func TestMultipassServer_ErrorStatusCodes(t *testing.T) {
+	s, ctx, err := newTestErrorServer()
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	wrongProvider := &apigrpc.NodeGroupServiceRequest{
+		ProviderID:  "wrong",
+		NodeGroupID: testGroupID,
+	}
+
+	unknownGroup := &apigrpc.NodeGroupServiceRequest{
+		ProviderID:  testProviderID,
+		NodeGroupID: "unknown",
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{
+			name: "NodeGroupsMismatchingProvider",
+			want: codes.PermissionDenied,
+			call: func() error {
+				_, err := s.NodeGroups(ctx, &apigrpc.CloudProviderServiceRequest{ProviderID: "wrong"})
+				return err
+			},
+		},
+		{
+			name: "IncreaseSizeMismatchingProvider",
+			want: codes.PermissionDenied,
+			call: func() error {
+				_, err := s.IncreaseSize(ctx, &apigrpc.IncreaseSizeRequest{ProviderID: "wrong", NodeGroupID: testGroupID, Delta: 1})
+				return err
+			},
+		},
+		{
+			name: "NodesMismatchingProvider",
+			want: codes.PermissionDenied,
+			call: func() error {
+				_, err := s.Nodes(ctx, wrongProvider)
+				return err
+			},
+		},
+		{
+			name: "IdNotFound",
+			want: codes.NotFound,
+			call: func() error {
+				_, err := s.Id(ctx, unknownGroup)
+				return err
+			},
+		},
+		{
+			name: "DebugNotFound",
+			want: codes.NotFound,
+			call: func() error {
+				_, err := s.Debug(ctx, unknownGroup)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, status.Code(tt.call()))
+		})
+	}
+}
+
+func TestMultipassServer_ErrorClasses(t *testing.T) {
+	s, ctx, err := newTestErrorServer()
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		call func() (*apigrpc.Error, error)
+		want string
+	}{
+		{
+			name: "TargetSizeNodeGroupNotFound",
+			want: cloudProviderError,
+			call: func() (*apigrpc.Error, error) {
+				r, err := s.TargetSize(ctx, &apigrpc.NodeGroupServiceRequest{ProviderID: testProviderID, NodeGroupID: "unknown"})
+				return r.GetError(), err
+			},
+		},
+		{
+			name: "IncreaseSizeMustBePositive",
+			want: internalError,
+			call: func() (*apigrpc.Error, error) {
+				r, err := s.IncreaseSize(ctx, &apigrpc.IncreaseSizeRequest{ProviderID: testProviderID, NodeGroupID: testGroupID, Delta: 0})
+				return r.GetError(), err
+			},
+		},
+		{
+			name: "IncreaseSizeTooLarge",
+			want: cloudProviderError,
+			call: func() (*apigrpc.Error, error) {
+				r, err := s.IncreaseSize(ctx, &apigrpc.IncreaseSizeRequest{ProviderID: testProviderID, NodeGroupID: testGroupID, Delta: 10})
+				return r.GetError(), err
+			},
+		},
+		{
+			name: "DecreaseTargetSizeMustBeNegative",
+			want: internalError,
+			call: func() (*apigrpc.Error, error) {
+				r, err := s.DecreaseTargetSize(ctx, &apigrpc.DecreaseTargetSizeRequest{ProviderID: testProviderID, NodeGroupID: testGroupID, Delta: 1})
+				return r.GetError(), err
+			},
+		},
+		{
+			name: "DeleteNodesMinSizeReached",
+			want: cloudProviderError,
+			call: func() (*apigrpc.Error, error) {
+				r, err := s.DeleteNodes(ctx, &apigrpc.DeleteNodesRequest{ProviderID: testProviderID, NodeGroupID: testGroupID, Node: []string{"{"}})
+				return r.GetError(), err
+			},
+		},
+		{
+			name: "NodeGroupForNodeWrongNode",
+			want: internalError,
+			call: func() (*apigrpc.Error, error) {
+				r, err := s.NodeGroupForNode(ctx, &apigrpc.NodeGroupForNodeRequest{ProviderID: testProviderID, Node: "{"})
+				return r.GetError(), err
+			},
+		},
+		{
+			name: "NewNodeGroupMachineTypeNotFound",
+			want: cloudProviderError,
+			call: func() (*apigrpc.Error, error) {
+				r, err := s.NewNodeGroup(ctx, &apigrpc.NewNodeGroupRequest{ProviderID: testProviderID, MachineType: "unknown"})
+				return r.GetError(), err
+			},
+		},
+		{
+			name: "NodePriceWrongInterval",
+			want: internalError,
+			call: func() (*apigrpc.Error, error) {
+				r, err := s.NodePrice(ctx, &apigrpc.NodePriceRequest{ProviderID: testProviderID, Node: "{}", StartTime: now.Unix(), EndTime: now.Add(-time.Hour).Unix()})
+				return r.GetError(), err
+			},
+		},
+		{
+			name: "PodPriceWrongPod",
+			want: internalError,
+			call: func() (*apigrpc.Error, error) {
+				r, err := s.PodPrice(ctx, &apigrpc.PodPriceRequest{ProviderID: testProviderID, Pod: "{"})
+				return r.GetError(), err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.call()
+
+			if assert.NoError(t, err) && assert.NotNil(t, got) {
+				assert.Equal(t, tt.want, got.GetCode(), got.GetReason())
+			}
+		})
+	}
+}
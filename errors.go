@@ -1,65 +1,135 @@
 package main
 
+import "github.com/Fred78290/kubernetes-multipass-autoscaler/constantes"
+
 const (
 	// cloudProviderError is an error related to underlying infrastructure
-	cloudProviderError = "cloudProviderError"
+	cloudProviderError = constantes.CloudProviderError
 	// apiCallError is an error related to communication with k8s API server
-	apiCallError = "apiCallError"
+	apiCallError = constantes.APICallError
 	// internalError is an error inside Cluster Autoscaler
-	internalError = "internalError"
+	internalError = constantes.InternalError
 	// transientError is an error that causes us to skip a single loop, but
 	// does not require any additional action.
-	transientError = "transientError"
+	transientError = constantes.TransientError
 )
 
 const (
-	providerName                      = "grpc"
-	errMismatchingProvider            = "Secret doesn't match with target server"
-	errNodeGroupNotFound              = "Node group %s not found"
-	errNodeGroupForNodeNotFound       = "NodeGroup %s not found for Node %s"
-	errNodeNotFoundInNodeGroup        = "The node %s not found in node group %s"
-	errMachineTypeNotFound            = "Machine type %s not found"
-	errNodeGroupAlreadyExists         = "Can't create node group: %s, already exists"
-	errUnableToCreateNodeGroup        = "Can't create node group: %s, reason: %v"
-	errUnableToDeleteNodeGroup        = "Can't delete node group: %s, reason: %v"
-	errCantDecodeNodeIDWithReason     = "Node providerID %s not conform, reason: %v"
-	errCantDecodeNodeID               = "Node providerID %s not conform"
-	errCantUnmarshallNodeWithReason   = "Can't unmarshall node definition:%s, reason: %v"
-	errCantUnmarshallNode             = "Can't unmarshall node definition[%d] in group %s"
-	errUnableToDeleteNode             = "Can't delete node: %s, because not owned by node group: %s"
-	errMinSizeReached                 = "Min size reached for group: %s, nodes will not be deleted"
-	errIncreaseSizeMustBePositive     = "Size increase must be positive"
-	errIncreaseSizeTooLarge           = "Size increase too large, desired: %d max: %d"
-	errDecreaseSizeMustBeNegative     = "Size decrease must be negative"
-	errDecreaseSizeAttemptDeleteNodes = "Attempt to delete existing nodes, targetSize: %d delta: %d existingNodes: %d"
-	errUnableToLaunchVM               = "Unable to launch the VM owned by node: %s, reason: %v"
-	errUnableToDeleteVM               = "Unable to delete the VM owned by node: %s, reason: %v"
+	providerName                      = constantes.ProviderName
+	errMismatchingProvider            = constantes.ErrMismatchingProvider
+	errNodeGroupNotFound              = constantes.ErrNodeGroupNotFound
+	errNodeGroupForNodeNotFound       = constantes.ErrNodeGroupForNodeNotFound
+	errNodeNotFoundInNodeGroup        = constantes.ErrNodeNotFoundInNodeGroup
+	errMachineTypeNotFound            = constantes.ErrMachineTypeNotFound
+	errNodeGroupAlreadyExists         = constantes.ErrNodeGroupAlreadyExists
+	errUnableToCreateNodeGroup        = constantes.ErrUnableToCreateNodeGroup
+	errUnableToDeleteNodeGroup        = constantes.ErrUnableToDeleteNodeGroup
+	errCantDecodeNodeIDWithReason     = constantes.ErrCantDecodeNodeIDWithReason
+	errCantDecodeNodeID               = constantes.ErrCantDecodeNodeID
+	errCantUnmarshallNodeWithReason   = constantes.ErrCantUnmarshallNodeWithReason
+	errCantUnmarshallNode             = constantes.ErrCantUnmarshallNode
+	errUnableToDeleteNode             = constantes.ErrUnableToDeleteNode
+	errMinSizeReached                 = constantes.ErrMinSizeReached
+	errIncreaseSizeMustBePositive     = constantes.ErrIncreaseSizeMustBePositive
+	errIncreaseSizeTooLarge           = constantes.ErrIncreaseSizeTooLarge
+	errDecreaseSizeMustBeNegative     = constantes.ErrDecreaseSizeMustBeNegative
+	errDecreaseSizeAttemptDeleteNodes = constantes.ErrDecreaseSizeAttemptDeleteNodes
+	errUnableToLaunchVM               = constantes.ErrUnableToLaunchVM
+	errUnableToDeleteVM               = constantes.ErrUnableToDeleteVM
 	errWrongSchemeInProviderID        = "Wrong scheme in providerID %s. expect multipass, got: %s"
-	errWrongPathInProviderID          = "Wrong path in providerID: %s. expect object, got: %s"
-	errVMAlreadyCreated               = "Unable to launch VM, %s is already created"
-	errUnableToMountPath              = "Unable to mount host path:%s into guest:%s for node:%s, reason: %v"
-	errTempFile                       = "Can't create temp file, reason: %v"
-	errCloudInitMarshallError         = "Can't marshall cloud-init, reason: %v"
-	errCloudInitWriteError            = "Can't write cloud-init, reason: %v"
+	errWrongPathInProviderID          = constantes.ErrWrongPathInProviderID
+	errVMAlreadyCreated               = constantes.ErrVMAlreadyCreated
+	errUnableToMountPath              = constantes.ErrUnableToMountPath
+	errTempFile                       = constantes.ErrTempFile
+	errCloudInitMarshallError         = constantes.ErrCloudInitMarshallError
+	errCloudInitWriteError            = constantes.ErrCloudInitWriteError
 	errGetVMInfoFailed                = "Can't get the VM info from multipass for VM: %s, reason: %v"
 	errMultiPassInfoNotFound          = "Can't find the VM info from multipass for VM: %s"
-	errKubeAdmJoinFailed              = "Unable to join the master kubernetes node for VM: %s, reason: %v"
-	errKubeAdmJoinNotRunning          = "Could not join kubernetes master node, the VM: %s is not running"
-	errStopVMFailed                   = "Could not stop VM: %s, reason: %v"
-	errStartVMFailed                  = "Could not start VM: %s, reason: %v"
-	errDeleteVMFailed                 = "Could not delete VM: %s, reason: %v"
-	errVMNotFound                     = "Unable to find VM: %s"
-	errVMStopFailed                   = "Unable to stop VM: %s before delete"
-	errNodeGroupCleanupFailOnVM       = "On node group: %s, failed to delete VM: %s, reason: %v"
-	errKubeCtlIgnoredError            = "kubectl got error on VM: %s, reason: %s"
-	errNotImplemented                 = "Not implemented"
-	errNodeIsNotReady                 = "Node %s is not ready"
-	errUnableToAutoProvisionNodeGroup = "Warning can't autoprovision node group, reason: %v"
-	errUnmarshallingError             = "Unable to unmarshall node: %s as json, reason: %v"
-	errMarshallingError               = "Unable to marshall node: %s as json, reason: %v"
-	errKubeletNotConfigured           = "Can't set provider ID in kubelet for VM: %s, %s, reason: %v"
-	errVMNotProvisionnedByMe          = "The VM: %s is not provisionned by me"
-	errFailedToLoadServerState        = "Failed to load server state, reason: %v"
-	errFailedToSaveServerState        = "Failed to save server state, reason: %v"
+	errKubeAdmJoinFailed              = constantes.ErrKubeAdmJoinFailed
+	errKubeAdmJoinNotRunning          = constantes.ErrKubeAdmJoinNotRunning
+	errStopVMFailed                   = constantes.ErrStopVMFailed
+	errStartVMFailed                  = constantes.ErrStartVMFailed
+	errDeleteVMFailed                 = constantes.ErrDeleteVMFailed
+	errVMNotFound                     = constantes.ErrVMNotFound
+	errVMStopFailed                   = constantes.ErrVMStopFailed
+	errNodeGroupCleanupFailOnVM       = constantes.ErrNodeGroupCleanupFailOnVM
+	errKubeCtlIgnoredError            = constantes.ErrKubeCtlIgnoredError
+	errNotImplemented                 = constantes.ErrNotImplemented
+	errNodeIsNotReady                 = constantes.ErrNodeIsNotReady
+	errUnableToAutoProvisionNodeGroup = constantes.ErrUnableToAutoProvisionNodeGroup
+	errUnmarshallingError             = constantes.ErrUnmarshallingError
+	errMarshallingError               = constantes.ErrMarshallingError
+	errKubeletNotConfigured           = constantes.ErrKubeletNotConfigured
+	errVMNotProvisionnedByMe          = constantes.ErrVMNotProvisionnedByMe
+	errFailedToLoadServerState        = constantes.ErrFailedToLoadServerState
+	errFailedToSaveServerState        = constantes.ErrFailedToSaveServerState
 	errVMStateUndefined               = "VM state %s is not defined:%s"
+	errCantUnmarshallPodWithReason    = "Can't unmarshall pod definition:%s, reason: %v"
+	errPricingWrongInterval           = "Wrong pricing interval, start time: %d is after end time: %d"
+	errCapabilityDisabled             = "Capability %s is disabled"
+	errResourceLimitExceeded          = "Scale up of node group: %s refused, total %s: %d would exceed the max limit: %d"
+	errResourceLimitBelowMin          = "Scale down of node group: %s refused, total %s: %d would go below the min limit: %d"
+	errUnableToReadHostCapacity       = "Unable to read host capacity, reason: %v"
+	errMemAvailableNotFound           = "MemAvailable not found in: %s"
+	errOutOfHostResources             = "Not enough host resources to launch %d VM of type: %s for node group: %s, available %s"
+	errCommandCanceled                = "Command: %s canceled, reason: %v"
+	errCommandRetryFailed             = "Command: %s failed after %d attempts: %s"
+	errCommandFailed                  = "%v, %s"
+	errUnknownBackend                 = "Unknown VM backend: %s"
+	errLXDRequestFailed               = "LXD request: %s %s failed, reason: %v"
+	errLXDOperationFailed             = "LXD operation: %s failed, reason: %s"
+	errLXDExecFailed                  = "Command: %s in instance: %s exited with code: %d, %s"
+	errLibvirtBaseVolumeNotDefined    = "libvirt base volume is not defined"
+	errLibvirtSeedFailed              = "Unable to build cloud-init seed for VM: %s, reason: %v"
+	errLibvirtDomainFailed            = "Unable to define or start domain: %s, reason: %v"
+	errVMNoAddress                    = "Unable to find the address of VM: %s, reason: %v"
+	errMountUnsupported               = "Unable to mount: %s in VM: %s, mount is not supported by %s backend"
+	errUnableToEncodeGuestInfo        = constantes.ErrUnableToEncodeGuestInfo
+	errUnableToAddHardDrive           = constantes.ErrUnableToAddHardDrive
+	errUnableToAddNetworkCard         = constantes.ErrUnableToAddNetworkCard
+	errUnableToCreateDeviceChangeOp   = constantes.ErrUnableToCreateDeviceChangeOp
+	errCloudInitFailCreation          = constantes.ErrCloudInitFailCreation
+	errUnableToReconfigureVM          = constantes.ErrUnableToReconfigureVM
+	errVSphereConnectFailed           = "Unable to connect to vSphere: %s, reason: %v"
+	errVSphereOperationFailed         = "vSphere operation on: %s failed, reason: %v"
+	errVSphereTemplateNotDefined      = "vSphere template is not defined"
+	errMultipassdConnectFailed        = "Unable to connect to multipassd: %s, reason: %v"
+	errMultipassdCallFailed           = "multipassd call for VM: %s failed, reason: %v"
+	errHostNameNotDefined             = "Hypervisor host name is not defined"
+	errHostAlreadyDeclared            = "Hypervisor host: %s is declared twice"
+	errHostAddressNotDefined          = "Hypervisor host: %s has no address"
+	errUnknownHostType                = "Unknown hypervisor host type: %s for host: %s"
+	errHostNotDeclared                = "Hypervisor host: %s of VM: %s is not declared"
+	errUnknownPlacementStrategy       = "Unknown placement strategy: %s"
+	errPlacementHostNotDeclared       = "Hypervisor host: %s in placement of node group: %s is not declared"
+	errNoHostAvailable                = "No hypervisor host available for a VM of %dM"
+	errUnableToPlaceNodes             = "Unable to place %d VM for node group: %s, reason: %v"
+	errUnableToStartStoppedVM         = "Unable to start stopped VM: %s for node group: %s, reason: %v"
+	errUnableToFillWarmPool           = "Unable to fill the warm pool of node group: %s, reason: %v"
+	errUnknownScaleDownMode           = "Unknown scale down mode: %s for node group: %s"
+	errUnableToHibernateVM            = "Unable to hibernate VM: %s, it will be deleted, reason: %v"
+	errUnknownBootstrapMode           = "Unknown bootstrap mode: %s"
+	errInvalidNodeTaint               = "Invalid taint key: %s, effect: %s for node group: %s"
+	errNodeNotJoined                  = "The kubernetes node:%s didn't join the cluster"
+	errTokenRotationFailed            = "Unable to rotate the kubeadm token, reason: %v"
+	errClusterCANotFound              = "Can't find the cluster CA in kubeconfig: %s"
+	errInvalidClusterCA               = "Invalid cluster CA in kubeconfig: %s, reason: %v"
+	errUnknownBootstrapper            = "Unknown bootstrapper: %s"
+	errBootstrapperNotConfigured      = "The server url and token of the %s bootstrapper are not defined"
+	errAgentJoinFailed                = "Unable to join the %s agent: %s, reason: %v"
+	errCloudInitTemplate              = "Unable to parse the cloud-init template: %s, reason: %v"
+	errControlPlaneMasterNotDefined   = "The control plane master VM is not defined"
+	errControlPlaneNotSupported       = "The node group: %s can't join as control plane, reason: %s"
+	errUploadCertificatesFailed       = "Unable to upload the control plane certificates, reason: %v"
+	errRemoveEtcdMemberFailed         = "Unable to remove the etcd member: %s, reason: %v"
+	errUnableToCheckQuorum            = "Unable to check the etcd quorum of node group: %s, reason: %v"
+	errEtcdQuorumWouldBreak           = "Removing %d members of node group: %s would break the etcd quorum, members: %d, ready: %d"
+	errCloudInitNotYAML               = "The rendered cloud-init template: %s is not a valid YAML, reason: %v"
+	errCloudInitNotList               = "The cloud-init key: %s is not a list"
+	errInvalidKubeletConfig           = "Invalid kubelet configuration of node group: %s, reason: %v"
+	errKubeletConfigFailed            = "Unable to configure the kubelet of VM: %s, reason: %v"
+	errNoAddressInNodeIP              = "No VM address in the node-ip CIDR: %s"
+	errInvalidReadinessGate           = "Invalid readiness gate: %s of node group: %s, reason: %s"
+	errReadinessGateFailed            = "The kubernetes node:%s didn't pass the readiness gate: %s"
+	errLibvirtCommandFailed           = "virsh %s failed, reason: %v"
 )
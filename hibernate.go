@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const (
+	scaleDownDelete    = "delete"
+	scaleDownHibernate = "hibernate"
+)
+
+// ScaleDownPolicy declare what happens to the VMs removed from a node group
+type ScaleDownPolicy struct {
+	Mode          string `json:"mode"`           // Optional, delete or hibernate, delete by default
+	MaxHibernated int    `json:"max-hibernated"` // Optional, number of hibernated VMs kept, unlimited when not set
+	MaxAge        int    `json:"max-age"`        // Optional, seconds a VM stays hibernated before being deleted, unlimited when not set
+}
+
+func (p *ScaleDownPolicy) validate(nodeGroupID string) error {
+	if p == nil {
+		return nil
+	}
+
+	switch p.Mode {
+	case "", scaleDownDelete, scaleDownHibernate:
+		return nil
+	}
+
+	return newError(kindInvalidArgument, errUnknownScaleDownMode, p.Mode, nodeGroupID)
+}
+
+// hibernate tells if the removed VMs are stopped instead of deleted
+func (p *ScaleDownPolicy) hibernate() bool {
+	return p != nil && p.Mode == scaleDownHibernate
+}
+
+func (p *ScaleDownPolicy) maxAge() time.Duration {
+	if p == nil {
+		return 0
+	}
+
+	return time.Duration(p.MaxAge) * time.Second
+}
+
+func (p *ScaleDownPolicy) maxHibernated() int {
+	if p == nil {
+		return 0
+	}
+
+	return p.MaxHibernated
+}
+
+// hibernateVM drain, cordon and stop the VM
+func (vm *MultipassNode) hibernateVM(ctx context.Context, extras *nodeCreationExtra) error {
+	glog.V(5).Infof("multipassNode::hibernateVM, node:%s", vm.NodeName)
+
+	drainCtx, cancel := context.WithTimeout(ctx, extras.timeouts.drain())
+
+	defer cancel()
+
+	vm.drain(drainCtx, extras)
+
+	if err := vm.stopVM(ctx, extras); err != nil {
+		return err
+	}
+
+	hibernatedAt := time.Now()
+
+	vm.HibernatedAt = &hibernatedAt
+
+	return nil
+}
+
+// removeNode hibernate or delete a node removed from the group, it's deleted if it can't be hibernated
+func (g *MultipassNodeGroup) removeNode(ctx context.Context, extras *nodeCreationExtra, node *MultipassNode) error {
+	if g.ScaleDown.hibernate() {
+		err := node.hibernateVM(ctx, extras)
+
+		if err == nil {
+			glog.Infof("Hibernate VM:%s for nodegroup: %s", node.NodeName, g.NodeGroupIdentifier)
+
+			g.standbyLock.Lock()
+
+			if g.HibernatedNodes == nil {
+				g.HibernatedNodes = make(map[string]*MultipassNode)
+			}
+
+			g.HibernatedNodes[node.NodeName] = node
+
+			g.standbyLock.Unlock()
+
+			g.pruneHibernated(ctx, extras)
+
+			return nil
+		}
+
+		glog.Errorf(errUnableToHibernateVM, node.NodeName, err)
+	}
+
+	return node.deleteVM(ctx, extras)
+}
+
+// pruneHibernated delete the hibernated VMs too old or beyond the max count, oldest first
+func (g *MultipassNodeGroup) pruneHibernated(ctx context.Context, extras *nodeCreationExtra) {
+	maxAge := g.ScaleDown.maxAge()
+	excess := 0
+
+	g.standbyLock.RLock()
+
+	nodes := make([]*MultipassNode, 0, len(g.HibernatedNodes))
+
+	for _, node := range g.HibernatedNodes {
+		nodes = append(nodes, node)
+	}
+
+	g.standbyLock.RUnlock()
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].HibernatedAt.Before(*nodes[j].HibernatedAt)
+	})
+
+	if maxHibernated := g.ScaleDown.maxHibernated(); maxHibernated > 0 {
+		excess = len(nodes) - maxHibernated
+	}
+
+	for index, node := range nodes {
+		if index >= excess && (maxAge == 0 || time.Since(*node.HibernatedAt) < maxAge) {
+			continue
+		}
+
+		// Refresh and DeleteNodes prune concurrently, the first one deletes the VM
+		g.standbyLock.Lock()
+
+		pruned := g.HibernatedNodes[node.NodeName] == node
+
+		if pruned {
+			delete(g.HibernatedNodes, node.NodeName)
+		}
+
+		g.standbyLock.Unlock()
+
+		if !pruned {
+			continue
+		}
+
+		glog.Infof("Delete hibernated VM:%s for nodegroup: %s", node.NodeName, g.NodeGroupIdentifier)
+
+		if err := node.deleteVM(ctx, extras); err != nil {
+			glog.Errorf(errUnableToDeleteVM, node.NodeName, err)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func newTestHibernateGroup(policy *ScaleDownPolicy) *MultipassNodeGroup {
+	ng := newTestWarmPoolGroup(0)
+
+	ng.ScaleDown = policy
+	ng.HibernatedNodes = make(map[string]*MultipassNode)
+
+	return ng
+}
+
+func Test_scaleDownPolicy_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *ScaleDownPolicy
+		wantErr bool
+	}{
+		{
+			name: "Nil",
+		},
+		{
+			name:   "Default",
+			policy: &ScaleDownPolicy{},
+		},
+		{
+			name:   "Delete",
+			policy: &ScaleDownPolicy{Mode: scaleDownDelete},
+		},
+		{
+			name:   "Hibernate",
+			policy: &ScaleDownPolicy{Mode: scaleDownHibernate, MaxHibernated: 2, MaxAge: 3600},
+		},
+		{
+			name:    "Unknown",
+			policy:  &ScaleDownPolicy{Mode: "suspend"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.policy.validate(testGroupID)
+
+			if test.wantErr {
+				assert.Equal(t, codes.InvalidArgument, kindOf(err).code)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_nodeGroup_hibernate(t *testing.T) {
+	ctx := context.Background()
+	backend := newFakeBackend()
+	extras := newFakeExtras(backend)
+	ng := newTestHibernateGroup(&ScaleDownPolicy{Mode: scaleDownHibernate, MaxHibernated: 2})
+
+	if !assert.NoError(t, ng.setNodeGroupSize(ctx, 3, extras)) {
+		return
+	}
+
+	names := make([]string, 0, 3)
+
+	for index := 1; index <= 3; index++ {
+		names = append(names, ng.nodeName(index))
+	}
+
+	// Scale down stop the VMs, the oldest beyond the max count is deleted
+	for _, nodeName := range names {
+		if assert.NoError(t, ng.deleteNodeByName(ctx, extras, nodeName)) {
+			assert.True(t, ng.standbyNode(nodeName))
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.Equal(t, 0, ng.targetSize(), "hibernated VMs are excluded from target size")
+	assert.Len(t, ng.Nodes, 0)
+	assert.Len(t, ng.HibernatedNodes, 2)
+	assert.NotContains(t, backend.vms, names[0])
+
+	for _, nodeName := range names[1:] {
+		if assert.Contains(t, ng.HibernatedNodes, nodeName) {
+			assert.NotNil(t, ng.HibernatedNodes[nodeName].HibernatedAt)
+			assert.Equal(t, MultipassNodeStateStopped, backend.vms[nodeName].State)
+		}
+	}
+
+	// Scale up resume the hibernated VMs before launching new ones
+	if assert.NoError(t, ng.setNodeGroupSize(ctx, 3, extras)) {
+		assert.Equal(t, 3, ng.targetSize())
+		assert.Len(t, ng.HibernatedNodes, 0)
+		assert.Equal(t, 4, backend.launched())
+
+		for _, nodeName := range names[1:] {
+			if assert.Contains(t, ng.Nodes, nodeName) {
+				assert.Nil(t, ng.Nodes[nodeName].HibernatedAt)
+				assert.Equal(t, MultipassNodeStateRunning, backend.vms[nodeName].State)
+			}
+		}
+	}
+}
+
+func Test_nodeGroup_hibernateMaxAge(t *testing.T) {
+	ctx := context.Background()
+	backend := newFakeBackend()
+	extras := newFakeExtras(backend)
+	ng := newTestHibernateGroup(&ScaleDownPolicy{Mode: scaleDownHibernate, MaxAge: 60})
+
+	if !assert.NoError(t, ng.setNodeGroupSize(ctx, 2, extras)) {
+		return
+	}
+
+	for nodeName := range ng.Nodes {
+		assert.NoError(t, ng.deleteNodeByName(ctx, extras, nodeName))
+	}
+
+	if !assert.Len(t, ng.HibernatedNodes, 2) {
+		return
+	}
+
+	var expired string
+
+	for nodeName, node := range ng.HibernatedNodes {
+		hibernatedAt := time.Now().Add(-time.Hour)
+
+		node.HibernatedAt = &hibernatedAt
+		expired = nodeName
+
+		break
+	}
+
+	ng.pruneHibernated(ctx, extras)
+
+	assert.Len(t, ng.HibernatedNodes, 1)
+	assert.NotContains(t, ng.HibernatedNodes, expired)
+	assert.NotContains(t, backend.vms, expired)
+}
+
+func Test_nodeGroup_hibernateConcurrentPrune(t *testing.T) {
+	ctx := context.Background()
+	backend := newFakeBackend()
+	extras := newFakeExtras(backend)
+	ng := newTestHibernateGroup(&ScaleDownPolicy{Mode: scaleDownHibernate, MaxAge: 60})
+
+	if !assert.NoError(t, ng.setNodeGroupSize(ctx, 4, extras)) {
+		return
+	}
+
+	for nodeName := range ng.Nodes {
+		assert.NoError(t, ng.deleteNodeByName(ctx, extras, nodeName))
+	}
+
+	hibernatedAt := time.Now().Add(-time.Hour)
+
+	for _, node := range ng.HibernatedNodes {
+		node.HibernatedAt = &hibernatedAt
+	}
+
+	// Refresh and DeleteNodes prune at the same time
+	var wg sync.WaitGroup
+
+	for index := 0; index < 4; index++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			ng.pruneHibernated(ctx, extras)
+			ng.standbyNodes()
+		}()
+	}
+
+	wg.Wait()
+
+	deleted := 0
+
+	for _, call := range backend.calls {
+		if call == "delete" {
+			deleted++
+		}
+	}
+
+	assert.Len(t, ng.HibernatedNodes, 0)
+	assert.Len(t, backend.vms, 0)
+	assert.Equal(t, 4, deleted, "each VM is deleted once")
+}
+
+func Test_nodeGroup_scaleDownDelete(t *testing.T) {
+	ctx := context.Background()
+	backend := newFakeBackend()
+	extras := newFakeExtras(backend)
+	ng := newTestHibernateGroup(nil)
+
+	if !assert.NoError(t, ng.setNodeGroupSize(ctx, 1, extras)) {
+		return
+	}
+
+	for nodeName := range ng.Nodes {
+		if assert.NoError(t, ng.deleteNodeByName(ctx, extras, nodeName)) {
+			assert.NotContains(t, backend.vms, nodeName)
+		}
+	}
+
+	assert.Len(t, ng.HibernatedNodes, 0)
+}
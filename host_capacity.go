@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+
+	apigrpc "github.com/Fred78290/kubernetes-multipass-autoscaler/grpc"
+	"github.com/golang/glog"
+)
+
+const (
+	errorCodeOutOfHostResources = "OutOfHostResources"
+	linuxMultipassStoragePath   = "/var/snap/multipass/common/data/multipassd"
+	darwinMultipassStoragePath  = "/var/root/Library/Application Support/multipassd"
+)
+
+// procMemInfoPath is the file used to read the host available memory
+var procMemInfoPath = "/proc/meminfo"
+
+// HostReservation declare the resources reserved for the host, they are never allocated to VMs
+type HostReservation struct {
+	Memory             int     `json:"memsize"`              // Reserved memory in megabytes
+	Vcpu               int     `json:"vcpus"`                // Reserved cpus
+	Disk               int     `json:"disksize"`             // Reserved disk in megabytes
+	CPUOvercommitRatio float64 `json:"cpu-overcommit-ratio"` // Optional, number of vcpus allowed per host cpu, default 1
+}
+
+// HostCapacity describe the resources still available on the hypervisor host
+type HostCapacity struct {
+	Memory int `json:"memsize"`  // Free memory in megabytes
+	Vcpu   int `json:"vcpus"`    // Cpus not allocated to VMs
+	Disk   int `json:"disksize"` // Free disk in megabytes
+}
+
+func (c *HostCapacity) String() string {
+	return fmt.Sprintf("memory: %dM, vcpus: %d, disk: %dM", c.Memory, c.Vcpu, c.Disk)
+}
+
+// fit tells if count machines could be launched on the host
+func (c *HostCapacity) fit(machine *MachineCharacteristic, count int) bool {
+	return c.Memory >= machine.Memory*count && c.Vcpu >= machine.Vcpu*count && c.Disk >= machine.Disk*count
+}
+
+// defaultStoragePath returns the multipass storage path for the host OS
+func defaultStoragePath() string {
+	if runtime.GOOS == "darwin" {
+		return darwinMultipassStoragePath
+	}
+
+	return linuxMultipassStoragePath
+}
+
+// readAvailableMemory returns the host available memory in megabytes
+func readAvailableMemory(fileName string) (int, error) {
+	file, err := os.Open(fileName)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		if len(fields) >= 2 && fields[0] == "MemAvailable:" {
+			kb, err := strconv.Atoi(fields[1])
+
+			if err != nil {
+				return 0, err
+			}
+
+			return kb / 1024, nil
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, newError(kindInternal, errMemAvailableNotFound, fileName)
+}
+
+// readAvailableDisk returns the free disk in megabytes for the file system holding path
+func readAvailableDisk(path string) (int, error) {
+	var stat syscall.Statfs_t
+
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+
+	return int(uint64(stat.Bavail) * uint64(stat.Bsize) / bytesPerMegabyte), nil
+}
+
+// hostCapacity returns the resources still available on the local host for new VMs, VMs of remote hosts are ignored
+func (s *MultipassServer) hostCapacity() (*HostCapacity, error) {
+	var err error
+	var allocatedCPU, pendingMemory, pendingDisk int
+
+	reservation := s.Configuration.HostReservation
+
+	if reservation == nil {
+		reservation = &HostReservation{}
+	}
+
+	storagePath := s.Configuration.StoragePath
+
+	if len(storagePath) == 0 {
+		storagePath = defaultStoragePath()
+	}
+
+	overcommit := reservation.CPUOvercommitRatio
+
+	if overcommit <= 0 {
+		overcommit = 1
+	}
+
+	capacity := &HostCapacity{}
+
+	if capacity.Memory, err = readAvailableMemory(procMemInfoPath); err != nil {
+		return nil, wrapError(kindInternal, err, errUnableToReadHostCapacity, err)
+	}
+
+	if capacity.Disk, err = readAvailableDisk(storagePath); err != nil {
+		return nil, wrapError(kindInternal, err, errUnableToReadHostCapacity, err)
+	}
+
+	for _, nodeGroup := range s.Groups {
+		nodes, pendingNodes := nodeGroup.allocatedNodes()
+
+		for _, node := range nodes {
+			if s.localHost(node.Host) && node.AutoProvisionned && !node.failed() && node.State != MultipassNodeStateDeleted {
+				allocatedCPU += node.CPU
+			}
+		}
+
+		// Stopped warm and hibernated VMs keep their cpus and disk
+		for _, node := range nodeGroup.standbyNodes() {
+			if s.localHost(node.Host) {
+				allocatedCPU += node.CPU
+			}
+		}
+
+		// Pending VMs don't consume yet memory and disk
+		for _, node := range pendingNodes {
+			if s.localHost(node.Host) {
+				allocatedCPU += node.CPU
+				pendingMemory += node.Memory
+				pendingDisk += node.Disk
+			}
+		}
+	}
+
+	capacity.Vcpu = int(float64(runtime.NumCPU()-reservation.Vcpu)*overcommit) - allocatedCPU
+	capacity.Memory -= reservation.Memory + pendingMemory
+	capacity.Disk -= reservation.Disk + pendingDisk
+
+	return capacity, nil
+}
+
+// localBackend tells if the backend runs the VMs on the local machine, vSphere, a remote libvirt URI
+// or multipassd on a TCP address don't
+func (c *MultipassServerConfig) localBackend() bool {
+	switch c.Backend {
+	case "", backendMultipass, backendLXD:
+		// LXD is reached through its local unix socket
+		return true
+	case backendMultipassd:
+		return c.Multipassd == nil || len(c.Multipassd.Address) == 0 || strings.HasPrefix(c.Multipassd.Address, "unix:")
+	case backendLibvirt:
+		return c.Libvirt == nil || !c.Libvirt.remote()
+	}
+
+	return false
+}
+
+// checkHostCapacity returns an error info if delta nodes of the node group machine don't fit on the local host.
+// With remote hosts or a remote backend, the VMs could be launched anywhere and checkPlacement is the only check
+func (s *MultipassServer) checkHostCapacity(nodeGroup *MultipassNodeGroup, delta int) *MultipassNodeErrorInfo {
+	if nodeGroup.Machine == nil || s.remoteHosts() || !s.Configuration.localBackend() {
+		return nil
+	}
+
+	capacity, err := s.hostCapacity()
+
+	// Don't block scale up if the capacity is not readable, ie: not a linux host
+	if err != nil {
+		glog.Warningf(err.Error())
+		return nil
+	}
+
+	if capacity.fit(nodeGroup.Machine, delta) {
+		return nil
+	}
+
+	message := fmt.Sprintf(errOutOfHostResources, delta, nodeGroup.MachineType, nodeGroup.NodeGroupIdentifier, capacity)
+
+	glog.Errorf(message)
+
+	return &MultipassNodeErrorInfo{
+		Class:   apigrpc.InstanceErrorClass_ERROR_OUT_OF_RESOURCES,
+		Code:    errorCodeOutOfHostResources,
+		Message: message,
+	}
+}
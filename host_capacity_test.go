@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apigrpc "github.com/Fred78290/kubernetes-multipass-autoscaler/grpc"
+)
+
+const testMemInfo = `MemTotal:       16314240 kB
+MemFree:         1024000 kB
+MemAvailable:    8388608 kB
+Buffers:          204800 kB
+`
+
+func newTestHostCapacityServer(t *testing.T, reservation *HostReservation) *MultipassServer {
+	dir, err := ioutil.TempDir("", "host-capacity")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	memInfo := filepath.Join(dir, "meminfo")
+
+	if err = ioutil.WriteFile(memInfo, []byte(testMemInfo), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	savedMemInfoPath := procMemInfoPath
+	procMemInfoPath = memInfo
+
+	t.Cleanup(func() {
+		procMemInfoPath = savedMemInfoPath
+	})
+
+	s, _, err := newTestServerWithConfig(newTestNodeGroup("large"), MultipassServerConfig{
+		ProviderID:      testProviderID,
+		Machines:        testMachines,
+		StoragePath:     dir,
+		HostReservation: reservation,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.ResourceLimiter = nil
+
+	return s
+}
+
+func Test_readAvailableMemory(t *testing.T) {
+	s := newTestHostCapacityServer(t, nil)
+
+	memory, err := readAvailableMemory(procMemInfoPath)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, 8192, memory)
+	}
+
+	_, err = readAvailableMemory(s.Configuration.StoragePath)
+	assert.Error(t, err)
+}
+
+func TestMultipassServer_hostCapacity(t *testing.T) {
+	s := newTestHostCapacityServer(t, &HostReservation{
+		Memory: 1024,
+	})
+
+	s.Groups[testGroupID].PendingNodes["pending"] = &MultipassNode{
+		NodeName: "pending",
+		Memory:   2048,
+		CPU:      1,
+		Disk:     0,
+	}
+
+	// A VM booting on a remote host don't consume the local memory
+	s.Groups[testGroupID].PendingNodes["remote"] = &MultipassNode{
+		NodeName: "remote",
+		Memory:   4096,
+		CPU:      2,
+		Host:     "remote",
+	}
+
+	s.Configuration.Hosts = []*HypervisorHost{{Name: "remote", Type: hostSSH, Address: "remote"}}
+
+	capacity, err := s.hostCapacity()
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, 8192-1024-2048, capacity.Memory)
+		assert.True(t, capacity.Disk > 0)
+	}
+}
+
+func TestMultipassServer_hostCapacityConcurrentScaleUp(t *testing.T) {
+	s := newTestHostCapacityServer(t, nil)
+	ng := s.Groups[testGroupID]
+	done := make(chan struct{})
+
+	// A node group growing in background is read under its lock
+	go func() {
+		defer close(done)
+
+		for index := 0; index < 50; index++ {
+			ng.Lock()
+			ng.PendingNodes[ng.nodeName(index)] = &MultipassNode{NodeName: ng.nodeName(index), CPU: 1}
+			ng.Unlock()
+		}
+	}()
+
+	for index := 0; index < 50; index++ {
+		s.hostCapacity()
+	}
+
+	<-done
+
+	capacity, err := s.hostCapacity()
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, runtime.NumCPU()-50, capacity.Vcpu)
+	}
+}
+
+func TestMultipassServer_IncreaseSizeOutOfHostResources(t *testing.T) {
+	s := newTestHostCapacityServer(t, &HostReservation{
+		Memory: 8192,
+	})
+
+	reply, err := s.IncreaseSize(context.Background(), &apigrpc.IncreaseSizeRequest{
+		ProviderID:  testProviderID,
+		NodeGroupID: testGroupID,
+		Delta:       2,
+	})
+
+	if assert.NoError(t, err) && assert.Nil(t, reply.GetError()) {
+		nodes, err := s.Nodes(context.Background(), &apigrpc.NodeGroupServiceRequest{
+			ProviderID:  testProviderID,
+			NodeGroupID: testGroupID,
+		})
+
+		if assert.NoError(t, err) {
+			instances := nodes.GetInstances().GetItems()
+
+			if assert.Len(t, instances, 2) {
+				for _, instance := range instances {
+					assert.Equal(t, apigrpc.InstanceState_STATE_BEING_CREATED, instance.GetStatus().GetState())
+					assert.Equal(t, apigrpc.InstanceErrorClass_ERROR_OUT_OF_RESOURCES, instance.GetStatus().GetErrorInfo().GetErrorClass())
+					assert.Equal(t, errorCodeOutOfHostResources, instance.GetStatus().GetErrorInfo().GetErrorCode())
+				}
+			}
+		}
+
+		// Failed nodes don't consume cluster resources
+		assert.Equal(t, int64(0), s.usedResources()[ResourceNameCores])
+	}
+}
+
+func TestMultipassServer_checkHostCapacityRemoteHosts(t *testing.T) {
+	s := newTestHostCapacityServer(t, &HostReservation{
+		Memory: 8192,
+	})
+
+	nodeGroup := s.Groups[testGroupID]
+
+	assert.NotNil(t, s.checkHostCapacity(nodeGroup, 1), "local host is full")
+
+	s.Configuration.Hosts = []*HypervisorHost{
+		{Name: "local"},
+		{Name: "remote", Type: hostMultipassd, Address: "remote:50051"},
+	}
+
+	assert.True(t, s.localHost("local"))
+	assert.False(t, s.localHost("remote"))
+	assert.Nil(t, s.checkHostCapacity(nodeGroup, 1), "the VM could be placed on the remote host")
+}
+
+func TestMultipassServer_checkHostCapacityRemoteBackend(t *testing.T) {
+	tests := []struct {
+		name   string
+		config MultipassServerConfig
+		local  bool
+	}{
+		{name: "Multipass", config: MultipassServerConfig{}, local: true},
+		{name: "LXD", config: MultipassServerConfig{Backend: backendLXD}, local: true},
+		{name: "vSphere", config: MultipassServerConfig{Backend: backendVSphere, VSphere: &VSphereConfig{URL: "https://vcenter/sdk"}}},
+		{name: "Libvirt", config: MultipassServerConfig{Backend: backendLibvirt}, local: true},
+		{name: "LibvirtSession", config: MultipassServerConfig{Backend: backendLibvirt, Libvirt: &LibvirtConfig{URI: "qemu:///session"}}, local: true},
+		{name: "LibvirtUnix", config: MultipassServerConfig{Backend: backendLibvirt, Libvirt: &LibvirtConfig{URI: "qemu+unix:///system"}}, local: true},
+		{name: "LibvirtSSH", config: MultipassServerConfig{Backend: backendLibvirt, Libvirt: &LibvirtConfig{URI: "qemu+ssh://admin@host/system"}}},
+		{name: "LibvirtTLS", config: MultipassServerConfig{Backend: backendLibvirt, Libvirt: &LibvirtConfig{URI: "qemu://host/system"}}},
+		{name: "MultipassdSocket", config: MultipassServerConfig{Backend: backendMultipassd, Multipassd: &MultipassdConfig{Address: defaultMultipassdAddress}}, local: true},
+		{name: "MultipassdTCP", config: MultipassServerConfig{Backend: backendMultipassd, Multipassd: &MultipassdConfig{Address: "remote:50051"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.local, tt.config.localBackend())
+		})
+	}
+
+	// The autoscaler machine is full but the VMs run on vSphere
+	s := newTestHostCapacityServer(t, &HostReservation{
+		Memory: 8192,
+	})
+
+	nodeGroup := s.Groups[testGroupID]
+
+	assert.NotNil(t, s.checkHostCapacity(nodeGroup, 1), "local host is full")
+
+	s.Configuration.Backend = backendVSphere
+	s.Configuration.VSphere = &VSphereConfig{URL: "https://vcenter/sdk"}
+
+	assert.Nil(t, s.checkHostCapacity(nodeGroup, 1), "the VMs don't run on the local host")
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"github.com/golang/glog"
+)
+
+const (
+	hostLocal      = "local"
+	hostMultipassd = "multipassd"
+	hostSSH        = "ssh"
+)
+
+// HypervisorHost declare a machine running VMs for the node groups
+type HypervisorHost struct {
+	Name       string `json:"name"`        // Mandatory, host name recorded in the nodes it runs
+	Type       string `json:"type"`        // Optional, local, multipassd or ssh, local by default
+	Address    string `json:"address"`     // Mandatory for remote hosts, multipassd address or ssh host
+	ClientCert string `json:"client-cert"` // Optional, multipassd client certificate
+	ClientKey  string `json:"client-key"`  // Optional, multipassd client certificate key
+	SSHUser    string `json:"ssh-user"`    // Optional, ssh user running multipass on the host
+	SSHKey     string `json:"ssh-key"`     // Optional, ssh private key
+	KnownHosts string `json:"known-hosts"` // Optional, known_hosts file with the ssh host key, trusted on first use when not set
+	Memory     int    `json:"memsize"`     // Optional, memory in megabytes allocatable to VMs, unlimited when not set
+}
+
+// hypervisorHosts route the VM operations to the host running the VM
+type hypervisorHosts struct {
+	names    []string
+	backends map[string]VMBackend
+}
+
+// newHypervisorHosts returns the hosts declared in config, local hosts use the server backend
+func newHypervisorHosts(config *MultipassServerConfig, local VMBackend, cacheDir string) (*hypervisorHosts, error) {
+	hosts := &hypervisorHosts{
+		names:    make([]string, 0, len(config.Hosts)),
+		backends: make(map[string]VMBackend),
+	}
+
+	for _, host := range config.Hosts {
+		var backend VMBackend
+
+		if len(host.Name) == 0 {
+			return nil, newError(kindInvalidArgument, errHostNameNotDefined)
+		}
+
+		if hosts.backends[host.Name] != nil {
+			return nil, newError(kindInvalidArgument, errHostAlreadyDeclared, host.Name)
+		}
+
+		switch host.Type {
+		case "", hostLocal:
+			backend = local
+		case hostMultipassd:
+			backend = newMultipassdBackend(&MultipassdConfig{
+				Address:    host.Address,
+				ClientCert: host.ClientCert,
+				ClientKey:  host.ClientKey,
+			}, cacheDir)
+		case hostSSH:
+			if len(host.Address) == 0 {
+				return nil, newError(kindInvalidArgument, errHostAddressNotDefined, host.Name)
+			}
+
+			backend = newRemoteMultipassBackend(cacheDir, host.Address, newHostSSHRemote(host, cacheDir))
+		default:
+			return nil, newError(kindInvalidArgument, errUnknownHostType, host.Type, host.Name)
+		}
+
+		hosts.names = append(hosts.names, host.Name)
+		hosts.backends[host.Name] = backend
+	}
+
+	if err := config.Placement.validate(config.Hosts); err != nil {
+		return nil, err
+	}
+
+	return hosts, nil
+}
+
+// declared tells if the host is known
+func (h *hypervisorHosts) declared(host string) bool {
+	return h != nil && h.backends[host] != nil
+}
+
+// backend returns the backend of the host, nodes without host run with the server backend
+func (h *hypervisorHosts) backend(host string, local VMBackend) VMBackend {
+	if len(host) == 0 || h == nil {
+		return local
+	}
+
+	return h.backends[host]
+}
+
+// localHost tells if the VMs of the host run on the autoscaler machine, nodes without host are local
+func (s *MultipassServer) localHost(host string) bool {
+	if len(host) == 0 {
+		return true
+	}
+
+	for _, declared := range s.Configuration.Hosts {
+		if declared.Name == host {
+			return len(declared.Type) == 0 || declared.Type == hostLocal
+		}
+	}
+
+	return false
+}
+
+// remoteHosts tells if a declared host runs VMs on another machine
+func (s *MultipassServer) remoteHosts() bool {
+	for _, declared := range s.Configuration.Hosts {
+		if !s.localHost(declared.Name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hypervisorHosts returns the server hosts, empty if the hosts are not valid
+func (s *MultipassServer) hypervisorHosts() *hypervisorHosts {
+	if s.Hosts == nil {
+		hosts, err := newHypervisorHosts(&s.Configuration, s.vmBackend(), s.CacheDir)
+
+		if err != nil {
+			glog.Errorf("%v, hosts are ignored", err)
+
+			hosts = &hypervisorHosts{}
+		}
+
+		s.Hosts = hosts
+	}
+
+	return s.Hosts
+}
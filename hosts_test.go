@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func Test_newHypervisorHosts(t *testing.T) {
+	local := newFakeBackend()
+
+	tests := []struct {
+		name    string
+		hosts   []*HypervisorHost
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "None",
+			want: []string{},
+		},
+		{
+			name: "Mixed",
+			hosts: []*HypervisorHost{
+				{Name: "host-1"},
+				{Name: "host-2", Type: hostMultipassd, Address: "10.0.0.2:50051"},
+				{Name: "host-3", Type: hostSSH, Address: "10.0.0.3"},
+			},
+			want: []string{"host-1", "host-2", "host-3"},
+		},
+		{
+			name:    "Unnamed",
+			hosts:   []*HypervisorHost{{Type: hostLocal}},
+			wantErr: true,
+		},
+		{
+			name:    "Duplicate",
+			hosts:   []*HypervisorHost{{Name: "host-1"}, {Name: "host-1"}},
+			wantErr: true,
+		},
+		{
+			name:    "SSHWithoutAddress",
+			hosts:   []*HypervisorHost{{Name: "host-1", Type: hostSSH}},
+			wantErr: true,
+		},
+		{
+			name:    "UnknownType",
+			hosts:   []*HypervisorHost{{Name: "host-1", Type: "hyperv"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newHypervisorHosts(&MultipassServerConfig{Hosts: tt.hosts}, local, ".")
+
+			if tt.wantErr {
+				assert.Equal(t, codes.InvalidArgument, kindOf(err).code)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got.names)
+			}
+		})
+	}
+}
+
+func Test_hypervisorHosts_backend(t *testing.T) {
+	local := newFakeBackend()
+	hosts, _ := newHypervisorHosts(&MultipassServerConfig{
+		Hosts: []*HypervisorHost{
+			{Name: "host-1"},
+			{Name: "host-2", Type: hostSSH, Address: "10.0.0.2", SSHUser: "admin"},
+		},
+	}, local, ".")
+
+	assert.Equal(t, local, hosts.backend("", local))
+	assert.Equal(t, local, hosts.backend("host-1", local))
+	assert.Nil(t, hosts.backend("host-3", local))
+
+	if remote, ok := hosts.backend("host-2", local).(*multipassBackend); assert.True(t, ok) {
+		assert.Equal(t, []string{sshCommandLine, "admin@10.0.0.2", multipassCommandLine, listArgument},
+			removeSSHOptions(remote.command(multipassCommandLine, listArgument)))
+	}
+
+	var none *hypervisorHosts
+
+	assert.False(t, none.declared("host-1"))
+	assert.True(t, hosts.declared("host-2"))
+	assert.Equal(t, local, none.backend("host-1", local))
+}
+
+// removeSSHOptions drop the -o, -i, -p options from the ssh command line
+func removeSSHOptions(command []string) []string {
+	result := []string{command[0]}
+
+	for index := 1; index < len(command); index++ {
+		switch command[index] {
+		case "-o", "-i", "-p":
+			index++
+		default:
+			result = append(result, command[index])
+		}
+	}
+
+	return result
+}
+
+func Test_nodeGroup_spanHosts(t *testing.T) {
+	first := newFakeBackend()
+	second := newFakeBackend()
+	extras := newFakeExtras(newFakeBackend())
+
+	extras.hosts = &hypervisorHosts{
+		names: []string{"first", "second"},
+		backends: map[string]VMBackend{
+			"first":  first,
+			"second": second,
+		},
+	}
+
+	extras.placement = &hostPlacement{
+		strategy:    placementSpread,
+		candidates:  []*HypervisorHost{{Name: "first"}, {Name: "second"}},
+		allocations: make(map[string]*hostAllocation),
+	}
+
+	ng := newTestNodeGroup("", withMachine(&MachineCharacteristic{Memory: 1024, Vcpu: 1, Disk: 5120}))
+
+	if !assert.NoError(t, ng.addNodes(context.Background(), 3, extras)) {
+		return
+	}
+
+	assert.Equal(t, "first", ng.Nodes[ng.nodeName(1)].Host)
+	assert.Equal(t, "second", ng.Nodes[ng.nodeName(2)].Host)
+	assert.Equal(t, "first", ng.Nodes[ng.nodeName(3)].Host)
+	assert.Len(t, first.vms, 2)
+	assert.Len(t, second.vms, 1)
+
+	if assert.NoError(t, ng.Nodes[ng.nodeName(2)].deleteVM(context.Background(), extras)) {
+		assert.Len(t, first.vms, 2)
+		assert.Len(t, second.vms, 0)
+	}
+
+	vm := &MultipassNode{
+		NodeName:         testNodeName,
+		Host:             "third",
+		AutoProvisionned: true,
+	}
+
+	_, err := vm.statusVM(context.Background(), extras)
+
+	assert.Equal(t, codes.FailedPrecondition, kindOf(err).code)
+}
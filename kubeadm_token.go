@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/glog"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	defaultTokenTTL         = 24 * time.Hour
+	defaultTokenRenewBefore = time.Hour
+
+	bootstrapTokenChars      = "abcdefghijklmnopqrstuvwxyz0123456789"
+	bootstrapTokenSecretType = "bootstrap.kubernetes.io/token"
+	bootstrapTokenGroups     = "system:bootstrappers:kubeadm:default-node-token"
+
+	applyArgument string = "apply"
+	fileArgument  string = "-f"
+)
+
+// TokenRotation declare how the provider create and renew the kubeadm bootstrap token
+type TokenRotation struct {
+	TTL         int `json:"ttl"`          // Optional, token lifetime in seconds, 24 hours by default
+	RenewBefore int `json:"renew-before"` // Optional, seconds before the expiration when the token is renewed, 1 hour by default
+}
+
+func (r *TokenRotation) ttl() time.Duration {
+	if r.TTL <= 0 {
+		return defaultTokenTTL
+	}
+
+	return time.Duration(r.TTL) * time.Second
+}
+
+func (r *TokenRotation) renewBefore() time.Duration {
+	if r.RenewBefore <= 0 {
+		return defaultTokenRenewBefore
+	}
+
+	return time.Duration(r.RenewBefore) * time.Second
+}
+
+// kubeConfigFile is the part of the kubeconfig needed to find the cluster CA
+type kubeConfigFile struct {
+	CurrentContext string `yaml:"current-context"`
+	Contexts       []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Clusters []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+}
+
+// clusterCA returns the PEM CA of the current context cluster
+func (k *kubeConfigFile) clusterCA(dir string) ([]byte, error) {
+	var cluster string
+
+	for _, context := range k.Contexts {
+		if context.Name == k.CurrentContext {
+			cluster = context.Context.Cluster
+		}
+	}
+
+	for _, c := range k.Clusters {
+		if c.Name != cluster && len(cluster) > 0 {
+			continue
+		}
+
+		if len(c.Cluster.CertificateAuthorityData) > 0 {
+			return base64.StdEncoding.DecodeString(c.Cluster.CertificateAuthorityData)
+		}
+
+		if fileName := c.Cluster.CertificateAuthority; len(fileName) > 0 {
+			if !filepath.IsAbs(fileName) {
+				fileName = filepath.Join(dir, fileName)
+			}
+
+			return ioutil.ReadFile(fileName)
+		}
+
+		break
+	}
+
+	return nil, nil
+}
+
+// discoveryTokenCACertHash returns the sha256 hash of the cluster CA public key, as expected by kubeadm join
+func discoveryTokenCACertHash(kubeConfig string) (string, error) {
+	var config kubeConfigFile
+
+	content, err := ioutil.ReadFile(kubeConfig)
+
+	if err != nil {
+		return "", wrapError(kindInternal, err, errInvalidClusterCA, kubeConfig, err)
+	}
+
+	if err = yaml.Unmarshal(content, &config); err != nil {
+		return "", wrapError(kindInternal, err, errInvalidClusterCA, kubeConfig, err)
+	}
+
+	ca, err := config.clusterCA(filepath.Dir(kubeConfig))
+
+	if err != nil {
+		return "", wrapError(kindInternal, err, errInvalidClusterCA, kubeConfig, err)
+	}
+
+	block, _ := pem.Decode(ca)
+
+	if block == nil {
+		return "", newError(kindInternal, errClusterCANotFound, kubeConfig)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+
+	if err != nil {
+		return "", wrapError(kindInternal, err, errInvalidClusterCA, kubeConfig, err)
+	}
+
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(cert.RawSubjectPublicKeyInfo)), nil
+}
+
+func randomString(length int) (string, error) {
+	max := big.NewInt(int64(len(bootstrapTokenChars)))
+	b := make([]byte, length)
+
+	for index := range b {
+		n, err := rand.Int(rand.Reader, max)
+
+		if err != nil {
+			return "", err
+		}
+
+		b[index] = bootstrapTokenChars[n.Int64()]
+	}
+
+	return string(b), nil
+}
+
+// generateBootstrapToken returns a new token id and secret
+func generateBootstrapToken() (string, string, error) {
+	tokenID, err := randomString(6)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	tokenSecret, err := randomString(16)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return tokenID, tokenSecret, nil
+}
+
+// bootstrapTokenSecret returns the kube-system secret declaring the bootstrap token
+func bootstrapTokenSecret(tokenID, tokenSecret string, expiration time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"type":       bootstrapTokenSecretType,
+		"metadata": map[string]interface{}{
+			"name":      "bootstrap-token-" + tokenID,
+			"namespace": "kube-system",
+		},
+		"stringData": map[string]string{
+			"description":                    "Bootstrap token created by the multipass autoscaler",
+			"token-id":                       tokenID,
+			"token-secret":                   tokenSecret,
+			"expiration":                     expiration.UTC().Format(time.RFC3339),
+			"usage-bootstrap-authentication": "true",
+			"usage-bootstrap-signing":        "true",
+			"auth-extra-groups":              bootstrapTokenGroups,
+		},
+	}
+}
+
+// createBootstrapToken create a bootstrap token secret through the API server
+func createBootstrapToken(ctx context.Context, kubeConfig, cacheDir string, expiration time.Time) (string, error) {
+	tokenID, tokenSecret, err := generateBootstrapToken()
+
+	if err != nil {
+		return "", err
+	}
+
+	manifest, err := json.Marshal(bootstrapTokenSecret(tokenID, tokenSecret, expiration))
+
+	if err != nil {
+		return "", err
+	}
+
+	fileName := fmt.Sprintf("%s/bootstrap-token-%s.json", cacheDir, tokenID)
+
+	if err = ioutil.WriteFile(fileName, manifest, 0600); err != nil {
+		return "", err
+	}
+
+	defer os.Remove(fileName)
+
+	if err = shell(ctx, kubectlCommandLine, applyArgument, fileArgument, fileName, kubeConfigArgument, kubeConfig); err != nil {
+		return "", err
+	}
+
+	return tokenID + "." + tokenSecret, nil
+}
+
+// tokenRenewalDue tells if the kubeadm token must be created or renewed
+func (s *MultipassServer) tokenRenewalDue(now time.Time) bool {
+	rotation := s.Configuration.KubeAdm.Rotation
+
+	if rotation == nil {
+		return false
+	}
+
+	return s.KubeAdmTokenExpiration == nil || now.Add(rotation.renewBefore()).After(*s.KubeAdmTokenExpiration)
+}
+
+// rotateKubeAdmToken create a new bootstrap token and refresh the CA hash before the token expires
+func (s *MultipassServer) rotateKubeAdmToken(ctx context.Context) error {
+	s.tokenLock.Lock()
+	defer s.tokenLock.Unlock()
+
+	now := time.Now()
+
+	if !s.tokenRenewalDue(now) {
+		return nil
+	}
+
+	glog.Infof("Rotate the kubeadm token")
+
+	caCertHash, err := discoveryTokenCACertHash(s.Configuration.KubeCtlConfig)
+
+	if err != nil {
+		return wrapError(kindKubernetes, err, errTokenRotationFailed, err)
+	}
+
+	expiration := now.Add(s.Configuration.KubeAdm.Rotation.ttl())
+
+	token, err := createBootstrapToken(ctx, s.Configuration.KubeCtlConfig, s.CacheDir, expiration)
+
+	if err != nil {
+		return wrapError(kindKubernetes, err, errTokenRotationFailed, err)
+	}
+
+	s.KubeAdmConfiguration.KubeAdmToken = token
+	s.KubeAdmConfiguration.KubeAdmCACert = caCertHash
+	s.KubeAdmTokenExpiration = &expiration
+
+	glog.Infof("The kubeadm token expires at %s", expiration.Format(time.RFC3339))
+
+	return nil
+}
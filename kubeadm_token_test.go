@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	apigrpc "github.com/Fred78290/kubernetes-multipass-autoscaler/grpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestCA returns a self signed PEM CA and its kubeadm discovery hash
+func newTestCA(t *testing.T) ([]byte, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kubernetes"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	cert, _ := x509.ParseCertificate(der)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), fmt.Sprintf("sha256:%x", sha256.Sum256(cert.RawSubjectPublicKeyInfo))
+}
+
+func Test_discoveryTokenCACertHash(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer os.RemoveAll(dir)
+
+	ca, hash := newTestCA(t)
+	other, _ := newTestCA(t)
+
+	ioutil.WriteFile(filepath.Join(dir, "ca.crt"), ca, 0600)
+
+	tests := []struct {
+		name       string
+		kubeConfig string
+		wantErr    bool
+	}{
+		{
+			name: "Data",
+			kubeConfig: fmt.Sprintf(`apiVersion: v1
+clusters:
+- name: other
+  cluster:
+    certificate-authority-data: %s
+- name: kubernetes
+  cluster:
+    certificate-authority-data: %s
+contexts:
+- name: admin@kubernetes
+  context:
+    cluster: kubernetes
+current-context: admin@kubernetes
+`, base64.StdEncoding.EncodeToString(other), base64.StdEncoding.EncodeToString(ca)),
+		},
+		{
+			name: "RelativeFile",
+			kubeConfig: `clusters:
+- name: kubernetes
+  cluster:
+    certificate-authority: ca.crt
+`,
+		},
+		{
+			name: "NoCA",
+			kubeConfig: `clusters:
+- name: kubernetes
+  cluster:
+    server: https://192.168.1.10:6443
+`,
+			wantErr: true,
+		},
+		{
+			name:       "Invalid",
+			kubeConfig: "clusters: [",
+			wantErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeConfig := filepath.Join(dir, "config")
+
+			ioutil.WriteFile(kubeConfig, []byte(test.kubeConfig), 0600)
+
+			got, err := discoveryTokenCACertHash(kubeConfig)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, hash, got)
+			}
+		})
+	}
+}
+
+func Test_bootstrapTokenSecret(t *testing.T) {
+	tokenID, tokenSecret, err := generateBootstrapToken()
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Regexp(t, regexp.MustCompile(`^[a-z0-9]{6}$`), tokenID)
+	assert.Regexp(t, regexp.MustCompile(`^[a-z0-9]{16}$`), tokenSecret)
+
+	expiration := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	secret := bootstrapTokenSecret(tokenID, tokenSecret, expiration)
+
+	assert.Equal(t, bootstrapTokenSecretType, secret["type"])
+	assert.Equal(t, map[string]interface{}{
+		"name":      "bootstrap-token-" + tokenID,
+		"namespace": "kube-system",
+	}, secret["metadata"])
+
+	data := secret["stringData"].(map[string]string)
+
+	assert.Equal(t, tokenID, data["token-id"])
+	assert.Equal(t, tokenSecret, data["token-secret"])
+	assert.Equal(t, "2021-03-01T12:00:00Z", data["expiration"])
+	assert.Equal(t, "true", data["usage-bootstrap-authentication"])
+	assert.Equal(t, bootstrapTokenGroups, data["auth-extra-groups"])
+}
+
+func Test_multipassServer_tokenRenewalDue(t *testing.T) {
+	now := time.Now()
+	soon := now.Add(30 * time.Minute)
+	later := now.Add(12 * time.Hour)
+
+	tests := []struct {
+		name       string
+		rotation   *TokenRotation
+		expiration *time.Time
+		want       bool
+	}{
+		{
+			name:       "NoRotation",
+			expiration: &soon,
+		},
+		{
+			name:     "NoToken",
+			rotation: &TokenRotation{},
+			want:     true,
+		},
+		{
+			name:       "Valid",
+			rotation:   &TokenRotation{},
+			expiration: &later,
+		},
+		{
+			name:       "ExpireSoon",
+			rotation:   &TokenRotation{},
+			expiration: &soon,
+			want:       true,
+		},
+		{
+			name:       "RenewBefore",
+			rotation:   &TokenRotation{RenewBefore: 24 * 3600},
+			expiration: &later,
+			want:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &MultipassServer{
+				Configuration:          MultipassServerConfig{KubeAdm: KubeJoinConfig{Rotation: test.rotation}},
+				KubeAdmTokenExpiration: test.expiration,
+			}
+
+			assert.Equal(t, test.want, s.tokenRenewalDue(now))
+		})
+	}
+}
+
+func Test_multipassServer_rotateKubeAdmToken(t *testing.T) {
+	later := time.Now().Add(12 * time.Hour)
+	s := &MultipassServer{
+		Configuration: MultipassServerConfig{
+			KubeCtlConfig: "/nonexistent/config",
+			KubeAdm:       KubeJoinConfig{Rotation: &TokenRotation{}},
+		},
+		KubeAdmConfiguration: &apigrpc.KubeAdmConfig{
+			KubeAdmToken:  "abcdef.0123456789abcdef",
+			KubeAdmCACert: "sha256:1234",
+		},
+		KubeAdmTokenExpiration: &later,
+	}
+
+	// The token is still valid
+	if assert.NoError(t, s.rotateKubeAdmToken(context.Background())) {
+		assert.Equal(t, "abcdef.0123456789abcdef", s.KubeAdmConfiguration.KubeAdmToken)
+	}
+
+	// The token expires, the kubeconfig is not readable
+	s.KubeAdmTokenExpiration = nil
+
+	err := s.rotateKubeAdmToken(context.Background())
+
+	if assert.Error(t, err) {
+		assert.Equal(t, kindInternal, kindOf(err), "the kubeconfig error is kept")
+		assert.Equal(t, "sha256:1234", s.KubeAdmConfiguration.KubeAdmCACert)
+		assert.Nil(t, s.KubeAdmTokenExpiration)
+	}
+}
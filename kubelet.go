@@ -0,0 +1,500 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	"gopkg.in/yaml.v2"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	kubeletConfigPath  = "/var/lib/kubelet/config.yaml"
+	kubeletDefaultPath = "/etc/default/kubelet"
+	kubeletExtraArgs   = "KUBELET_EXTRA_ARGS"
+	kubeletNodeIP      = "__NODE_IP__"
+
+	// kubeletNodeIPScript replace the node IP placeholder of the join configuration by the first VM address in the CIDR,
+	// the placeholder is kept when no address matches and the kubelet refuses to start
+	kubeletNodeIPScript = `ip=$(python3 -c 'import ipaddress, subprocess, sys; n = ipaddress.ip_network(sys.argv[1]); print(next(a for a in subprocess.check_output(["hostname", "-I"]).decode().split() if ipaddress.ip_address(a) in n))' %s) && sed -i "s/%s/$ip/" %s`
+
+	evictionMemoryAvailable = "memory.available"
+	evictionNodeFSAvailable = "nodefs.available"
+)
+
+// kubeletReservedResources are the resources accepted by kube-reserved and system-reserved
+var kubeletReservedResources = map[string]bool{
+	string(apiv1.ResourceCPU):              true,
+	string(apiv1.ResourceMemory):           true,
+	string(apiv1.ResourceEphemeralStorage): true,
+	"pid":                                  true,
+}
+
+// kubeletEvictionSignals are the signals accepted by eviction-hard
+var kubeletEvictionSignals = map[string]bool{
+	evictionMemoryAvailable: true,
+	evictionNodeFSAvailable: true,
+	"nodefs.inodesFree":     true,
+	"imagefs.available":     true,
+	"imagefs.inodesFree":    true,
+	"pid.available":         true,
+}
+
+// KubeletConfig declare the kubelet configuration of a node group
+type KubeletConfig struct {
+	MaxPods            int               `json:"max-pods,omitempty"`             // Optional, 110 by default
+	KubeReserved       map[string]string `json:"kube-reserved,omitempty"`        // Optional, ie: {"cpu": "100m", "memory": "256Mi"}
+	SystemReserved     map[string]string `json:"system-reserved,omitempty"`      // Optional, ie: {"cpu": "100m", "memory": "256Mi"}
+	EvictionHard       map[string]string `json:"eviction-hard,omitempty"`        // Optional, ie: {"memory.available": "100Mi", "nodefs.available": "10%"}
+	NodeIP             string            `json:"node-ip,omitempty"`              // Optional, CIDR of the VM address used as node IP
+	FeatureGates       map[string]bool   `json:"feature-gates,omitempty"`        // Optional, kubelet feature gates
+	RegisterWithTaints []*NodeTaint      `json:"register-with-taints,omitempty"` // Optional, taints registered by the kubelet
+}
+
+func (c *KubeletConfig) validate(nodeGroupID string, bootstrap *BootstrapConfig) error {
+	if c == nil {
+		return nil
+	}
+
+	if name := bootstrap.bootstrapperName(nodeGroupID); name != bootstrapperKubeadm {
+		return newError(kindInvalidArgument, errInvalidKubeletConfig, nodeGroupID, "only kubeadm nodes are configured, not "+name)
+	}
+
+	if c.MaxPods < 0 {
+		return newError(kindInvalidArgument, errInvalidKubeletConfig, nodeGroupID, "max-pods is negative")
+	}
+
+	for _, reserved := range []map[string]string{c.KubeReserved, c.SystemReserved} {
+		for name, value := range reserved {
+			if !kubeletReservedResources[name] {
+				return newError(kindInvalidArgument, errInvalidKubeletConfig, nodeGroupID, "unknown reserved resource "+name)
+			}
+
+			if _, err := resource.ParseQuantity(value); err != nil {
+				return wrapError(kindInvalidArgument, err, errInvalidKubeletConfig, nodeGroupID, err)
+			}
+		}
+	}
+
+	for signal, value := range c.EvictionHard {
+		if !kubeletEvictionSignals[signal] {
+			return newError(kindInvalidArgument, errInvalidKubeletConfig, nodeGroupID, "unknown eviction signal "+signal)
+		}
+
+		if !strings.HasSuffix(value, "%") {
+			if _, err := resource.ParseQuantity(value); err != nil {
+				return wrapError(kindInvalidArgument, err, errInvalidKubeletConfig, nodeGroupID, err)
+			}
+		}
+	}
+
+	if len(c.NodeIP) > 0 {
+		if _, _, err := net.ParseCIDR(c.NodeIP); err != nil {
+			return wrapError(kindInvalidArgument, err, errInvalidKubeletConfig, nodeGroupID, err)
+		}
+	}
+
+	for _, taint := range c.RegisterWithTaints {
+		switch apiv1.TaintEffect(taint.Effect) {
+		case apiv1.TaintEffectNoSchedule, apiv1.TaintEffectPreferNoSchedule, apiv1.TaintEffectNoExecute:
+			if len(taint.Key) > 0 {
+				continue
+			}
+		}
+
+		return newError(kindInvalidArgument, errInvalidNodeTaint, taint.Key, taint.Effect, nodeGroupID)
+	}
+
+	return nil
+}
+
+// registerTaints returns the taints registered by the kubelet
+func (c *KubeletConfig) registerTaints() []apiv1.Taint {
+	if c == nil {
+		return nil
+	}
+
+	taints := make([]apiv1.Taint, 0, len(c.RegisterWithTaints))
+
+	for _, taint := range c.RegisterWithTaints {
+		taints = append(taints, apiv1.Taint{
+			Key:    taint.Key,
+			Value:  taint.Value,
+			Effect: apiv1.TaintEffect(taint.Effect),
+		})
+	}
+
+	return taints
+}
+
+// kubeletConfiguration returns the KubeletConfiguration fields declared for the node group
+func (c *KubeletConfig) kubeletConfiguration() map[string]interface{} {
+	config := map[string]interface{}{
+		"apiVersion": "kubelet.config.k8s.io/v1beta1",
+		"kind":       "KubeletConfiguration",
+	}
+
+	if c.MaxPods > 0 {
+		config["maxPods"] = c.MaxPods
+	}
+
+	if len(c.KubeReserved) > 0 {
+		config["kubeReserved"] = c.KubeReserved
+	}
+
+	if len(c.SystemReserved) > 0 {
+		config["systemReserved"] = c.SystemReserved
+	}
+
+	if len(c.EvictionHard) > 0 {
+		config["evictionHard"] = c.EvictionHard
+	}
+
+	if len(c.FeatureGates) > 0 {
+		config["featureGates"] = c.FeatureGates
+	}
+
+	return config
+}
+
+// joinFlag returns the sorted name=value list of a kubelet map flag
+func joinFlag(values map[string]string, separator string) string {
+	pairs := make([]string, 0, len(values))
+
+	for name, value := range values {
+		pairs = append(pairs, name+separator+value)
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// configurationFlags returns the KubeletConfiguration fields as kubelet flags, given to the kubeadm join in cloud-init bootstrap
+// so the node registers with them
+func (c *KubeletConfig) configurationFlags() map[string]string {
+	flags := make(map[string]string)
+
+	if c == nil {
+		return flags
+	}
+
+	if c.MaxPods > 0 {
+		flags["max-pods"] = strconv.Itoa(c.MaxPods)
+	}
+
+	if len(c.KubeReserved) > 0 {
+		flags["kube-reserved"] = joinFlag(c.KubeReserved, "=")
+	}
+
+	if len(c.SystemReserved) > 0 {
+		flags["system-reserved"] = joinFlag(c.SystemReserved, "=")
+	}
+
+	if len(c.EvictionHard) > 0 {
+		flags["eviction-hard"] = joinFlag(c.EvictionHard, "<")
+	}
+
+	if len(c.FeatureGates) > 0 {
+		gates := make(map[string]string, len(c.FeatureGates))
+
+		for name, enabled := range c.FeatureGates {
+			gates[name] = strconv.FormatBool(enabled)
+		}
+
+		flags["feature-gates"] = joinFlag(gates, "=")
+	}
+
+	if len(c.NodeIP) > 0 {
+		flags["node-ip"] = kubeletNodeIP
+	}
+
+	return flags
+}
+
+// nodeIPCommand returns the cloud-init command setting the node IP in the join configuration, run before the join
+func (c *KubeletConfig) nodeIPCommand(joinConfigPath string) []interface{} {
+	if c == nil || len(c.NodeIP) == 0 {
+		return nil
+	}
+
+	return []interface{}{"sh", "-c", fmt.Sprintf(kubeletNodeIPScript, c.NodeIP, kubeletNodeIP, joinConfigPath)}
+}
+
+// reserved returns the quantity of the resource withdrawn from the capacity
+func (c *KubeletConfig) reserved(name apiv1.ResourceName, capacity resource.Quantity) resource.Quantity {
+	total := resource.Quantity{Format: capacity.Format}
+
+	for _, reserved := range []map[string]string{c.KubeReserved, c.SystemReserved} {
+		if value, found := reserved[string(name)]; found {
+			total.Add(resource.MustParse(value))
+		}
+	}
+
+	signal := map[apiv1.ResourceName]string{
+		apiv1.ResourceMemory:           evictionMemoryAvailable,
+		apiv1.ResourceEphemeralStorage: evictionNodeFSAvailable,
+	}[name]
+
+	if value, found := c.EvictionHard[signal]; found {
+		if strings.HasSuffix(value, "%") {
+			var percent float64
+
+			if _, err := fmt.Sscanf(value, "%f%%", &percent); err == nil {
+				total.Add(*resource.NewQuantity(int64(float64(capacity.Value())*percent/100), capacity.Format))
+			}
+		} else {
+			total.Add(resource.MustParse(value))
+		}
+	}
+
+	return total
+}
+
+// allocatable returns the node allocatable resources computed like the kubelet does
+func (c *KubeletConfig) allocatable(capacity apiv1.ResourceList) apiv1.ResourceList {
+	allocatable := capacity.DeepCopy()
+
+	if c == nil {
+		return allocatable
+	}
+
+	if c.MaxPods > 0 {
+		allocatable[apiv1.ResourcePods] = *resource.NewQuantity(int64(c.MaxPods), resource.DecimalSI)
+	}
+
+	for _, name := range []apiv1.ResourceName{apiv1.ResourceCPU, apiv1.ResourceMemory, apiv1.ResourceEphemeralStorage} {
+		if value, found := allocatable[name]; found {
+			value.Sub(c.reserved(name, value))
+
+			if value.Sign() < 0 {
+				value = resource.Quantity{Format: value.Format}
+			}
+
+			allocatable[name] = value
+		}
+	}
+
+	return allocatable
+}
+
+// nodeIP returns the first address in the node-ip CIDR
+func (c *KubeletConfig) nodeIP(addresses []string) (string, error) {
+	_, network, err := net.ParseCIDR(c.NodeIP)
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, address := range addresses {
+		if ip := net.ParseIP(address); ip != nil && network.Contains(ip) {
+			return address, nil
+		}
+	}
+
+	return "", newError(kindInternal, errNoAddressInNodeIP, c.NodeIP)
+}
+
+// setKubeletFlags set the flags in the KUBELET_EXTRA_ARGS of /etc/default/kubelet content,
+// the previous values of the flags are replaced so the file is the same after each run
+func setKubeletFlags(content string, flags map[string]string) string {
+	var args []string
+
+	lines := make([]string, 0)
+	prefix := kubeletExtraArgs + "="
+
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, prefix) {
+			args = strings.Fields(strings.Trim(strings.TrimPrefix(line, prefix), "\"'"))
+		} else if len(strings.TrimSpace(line)) > 0 {
+			lines = append(lines, line)
+		}
+	}
+
+	extraArgs := make([]string, 0, len(args)+len(flags))
+
+	for _, arg := range args {
+		name := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)[0]
+
+		if _, found := flags[name]; !found {
+			extraArgs = append(extraArgs, arg)
+		}
+	}
+
+	names := make([]string, 0, len(flags))
+
+	for name := range flags {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		extraArgs = append(extraArgs, fmt.Sprintf("--%s=%s", name, flags[name]))
+	}
+
+	lines = append(lines, fmt.Sprintf("%s\"%s\"", prefix, strings.Join(extraArgs, " ")))
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// readFile returns the content of a VM file, empty if it doesn't exist
+func (vm *MultipassNode) readFile(ctx context.Context, extras *nodeCreationExtra, fileName string) (string, error) {
+	backend, err := vm.backend(extras)
+
+	if err != nil {
+		return "", err
+	}
+
+	return backend.Exec(ctx, vm.NodeName, sudoArgument, "sh", "-c", fmt.Sprintf("cat %s 2>/dev/null || true", fileName))
+}
+
+// installFile copy the content in the VM file
+func (vm *MultipassNode) installFile(ctx context.Context, extras *nodeCreationExtra, content []byte, fileName, mode string) error {
+	backend, err := vm.backend(extras)
+
+	if err != nil {
+		return err
+	}
+
+	baseName := strings.ReplaceAll(strings.TrimPrefix(fileName, "/"), "/", "-")
+	srcName := fmt.Sprintf("%s/%s-%s", extras.cacheDir, vm.NodeName, baseName)
+	dstName := fmt.Sprintf("/tmp/%s-%s", vm.NodeName, baseName)
+
+	if err = ioutil.WriteFile(srcName, content, 0600); err != nil {
+		return err
+	}
+
+	defer os.Remove(srcName)
+
+	if err = backend.CopyFile(ctx, vm.NodeName, srcName, dstName); err != nil {
+		return err
+	}
+
+	install := fmt.Sprintf("mkdir -p $(dirname %s) && install -m %s %s %s && rm -f %s", fileName, mode, dstName, fileName, dstName)
+
+	_, err = backend.Exec(ctx, vm.NodeName, sudoArgument, "sh", "-c", install)
+
+	return err
+}
+
+// setKubeletDefault set the kubelet flags in /etc/default/kubelet
+func (vm *MultipassNode) setKubeletDefault(ctx context.Context, extras *nodeCreationExtra, flags map[string]string) error {
+	content, err := vm.readFile(ctx, extras, kubeletDefaultPath)
+
+	if err != nil {
+		return err
+	}
+
+	return vm.installFile(ctx, extras, []byte(setKubeletFlags(content, flags)), kubeletDefaultPath, "0644")
+}
+
+// kubeletNodeIP returns the VM address selected as node IP
+func (vm *MultipassNode) kubeletNodeIP(ctx context.Context, extras *nodeCreationExtra) (string, error) {
+	backend, err := vm.backend(extras)
+
+	if err != nil {
+		return "", err
+	}
+
+	status, err := backend.Status(ctx, vm.NodeName)
+
+	if err != nil {
+		return "", err
+	}
+
+	return extras.kubelet.nodeIP(status.Addresses)
+}
+
+// kubeletFlags returns the kubelet flags set before the node join
+func (vm *MultipassNode) kubeletFlags(ctx context.Context, extras *nodeCreationExtra) (map[string]string, error) {
+	var err error
+
+	flags := map[string]string{
+		"provider-id": vm.ProviderID,
+	}
+
+	if extras.kubelet == nil {
+		return flags, nil
+	}
+
+	if len(extras.kubelet.NodeIP) > 0 {
+		if flags["node-ip"], err = vm.kubeletNodeIP(ctx, extras); err != nil {
+			return nil, err
+		}
+	}
+
+	if taints := extras.kubelet.registerTaints(); len(taints) > 0 {
+		values := make([]string, 0, len(taints))
+
+		for _, taint := range taints {
+			values = append(values, taint.ToString())
+		}
+
+		flags["register-with-taints"] = strings.Join(values, ",")
+	}
+
+	return flags, nil
+}
+
+// configureKubelet merge the node group KubeletConfiguration in the kubelet config written by kubeadm join
+// in exec bootstrap, then restart the kubelet
+func (vm *MultipassNode) configureKubelet(ctx context.Context, extras *nodeCreationExtra) error {
+	if extras.kubelet == nil {
+		return nil
+	}
+
+	glog.Infof("Configure the kubelet of VM:%s", vm.NodeName)
+
+	backend, err := vm.backend(extras)
+
+	if err != nil {
+		return err
+	}
+
+	content, err := vm.readFile(ctx, extras, kubeletConfigPath)
+
+	if err != nil {
+		return wrapError(kindVM, err, errKubeletConfigFailed, vm.NodeName, err)
+	}
+
+	var config map[string]interface{}
+
+	if err = yaml.Unmarshal([]byte(content), &config); err != nil {
+		return wrapError(kindVM, err, errKubeletConfigFailed, vm.NodeName, err)
+	}
+
+	merged, _ := normalizeCloudInit(config).(map[string]interface{})
+
+	if merged == nil {
+		merged = make(map[string]interface{})
+	}
+
+	if err = mergeCloudInit(merged, extras.kubelet.kubeletConfiguration()); err != nil {
+		return wrapError(kindInternal, err, errKubeletConfigFailed, vm.NodeName, err)
+	}
+
+	out, err := yaml.Marshal(merged)
+
+	if err == nil {
+		err = vm.installFile(ctx, extras, out, kubeletConfigPath, "0644")
+	}
+
+	if err != nil {
+		return wrapError(kindVM, err, errKubeletConfigFailed, vm.NodeName, err)
+	}
+
+	if _, err = backend.Exec(ctx, vm.NodeName, sudoArgument, "systemctl", "restart", "kubelet"); err != nil {
+		return wrapError(kindVM, err, errKubeletConfigFailed, vm.NodeName, err)
+	}
+
+	return nil
+}
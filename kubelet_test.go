@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+func Test_kubeletConfig_validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    *KubeletConfig
+		bootstrap *BootstrapConfig
+		wantErr   bool
+	}{
+		{
+			name: "Nil",
+		},
+		{
+			name: "Valid",
+			config: &KubeletConfig{
+				MaxPods:            60,
+				KubeReserved:       map[string]string{"cpu": "100m", "memory": "256Mi"},
+				SystemReserved:     map[string]string{"ephemeral-storage": "1Gi"},
+				EvictionHard:       map[string]string{"memory.available": "100Mi", "nodefs.available": "10%"},
+				NodeIP:             "10.0.0.0/24",
+				FeatureGates:       map[string]bool{"RotateKubeletServerCertificate": true},
+				RegisterWithTaints: []*NodeTaint{{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}},
+			},
+		},
+		{
+			name:      "K3S",
+			config:    &KubeletConfig{MaxPods: 60},
+			bootstrap: &BootstrapConfig{Bootstrapper: bootstrapperK3S},
+			wantErr:   true,
+		},
+		{
+			name:    "Negative max pods",
+			config:  &KubeletConfig{MaxPods: -1},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown reserved resource",
+			config:  &KubeletConfig{KubeReserved: map[string]string{"gpu": "1"}},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid reserved quantity",
+			config:  &KubeletConfig{SystemReserved: map[string]string{"memory": "lot"}},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown eviction signal",
+			config:  &KubeletConfig{EvictionHard: map[string]string{"memory.free": "100Mi"}},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid node IP",
+			config:  &KubeletConfig{NodeIP: "10.0.0.1"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid taint",
+			config:  &KubeletConfig{RegisterWithTaints: []*NodeTaint{{Key: "dedicated", Effect: "Never"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validate(testGroupID, tt.bootstrap)
+
+			if tt.wantErr {
+				if assert.Error(t, err) {
+					assert.Equal(t, codes.InvalidArgument, kindOf(err).code)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_setKubeletFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		flags   map[string]string
+		want    string
+	}{
+		{
+			name:    "Empty",
+			content: "",
+			flags:   map[string]string{"provider-id": "multipass://id"},
+			want:    "KUBELET_EXTRA_ARGS=\"--provider-id=multipass://id\"\n",
+		},
+		{
+			name:    "Keep other flags",
+			content: "# kubelet\nKUBELET_EXTRA_ARGS=\"--cgroup-driver=systemd\"\n",
+			flags:   map[string]string{"provider-id": "multipass://id", "node-ip": "10.0.0.2"},
+			want:    "# kubelet\nKUBELET_EXTRA_ARGS=\"--cgroup-driver=systemd --node-ip=10.0.0.2 --provider-id=multipass://id\"\n",
+		},
+		{
+			name:    "Replace previous values",
+			content: "KUBELET_EXTRA_ARGS=\"--cgroup-driver=systemd --provider-id=multipass://old --provider-id=multipass://older\"\n",
+			flags:   map[string]string{"provider-id": "multipass://id"},
+			want:    "KUBELET_EXTRA_ARGS=\"--cgroup-driver=systemd --provider-id=multipass://id\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := setKubeletFlags(tt.content, tt.flags)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, got, setKubeletFlags(got, tt.flags), "not idempotent")
+		})
+	}
+}
+
+func Test_kubeletConfig_allocatable(t *testing.T) {
+	machine := &MachineCharacteristic{Memory: 4096, Vcpu: 2, Disk: 10240}
+	capacity := machine.capacity()
+
+	assert.Equal(t, capacity, (*KubeletConfig)(nil).allocatable(capacity))
+
+	config := &KubeletConfig{
+		MaxPods:        60,
+		KubeReserved:   map[string]string{"cpu": "100m", "memory": "256Mi"},
+		SystemReserved: map[string]string{"cpu": "100m", "memory": "256Mi"},
+		EvictionHard:   map[string]string{"memory.available": "512Mi", "nodefs.available": "10%"},
+	}
+
+	allocatable := config.allocatable(capacity)
+	cpu := allocatable[apiv1.ResourceCPU]
+	memory := allocatable[apiv1.ResourceMemory]
+	storage := allocatable[apiv1.ResourceEphemeralStorage]
+	pods := allocatable[apiv1.ResourcePods]
+
+	assert.Equal(t, int64(1800), cpu.MilliValue())
+	assert.Equal(t, int64(3072*1024*1024), memory.Value())
+	assert.Equal(t, int64(9216*1024*1024), storage.Value())
+	assert.Equal(t, int64(60), pods.Value())
+	assert.Equal(t, int64(2000), capacity.Cpu().MilliValue(), "the capacity is not modified")
+
+	// The reservation never makes a negative allocatable
+	config = &KubeletConfig{KubeReserved: map[string]string{"cpu": "4"}}
+	cpu = config.allocatable(capacity)[apiv1.ResourceCPU]
+
+	assert.Equal(t, int64(0), cpu.MilliValue())
+}
+
+func Test_kubeletConfig_nodeIP(t *testing.T) {
+	config := &KubeletConfig{NodeIP: "192.168.2.0/24"}
+
+	nodeIP, err := config.nodeIP([]string{"10.0.0.2", "192.168.2.12"})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "192.168.2.12", nodeIP)
+	}
+
+	_, err = config.nodeIP([]string{"10.0.0.2"})
+
+	if assert.Error(t, err) {
+		assert.Equal(t, codes.Internal, kindOf(err).code)
+	}
+}
+
+func Test_kubeletConfig_configurationFlags(t *testing.T) {
+	var config *KubeletConfig
+
+	assert.Empty(t, config.configurationFlags())
+	assert.Nil(t, config.nodeIPCommand(kubeadmJoinConfigPath))
+
+	config = &KubeletConfig{
+		MaxPods:        60,
+		KubeReserved:   map[string]string{"memory": "256Mi", "cpu": "100m"},
+		SystemReserved: map[string]string{"ephemeral-storage": "1Gi"},
+		EvictionHard:   map[string]string{"nodefs.available": "10%", "memory.available": "100Mi"},
+		NodeIP:         "192.168.2.0/24",
+		FeatureGates:   map[string]bool{"RotateKubeletServerCertificate": true, "GracefulNodeShutdown": false},
+	}
+
+	assert.Equal(t, map[string]string{
+		"max-pods":        "60",
+		"kube-reserved":   "cpu=100m,memory=256Mi",
+		"system-reserved": "ephemeral-storage=1Gi",
+		"eviction-hard":   "memory.available<100Mi,nodefs.available<10%",
+		"feature-gates":   "GracefulNodeShutdown=false,RotateKubeletServerCertificate=true",
+		"node-ip":         kubeletNodeIP,
+	}, config.configurationFlags())
+
+	command := config.nodeIPCommand(kubeadmJoinConfigPath)
+
+	if assert.Len(t, command, 3) {
+		assert.Contains(t, command[2], "192.168.2.0/24")
+		assert.Contains(t, command[2], kubeletNodeIP)
+		assert.Contains(t, command[2], kubeadmJoinConfigPath)
+	}
+}
+
+func Test_multipassNodeGroup_templateNodeKubelet(t *testing.T) {
+	ng := newTestNodeGroup("", withMachine(&MachineCharacteristic{Memory: 4096, Vcpu: 2, Disk: 10240}))
+
+	ng.Kubelet = &KubeletConfig{MaxPods: 60, KubeReserved: map[string]string{"cpu": "500m"}}
+
+	node := ng.templateNode()
+	cpu := node.Status.Allocatable[apiv1.ResourceCPU]
+	pods := node.Status.Capacity[apiv1.ResourcePods]
+
+	assert.Equal(t, int64(1500), cpu.MilliValue())
+	assert.Equal(t, int64(60), pods.Value())
+}
+
+func Test_multipassNode_configureKubelet(t *testing.T) {
+	backend := newFakeBackend()
+	extras := newFakeExtras(backend)
+	vm := &MultipassNode{NodeName: testNodeName, ProviderID: testProviderID}
+
+	backend.vms[testNodeName] = &VMStatus{
+		State:     MultipassNodeStateRunning,
+		Addresses: []string{"10.0.0.2"},
+	}
+
+	assert.NoError(t, vm.configureKubelet(context.Background(), extras), "nothing to configure")
+	assert.Empty(t, backend.calls)
+
+	extras.kubelet = &KubeletConfig{MaxPods: 60}
+
+	if assert.NoError(t, vm.configureKubelet(context.Background(), extras)) {
+		assert.Equal(t, []string{"exec", "copy", "exec", "exec"}, backend.calls)
+	}
+
+	backend.calls = nil
+	extras.kubelet.NodeIP = "192.168.2.0/24"
+
+	assert.NoError(t, vm.prepareKubelet(context.Background(), &nodeCreationExtra{backend: backend, cacheDir: "."}))
+
+	err := vm.prepareKubelet(context.Background(), extras)
+
+	if assert.Error(t, err, "no address in the node-ip CIDR") {
+		assert.Equal(t, codes.Internal, kindOf(err).code)
+	}
+}
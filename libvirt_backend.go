@@ -0,0 +1,418 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const (
+	backendLibvirt = "libvirt"
+
+	defaultLibvirtURI     = "qemu:///system"
+	defaultLibvirtPool    = "default"
+	defaultLibvirtNetwork = "default"
+
+	libvirtStateRunning = "running"
+	libvirtStateShutOff = "shut off"
+)
+
+// LibvirtConfig declare how to reach libvirt and how domains are created
+type LibvirtConfig struct {
+	URI        string `json:"uri"`         // Optional, libvirt connection URI, qemu:///system by default
+	Pool       string `json:"pool"`        // Optional, storage pool holding the base and the VM volumes, default by default
+	BaseVolume string `json:"base-volume"` // Mandatory, qcow2 volume cloned for each VM, the machine type image override it
+	Network    string `json:"network"`     // Optional, libvirt network serving DHCP, default by default
+	SeedDir    string `json:"seed-dir"`    // Optional, local directory where the cloud-init seed ISO is built before its upload in the pool, the cache directory by default
+	SSHUser    string `json:"ssh-user"`    // Optional, user to exec commands in the VM, ubuntu by default
+	SSHKey     string `json:"ssh-key"`     // Optional, private key to exec commands in the VM
+}
+
+// remote tells if the URI connect to another host, like qemu+ssh://host/system or qemu://host/system
+func (c *LibvirtConfig) remote() bool {
+	if len(c.URI) == 0 {
+		return false
+	}
+
+	uri, err := url.Parse(c.URI)
+
+	if err != nil {
+		return true
+	}
+
+	transport := ""
+
+	if index := strings.Index(uri.Scheme, "+"); index >= 0 {
+		transport = uri.Scheme[index+1:]
+	}
+
+	return len(uri.Host) > 0 || (len(transport) > 0 && transport != "unix")
+}
+
+// libvirtLease is a DHCP lease of a libvirt network
+type libvirtLease struct {
+	MAC     string
+	Address string
+}
+
+// libvirtConnection is the subset of libvirt used by the backend, the guest is reached over ssh
+type libvirtConnection interface {
+	// CloneVolume clone the base volume, resize it and returns the path of the new volume
+	CloneVolume(ctx context.Context, pool, base, name string, size int) (string, error)
+	DeleteVolume(ctx context.Context, pool, name string) error
+	// BuildSeedISO write a NoCloud ISO with user-data and meta-data
+	BuildSeedISO(ctx context.Context, iso string, userData, metaData []byte) error
+	// UploadVolume create a raw volume in the pool with the content of the local file, the libvirt host may be remote
+	UploadVolume(ctx context.Context, pool, name, file string) error
+	DefineDomain(ctx context.Context, xml string) error
+	StartDomain(ctx context.Context, name string) error
+	ShutdownDomain(ctx context.Context, name string) error
+	DestroyDomain(ctx context.Context, name string) error
+	UndefineDomain(ctx context.Context, name string) error
+	// DomainState returns the virsh domstate, running, shut off...
+	DomainState(ctx context.Context, name string) (string, error)
+	// DomainMACs returns the MAC addresses of the domain interfaces
+	DomainMACs(ctx context.Context, name string) ([]string, error)
+	NetworkLeases(ctx context.Context, network string) ([]libvirtLease, error)
+	ListDomains(ctx context.Context) ([]string, error)
+	// Exec and Copy reach the guest with ssh
+	Exec(ctx context.Context, address string, args ...string) (string, error)
+	Copy(ctx context.Context, address, src, dst string) error
+}
+
+// libvirtDomainTemplate is the domain definition, the NoCloud seed volume is attached as cdrom
+var libvirtDomainTemplate = template.Must(template.New("domain").Parse(`<domain type="kvm">
+  <name>{{.Name}}</name>
+  <memory unit="MiB">{{.Memory}}</memory>
+  <vcpu>{{.CPU}}</vcpu>
+  <os>
+    <type arch="{{.Arch}}">hvm</type>
+    <boot dev="hd"/>
+  </os>
+  <features>
+    <acpi/>
+    <apic/>
+  </features>
+  <cpu mode="host-passthrough"/>
+  <devices>
+    <disk type="file" device="disk">
+      <driver name="qemu" type="qcow2"/>
+      <source file="{{.Volume}}"/>
+      <target dev="vda" bus="virtio"/>
+    </disk>
+    <disk type="volume" device="cdrom">
+      <driver name="qemu" type="raw"/>
+      <source pool="{{.Pool}}" volume="{{.Seed}}"/>
+      <target dev="sda" bus="sata"/>
+      <readonly/>
+    </disk>
+    <interface type="network">
+      <source network="{{.Network}}"/>
+      <model type="virtio"/>
+    </interface>
+    <serial type="pty">
+      <target port="0"/>
+    </serial>
+    <console type="pty">
+      <target type="serial" port="0"/>
+    </console>
+  </devices>
+</domain>
+`))
+
+// libvirtBackend drive the domains of a libvirt host
+type libvirtBackend struct {
+	config   *LibvirtConfig
+	conn     libvirtConnection
+	cacheDir string
+}
+
+func newLibvirtBackend(config *LibvirtConfig, cacheDir string) *libvirtBackend {
+	if config == nil {
+		config = &LibvirtConfig{}
+	}
+
+	return &libvirtBackend{
+		config:   config,
+		conn:     newVirshConnection(config),
+		cacheDir: cacheDir,
+	}
+}
+
+func (b *libvirtBackend) pool() string {
+	if len(b.config.Pool) == 0 {
+		return defaultLibvirtPool
+	}
+
+	return b.config.Pool
+}
+
+func (b *libvirtBackend) network() string {
+	if len(b.config.Network) == 0 {
+		return defaultLibvirtNetwork
+	}
+
+	return b.config.Network
+}
+
+func (b *libvirtBackend) seedPath(name string) string {
+	dir := b.config.SeedDir
+
+	if len(dir) == 0 {
+		dir = b.cacheDir
+	}
+
+	return path.Join(dir, seedVolume(name))
+}
+
+// seedVolume is the pool volume of the NoCloud seed
+func seedVolume(name string) string {
+	return fmt.Sprintf("seed-%s.iso", name)
+}
+
+func (b *libvirtBackend) Launch(ctx context.Context, spec *VMLaunchSpec) error {
+	var err error
+	var userData []byte
+	var volume string
+	var domain bytes.Buffer
+
+	base := spec.Image
+
+	if len(base) == 0 {
+		base = b.config.BaseVolume
+	}
+
+	if len(base) == 0 {
+		return newError(kindInvalidArgument, errLibvirtBaseVolumeNotDefined)
+	}
+
+	if userData, err = spec.userData(); err != nil {
+		return err
+	}
+
+	metaData := []byte(fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", spec.Name, spec.Name))
+	seed := b.seedPath(spec.Name)
+
+	if err = b.conn.BuildSeedISO(ctx, seed, userData, metaData); err != nil {
+		return wrapError(kindVM, err, errLibvirtSeedFailed, spec.Name, err)
+	}
+
+	// The domain use the uploaded volume, the local ISO is no more needed
+	defer os.Remove(seed)
+
+	if err = b.conn.UploadVolume(ctx, b.pool(), seedVolume(spec.Name), seed); err != nil {
+		return wrapError(kindVM, err, errLibvirtSeedFailed, spec.Name, err)
+	}
+
+	if volume, err = b.conn.CloneVolume(ctx, b.pool(), base, spec.Name, spec.Disk); err != nil {
+		b.conn.DeleteVolume(ctx, b.pool(), seedVolume(spec.Name))
+
+		return err
+	}
+
+	err = libvirtDomainTemplate.Execute(&domain, map[string]interface{}{
+		"Name":    spec.Name,
+		"Memory":  spec.Memory,
+		"CPU":     maxInt(spec.CPU, 1),
+		"Arch":    kernelArch(spec.Arch),
+		"Volume":  volume,
+		"Pool":    b.pool(),
+		"Seed":    seedVolume(spec.Name),
+		"Network": b.network(),
+	})
+
+	if err == nil {
+		if err = b.conn.DefineDomain(ctx, domain.String()); err == nil {
+			if err = b.conn.StartDomain(ctx, spec.Name); err == nil {
+				return b.waitAddress(ctx, spec.Name)
+			}
+
+			b.conn.UndefineDomain(ctx, spec.Name)
+		}
+	}
+
+	b.conn.DeleteVolume(ctx, b.pool(), spec.Name)
+	b.conn.DeleteVolume(ctx, b.pool(), seedVolume(spec.Name))
+
+	return wrapError(kindVM, err, errLibvirtDomainFailed, spec.Name, err)
+}
+
+// waitAddress returns when the domain got a DHCP lease, kubeadm join need it
+func (b *libvirtBackend) waitAddress(ctx context.Context, name string) error {
+	for {
+		if addresses, err := b.addresses(ctx, name); err == nil && len(addresses) > 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return wrapError(kindTimeout, ctx.Err(), errVMNoAddress, name, ctx.Err())
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+func (b *libvirtBackend) Start(ctx context.Context, name string) error {
+	return b.conn.StartDomain(ctx, name)
+}
+
+// Stop shutdown the domain and wait it's shut off
+func (b *libvirtBackend) Stop(ctx context.Context, name string) error {
+	if err := b.conn.ShutdownDomain(ctx, name); err != nil {
+		return err
+	}
+
+	for {
+		state, err := b.conn.DomainState(ctx, name)
+
+		if err != nil {
+			return err
+		}
+
+		if state == libvirtStateShutOff {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return wrapError(kindTimeout, ctx.Err(), errStopVMFailed, name, ctx.Err())
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+func (b *libvirtBackend) Delete(ctx context.Context, name string) error {
+	state, err := b.conn.DomainState(ctx, name)
+
+	if err != nil {
+		return err
+	}
+
+	if state != libvirtStateShutOff {
+		if err = b.conn.DestroyDomain(ctx, name); err != nil {
+			return err
+		}
+	}
+
+	if err = b.conn.UndefineDomain(ctx, name); err != nil {
+		return err
+	}
+
+	if err = b.conn.DeleteVolume(ctx, b.pool(), name); err != nil {
+		glog.Warningf("Unable to delete volume of VM: %s, reason: %v", name, err)
+	}
+
+	if err = b.conn.DeleteVolume(ctx, b.pool(), seedVolume(name)); err != nil {
+		glog.Warningf("Unable to delete seed volume of VM: %s, reason: %v", name, err)
+	}
+
+	return nil
+}
+
+// addresses match the domain MAC with the network DHCP leases
+func (b *libvirtBackend) addresses(ctx context.Context, name string) ([]string, error) {
+	macs, err := b.conn.DomainMACs(ctx, name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	leases, err := b.conn.NetworkLeases(ctx, b.network())
+
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := []string{}
+
+	for _, mac := range macs {
+		for _, lease := range leases {
+			if strings.EqualFold(mac, lease.MAC) {
+				addresses = append(addresses, lease.Address)
+			}
+		}
+	}
+
+	return addresses, nil
+}
+
+func (b *libvirtBackend) Status(ctx context.Context, name string) (*VMStatus, error) {
+	state, err := b.conn.DomainState(ctx, name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	status := &VMStatus{
+		Addresses: []string{},
+	}
+
+	switch state {
+	case libvirtStateRunning:
+		status.State = MultipassNodeStateRunning
+
+		if status.Addresses, err = b.addresses(ctx, name); err != nil {
+			glog.Warningf(errGetVMInfoFailed, name, err)
+		}
+	case libvirtStateShutOff:
+		status.State = MultipassNodeStateStopped
+	default:
+		glog.Infof(errVMStateUndefined, name, state)
+
+		status.State = MultipassNodeStateUndefined
+	}
+
+	return status, nil
+}
+
+// address returns the first address of a running domain
+func (b *libvirtBackend) address(ctx context.Context, name string) (string, error) {
+	addresses, err := b.addresses(ctx, name)
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(addresses) == 0 {
+		return "", newError(kindVM, errVMNoAddress, name, "no DHCP lease")
+	}
+
+	return addresses[0], nil
+}
+
+func (b *libvirtBackend) Exec(ctx context.Context, name string, args ...string) (string, error) {
+	address, err := b.address(ctx, name)
+
+	if err != nil {
+		return "", err
+	}
+
+	return b.conn.Exec(ctx, address, args...)
+}
+
+func (b *libvirtBackend) CopyFile(ctx context.Context, name, src, dst string) error {
+	address, err := b.address(ctx, name)
+
+	if err != nil {
+		return err
+	}
+
+	return b.conn.Copy(ctx, address, src, dst)
+}
+
+// Mount is not supported, a shared folder must be declared in the domain before it start
+func (b *libvirtBackend) Mount(ctx context.Context, name, hostPath, guestPath string) error {
+	return newError(kindUnimplemented, errMountUnsupported, hostPath, name, backendLibvirt)
+}
+
+func (b *libvirtBackend) List(ctx context.Context) ([]string, error) {
+	return b.conn.ListDomains(ctx)
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+type fakeLibvirtDomain struct {
+	xml   string
+	state string
+	mac   string
+}
+
+// fakeLibvirtConnection is an in memory libvirt host with a DHCP server
+type fakeLibvirtConnection struct {
+	sync.Mutex
+	volumes  map[string]int
+	seeds    map[string]string
+	uploads  map[string]string
+	domains  map[string]*fakeLibvirtDomain
+	leases   []libvirtLease
+	commands []string
+	copies   map[string]string
+}
+
+func newFakeLibvirtConnection() *fakeLibvirtConnection {
+	return &fakeLibvirtConnection{
+		volumes: map[string]int{"bionic.qcow2": 2048},
+		seeds:   make(map[string]string),
+		uploads: make(map[string]string),
+		domains: make(map[string]*fakeLibvirtDomain),
+		copies:  make(map[string]string),
+	}
+}
+
+func (c *fakeLibvirtConnection) domain(name string) (*fakeLibvirtDomain, error) {
+	if domain := c.domains[name]; domain != nil {
+		return domain, nil
+	}
+
+	return nil, newError(kindNotFound, errVMNotFound, name)
+}
+
+func (c *fakeLibvirtConnection) CloneVolume(ctx context.Context, pool, base, name string, size int) (string, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, found := c.volumes[base]; !found {
+		return "", newError(kindNotFound, errVMNotFound, base)
+	}
+
+	c.volumes[name] = size
+
+	return "/var/lib/libvirt/images/" + name, nil
+}
+
+func (c *fakeLibvirtConnection) DeleteVolume(ctx context.Context, pool, name string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	delete(c.volumes, name)
+
+	return nil
+}
+
+func (c *fakeLibvirtConnection) BuildSeedISO(ctx context.Context, iso string, userData, metaData []byte) error {
+	c.Lock()
+	defer c.Unlock()
+
+	c.seeds[iso] = string(userData) + "---\n" + string(metaData)
+
+	return nil
+}
+
+func (c *fakeLibvirtConnection) UploadVolume(ctx context.Context, pool, name, file string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	c.volumes[name] = 0
+	c.uploads[name] = c.seeds[file]
+
+	return nil
+}
+
+func (c *fakeLibvirtConnection) DefineDomain(ctx context.Context, xml string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	name := xml[strings.Index(xml, "<name>")+6 : strings.Index(xml, "</name>")]
+	mac := "52:54:00:00:00:" + string(rune('a'+len(c.domains)))
+
+	c.domains[name] = &fakeLibvirtDomain{xml: xml, state: libvirtStateShutOff, mac: mac}
+
+	return nil
+}
+
+func (c *fakeLibvirtConnection) setState(name, state string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	domain, err := c.domain(name)
+
+	if err == nil {
+		domain.state = state
+
+		if state == libvirtStateRunning {
+			c.leases = append(c.leases, libvirtLease{MAC: strings.ToUpper(domain.mac), Address: "192.168.122.10"})
+		}
+	}
+
+	return err
+}
+
+func (c *fakeLibvirtConnection) StartDomain(ctx context.Context, name string) error {
+	return c.setState(name, libvirtStateRunning)
+}
+
+func (c *fakeLibvirtConnection) ShutdownDomain(ctx context.Context, name string) error {
+	return c.setState(name, libvirtStateShutOff)
+}
+
+func (c *fakeLibvirtConnection) DestroyDomain(ctx context.Context, name string) error {
+	return c.setState(name, libvirtStateShutOff)
+}
+
+func (c *fakeLibvirtConnection) UndefineDomain(ctx context.Context, name string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	delete(c.domains, name)
+
+	return nil
+}
+
+func (c *fakeLibvirtConnection) DomainState(ctx context.Context, name string) (string, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	domain, err := c.domain(name)
+
+	if err != nil {
+		return "", err
+	}
+
+	return domain.state, nil
+}
+
+func (c *fakeLibvirtConnection) DomainMACs(ctx context.Context, name string) ([]string, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	domain, err := c.domain(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{domain.mac}, nil
+}
+
+func (c *fakeLibvirtConnection) NetworkLeases(ctx context.Context, network string) ([]libvirtLease, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.leases, nil
+}
+
+func (c *fakeLibvirtConnection) ListDomains(ctx context.Context) ([]string, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	names := []string{}
+
+	for name := range c.domains {
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+func (c *fakeLibvirtConnection) Exec(ctx context.Context, address string, args ...string) (string, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.commands = append(c.commands, address+":"+strings.Join(args, " "))
+
+	return "", nil
+}
+
+func (c *fakeLibvirtConnection) Copy(ctx context.Context, address, src, dst string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	c.copies[dst] = address + ":" + src
+
+	return nil
+}
+
+func Test_libvirtBackend(t *testing.T) {
+	conn := newFakeLibvirtConnection()
+	b := &libvirtBackend{
+		config: &LibvirtConfig{
+			BaseVolume: "bionic.qcow2",
+			SeedDir:    "/var/lib/libvirt/seeds",
+		},
+		conn: conn,
+	}
+
+	ctx := context.Background()
+
+	spec := &VMLaunchSpec{
+		Name:   testNodeName,
+		Memory: 2048,
+		CPU:    2,
+		Disk:   5120,
+		Arch:   "arm64",
+		CloudInit: map[string]interface{}{
+			"package_update": true,
+		},
+	}
+
+	if !assert.NoError(t, b.Launch(ctx, spec)) {
+		return
+	}
+
+	domain := conn.domains[testNodeName]
+	seed := "seed-" + testNodeName + ".iso"
+
+	assert.Equal(t, 5120, conn.volumes[testNodeName])
+	assert.Contains(t, domain.xml, `<memory unit="MiB">2048</memory>`)
+	assert.Contains(t, domain.xml, "<vcpu>2</vcpu>")
+	assert.Contains(t, domain.xml, `<type arch="aarch64">hvm</type>`)
+	assert.Contains(t, domain.xml, "/var/lib/libvirt/images/"+testNodeName)
+	assert.Contains(t, domain.xml, `<source pool="default" volume="`+seed+`"/>`, "the seed is uploaded, the libvirt host may be remote")
+	assert.Contains(t, conn.seeds, "/var/lib/libvirt/seeds/"+seed)
+	assert.True(t, strings.HasPrefix(conn.uploads[seed], "#cloud-config\npackage_update: true\n"))
+	assert.Contains(t, conn.uploads[seed], "local-hostname: "+testNodeName)
+
+	status, err := b.Status(ctx, testNodeName)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, MultipassNodeStateRunning, status.State)
+		assert.Equal(t, []string{"192.168.122.10"}, status.Addresses)
+	}
+
+	_, err = b.Exec(ctx, testNodeName, "sudo", "kubeadm", "join")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192.168.122.10:sudo kubeadm join"}, conn.commands)
+
+	assert.NoError(t, b.CopyFile(ctx, testNodeName, "/tmp/src.sh", "/tmp/dst.sh"))
+	assert.Equal(t, "192.168.122.10:/tmp/src.sh", conn.copies["/tmp/dst.sh"])
+
+	assert.Equal(t, codes.Unimplemented, kindOf(b.Mount(ctx, testNodeName, "/home", "/mnt")).code)
+
+	names, err := b.List(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{testNodeName}, names)
+
+	if assert.NoError(t, b.Stop(ctx, testNodeName)) {
+		status, _ = b.Status(ctx, testNodeName)
+
+		assert.Equal(t, MultipassNodeStateStopped, status.State)
+	}
+
+	assert.NoError(t, b.Delete(ctx, testNodeName))
+	assert.NotContains(t, conn.volumes, testNodeName)
+	assert.NotContains(t, conn.volumes, seed)
+
+	_, err = b.Status(ctx, testNodeName)
+
+	assert.Equal(t, codes.NotFound, kindOf(err).code)
+}
+
+func Test_libvirtBackend_launchFailed(t *testing.T) {
+	conn := newFakeLibvirtConnection()
+	b := &libvirtBackend{
+		config: &LibvirtConfig{},
+		conn:   conn,
+	}
+
+	err := b.Launch(context.Background(), &VMLaunchSpec{Name: testNodeName})
+
+	assert.Equal(t, internalError, errorClass(err))
+
+	err = b.Launch(context.Background(), &VMLaunchSpec{Name: testNodeName, Image: "missing.qcow2"})
+
+	assert.Equal(t, codes.NotFound, kindOf(err).code)
+	assert.Empty(t, conn.domains)
+}
+
+func Test_parseVirsh(t *testing.T) {
+	domiflist := ` Interface   Type      Source    Model    MAC
+-------------------------------------------------------
+ vnet0       network   default   virtio   52:54:00:6b:3c:58
+`
+
+	leases := ` Expiry Time           MAC address         Protocol   IP address           Hostname   Client ID or DUID
+---------------------------------------------------------------------------------------------------------
+ 2020-01-01 10:00:00   52:54:00:6b:3c:58   ipv4       192.168.122.10/24    node1      -
+ 2020-01-01 10:00:00   52:54:00:6b:3c:58   ipv6       fd00::10/64          node1      -
+`
+
+	assert.Equal(t, []string{"52:54:00:6b:3c:58"}, parseVirshDomainMACs(strings.TrimSpace(domiflist)))
+	assert.Equal(t, []libvirtLease{{MAC: "52:54:00:6b:3c:58", Address: "192.168.122.10"}}, parseVirshLeases(strings.TrimSpace(leases)))
+}
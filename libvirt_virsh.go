@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	virshCommandLine       = "virsh"
+	genisoimageCommandLine = "genisoimage"
+)
+
+// virshConnection is the libvirt connection driven by virsh, genisoimage and ssh
+type virshConnection struct {
+	config *LibvirtConfig
+	ssh    *sshRemote
+}
+
+func newVirshConnection(config *LibvirtConfig) *virshConnection {
+	return &virshConnection{
+		config: config,
+		ssh: &sshRemote{
+			user: config.SSHUser,
+			key:  config.SSHKey,
+		},
+	}
+}
+
+func (c *virshConnection) uri() string {
+	if len(c.config.URI) == 0 {
+		return defaultLibvirtURI
+	}
+
+	return c.config.URI
+}
+
+// virsh run the virsh command, a missing domain or volume is reported as not found
+func (c *virshConnection) virsh(ctx context.Context, args ...string) (string, error) {
+	out, err := pipe(ctx, append([]string{virshCommandLine, "--connect", c.uri()}, args...)...)
+
+	if err != nil {
+		lower := strings.ToLower(out)
+
+		if strings.Contains(lower, "failed to get domain") || strings.Contains(lower, "domain not found") || strings.Contains(lower, "storage volume not found") {
+			return out, wrapError(kindNotFound, err, errVMNotFound, args[len(args)-1])
+		}
+
+		return out, wrapError(kindVM, err, errLibvirtCommandFailed, strings.Join(args, " "), err)
+	}
+
+	return out, nil
+}
+
+func (c *virshConnection) CloneVolume(ctx context.Context, pool, base, name string, size int) (string, error) {
+	if _, err := c.virsh(ctx, "vol-clone", "--pool", pool, base, name); err != nil {
+		return "", err
+	}
+
+	if size > 0 {
+		if _, err := c.virsh(ctx, "vol-resize", "--pool", pool, name, fmt.Sprintf("%dM", size)); err != nil {
+			return "", err
+		}
+	}
+
+	return c.virsh(ctx, "vol-path", "--pool", pool, name)
+}
+
+func (c *virshConnection) DeleteVolume(ctx context.Context, pool, name string) error {
+	_, err := c.virsh(ctx, "vol-delete", "--pool", pool, name)
+
+	return err
+}
+
+func (c *virshConnection) BuildSeedISO(ctx context.Context, iso string, userData, metaData []byte) error {
+	dir, err := ioutil.TempDir("", "seed")
+
+	if err != nil {
+		return wrapError(kindInternal, err, errTempFile, err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	if err = ioutil.WriteFile(path.Join(dir, "user-data"), userData, 0644); err != nil {
+		return wrapError(kindInternal, err, errCloudInitWriteError, err)
+	}
+
+	if err = ioutil.WriteFile(path.Join(dir, "meta-data"), metaData, 0644); err != nil {
+		return wrapError(kindInternal, err, errCloudInitWriteError, err)
+	}
+
+	return shell(ctx, genisoimageCommandLine, "-output", iso, "-volid", "cidata", "-joliet", "-rock", path.Join(dir, "user-data"), path.Join(dir, "meta-data"))
+}
+
+func (c *virshConnection) UploadVolume(ctx context.Context, pool, name, file string) error {
+	info, err := os.Stat(file)
+
+	if err != nil {
+		return wrapError(kindInternal, err, errTempFile, err)
+	}
+
+	if _, err = c.virsh(ctx, "vol-create-as", pool, name, strconv.FormatInt(info.Size(), 10), "--format", "raw"); err != nil {
+		return err
+	}
+
+	if _, err = c.virsh(ctx, "vol-upload", "--pool", pool, name, file); err != nil {
+		c.DeleteVolume(ctx, pool, name)
+
+		return err
+	}
+
+	return nil
+}
+
+func (c *virshConnection) DefineDomain(ctx context.Context, xml string) error {
+	file, err := ioutil.TempFile("", "domain-*.xml")
+
+	if err != nil {
+		return wrapError(kindInternal, err, errTempFile, err)
+	}
+
+	defer os.Remove(file.Name())
+
+	_, err = file.WriteString(xml)
+	file.Close()
+
+	if err != nil {
+		return wrapError(kindInternal, err, errTempFile, err)
+	}
+
+	_, err = c.virsh(ctx, "define", file.Name())
+
+	return err
+}
+
+func (c *virshConnection) StartDomain(ctx context.Context, name string) error {
+	_, err := c.virsh(ctx, "start", name)
+
+	return err
+}
+
+func (c *virshConnection) ShutdownDomain(ctx context.Context, name string) error {
+	_, err := c.virsh(ctx, "shutdown", name)
+
+	return err
+}
+
+func (c *virshConnection) DestroyDomain(ctx context.Context, name string) error {
+	_, err := c.virsh(ctx, "destroy", name)
+
+	return err
+}
+
+func (c *virshConnection) UndefineDomain(ctx context.Context, name string) error {
+	_, err := c.virsh(ctx, "undefine", name)
+
+	return err
+}
+
+func (c *virshConnection) DomainState(ctx context.Context, name string) (string, error) {
+	return c.virsh(ctx, "domstate", name)
+}
+
+func (c *virshConnection) DomainMACs(ctx context.Context, name string) ([]string, error) {
+	out, err := c.virsh(ctx, "domiflist", name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parseVirshDomainMACs(out), nil
+}
+
+func (c *virshConnection) NetworkLeases(ctx context.Context, network string) ([]libvirtLease, error) {
+	out, err := c.virsh(ctx, "net-dhcp-leases", network)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parseVirshLeases(out), nil
+}
+
+func (c *virshConnection) ListDomains(ctx context.Context) ([]string, error) {
+	out, err := c.virsh(ctx, "list", "--all", "--name")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(out), nil
+}
+
+func (c *virshConnection) Exec(ctx context.Context, address string, args ...string) (string, error) {
+	return c.ssh.exec(ctx, address, args...)
+}
+
+func (c *virshConnection) Copy(ctx context.Context, address, src, dst string) error {
+	return c.ssh.copy(ctx, address, src, dst)
+}
+
+// virshRows returns the fields of a virsh table, header and separator are skipped
+func virshRows(out string) [][]string {
+	var rows [][]string
+
+	lines := strings.Split(out, "\n")
+
+	for index, line := range lines {
+		if index < 2 || len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		rows = append(rows, strings.Fields(line))
+	}
+
+	return rows
+}
+
+// parseVirshDomainMACs parse virsh domiflist: Interface Type Source Model MAC
+func parseVirshDomainMACs(out string) []string {
+	macs := []string{}
+
+	for _, fields := range virshRows(out) {
+		if len(fields) >= 5 {
+			macs = append(macs, fields[4])
+		}
+	}
+
+	return macs
+}
+
+// parseVirshLeases parse virsh net-dhcp-leases: Expiry date, Expiry time, MAC, Protocol, IP/prefix, Hostname, Client ID
+func parseVirshLeases(out string) []libvirtLease {
+	leases := []libvirtLease{}
+
+	for _, fields := range virshRows(out) {
+		if len(fields) >= 5 && fields[3] == "ipv4" {
+			leases = append(leases, libvirtLease{
+				MAC:     fields[2],
+				Address: strings.Split(fields[4], "/")[0],
+			})
+		}
+	}
+
+	return leases
+}
@@ -0,0 +1,527 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+const (
+	backendLXD = "lxd"
+
+	lxdInstanceContainer      = "container"
+	lxdInstanceVirtualMachine = "virtual-machine"
+
+	defaultLXDImageServer   = "https://cloud-images.ubuntu.com/releases"
+	defaultLXDImageProtocol = "simplestreams"
+	defaultLXDImageAlias    = "lts"
+	defaultLXDStoragePool   = "default"
+)
+
+// lxdSocketPaths are the well known LXD socket, snap first
+var lxdSocketPaths = []string{
+	"/var/snap/lxd/common/lxd/unix.socket",
+	"/var/lib/lxd/unix.socket",
+}
+
+// LXDConfig declare how to reach LXD and how instances are created
+type LXDConfig struct {
+	Socket        string   `json:"socket"`         // Optional, LXD unix socket, the snap or deb socket by default
+	InstanceType  string   `json:"type"`           // Optional, container or virtual-machine, virtual-machine by default
+	ImageServer   string   `json:"image-server"`   // Optional, image server, ubuntu cloud images by default
+	ImageProtocol string   `json:"image-protocol"` // Optional, image server protocol, simplestreams by default
+	StoragePool   string   `json:"storage-pool"`   // Optional, storage pool of the root disk, default by default
+	Profiles      []string `json:"profiles"`       // Optional, profiles applied to instances, default by default
+}
+
+// lxdResponse is the LXD REST response envelope
+type lxdResponse struct {
+	Type       string          `json:"type"`
+	StatusCode int             `json:"status_code"`
+	Operation  string          `json:"operation"`
+	ErrorCode  int             `json:"error_code"`
+	Error      string          `json:"error"`
+	Metadata   json.RawMessage `json:"metadata"`
+}
+
+// lxdOperation is the metadata of a background operation
+type lxdOperation struct {
+	ID         string                 `json:"id"`
+	Status     string                 `json:"status"`
+	StatusCode int                    `json:"status_code"`
+	Err        string                 `json:"err"`
+	Metadata   map[string]interface{} `json:"metadata"`
+}
+
+// lxdInstanceState is the metadata of the instance state
+type lxdInstanceState struct {
+	Status  string                        `json:"status"`
+	Network map[string]lxdInstanceNetwork `json:"network"`
+}
+
+// lxdInstanceNetwork describe a network interface of the instance
+type lxdInstanceNetwork struct {
+	Addresses []struct {
+		Family  string `json:"family"`
+		Address string `json:"address"`
+		Scope   string `json:"scope"`
+	} `json:"addresses"`
+}
+
+// lxdBackend drive the instances with the LXD REST API
+type lxdBackend struct {
+	config *LXDConfig
+	client *http.Client
+}
+
+func newLXDBackend(config *LXDConfig) *lxdBackend {
+	if config == nil {
+		config = &LXDConfig{}
+	}
+
+	socket := config.Socket
+
+	if len(socket) == 0 {
+		socket = lxdSocketPaths[0]
+
+		for _, path := range lxdSocketPaths {
+			if fileExists(path) {
+				socket = path
+				break
+			}
+		}
+	}
+
+	return &lxdBackend{
+		config: config,
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+
+					return dialer.DialContext(ctx, "unix", socket)
+				},
+			},
+		},
+	}
+}
+
+func (b *lxdBackend) instanceType() string {
+	if len(b.config.InstanceType) == 0 {
+		return lxdInstanceVirtualMachine
+	}
+
+	return b.config.InstanceType
+}
+
+func (b *lxdBackend) imageSource(image string) map[string]interface{} {
+	server := b.config.ImageServer
+	protocol := b.config.ImageProtocol
+
+	if len(server) == 0 {
+		server = defaultLXDImageServer
+	}
+
+	if len(protocol) == 0 {
+		protocol = defaultLXDImageProtocol
+	}
+
+	if len(image) == 0 {
+		image = defaultLXDImageAlias
+	}
+
+	return map[string]interface{}{
+		"type":     "image",
+		"mode":     "pull",
+		"server":   server,
+		"protocol": protocol,
+		"alias":    image,
+	}
+}
+
+// do send the request once, body is marshalled in json unless it's a reader
+func (b *lxdBackend) do(ctx context.Context, method, path string, body interface{}, header map[string]string) (*lxdResponse, error) {
+	var reader io.Reader
+
+	switch v := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = v
+	default:
+		buffer, err := json.Marshal(body)
+
+		if err != nil {
+			return nil, wrapError(kindInternal, err, errLXDRequestFailed, method, path, err)
+		}
+
+		reader = bytes.NewReader(buffer)
+	}
+
+	req, err := http.NewRequest(method, "http://lxd"+path, reader)
+
+	if err != nil {
+		return nil, wrapError(kindInternal, err, errLXDRequestFailed, method, path, err)
+	}
+
+	req = req.WithContext(ctx)
+
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+
+	if _, ok := body.(io.Reader); !ok && body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	glog.V(5).Infof("LXD:%s %s", method, path)
+
+	resp, err := b.client.Do(req)
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, wrapError(kindTimeout, ctx.Err(), errLXDRequestFailed, method, path, ctx.Err())
+		}
+
+		return nil, wrapError(kindVM, err, errLXDRequestFailed, method, path, err)
+	}
+
+	defer resp.Body.Close()
+
+	var response lxdResponse
+
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, wrapError(kindInternal, err, errLXDRequestFailed, method, path, err)
+	}
+
+	if response.Type == "error" {
+		switch response.ErrorCode {
+		case http.StatusNotFound:
+			return nil, newError(kindNotFound, errLXDRequestFailed, method, path, response.Error)
+		case http.StatusConflict:
+			return nil, newError(kindAlreadyExists, errLXDRequestFailed, method, path, response.Error)
+		}
+
+		return nil, newError(kindVM, errLXDRequestFailed, method, path, response.Error)
+	}
+
+	return &response, nil
+}
+
+// request send the request, retry transient failures like the daemon restarting and wait the end of background operation
+func (b *lxdBackend) request(ctx context.Context, method, path string, body interface{}) (*lxdResponse, error) {
+	var response *lxdResponse
+
+	err := commandRetryPolicy.retry(ctx, []string{method, path}, func() (string, error) {
+		var err error
+
+		if response, err = b.do(ctx, method, path, body, nil); err != nil {
+			return err.Error(), err
+		}
+
+		return "", nil
+	})
+
+	if err != nil {
+		return nil, wrapError(kindVM, err, "%v", err)
+	}
+
+	if response.Type == "async" {
+		return b.wait(ctx, response.Operation)
+	}
+
+	return response, nil
+}
+
+// wait returns when the background operation is done
+func (b *lxdBackend) wait(ctx context.Context, operation string) (*lxdResponse, error) {
+	var op lxdOperation
+
+	response, err := b.do(ctx, http.MethodGet, operation+"/wait?timeout=-1", nil, nil)
+
+	if err != nil {
+		return nil, wrapError(kindVM, err, "%v", err)
+	}
+
+	if err = json.Unmarshal(response.Metadata, &op); err != nil {
+		return nil, wrapError(kindInternal, err, errLXDRequestFailed, http.MethodGet, operation, err)
+	}
+
+	if op.Status != "Success" {
+		return nil, newError(kindVM, errLXDOperationFailed, operation, op.Err)
+	}
+
+	return response, nil
+}
+
+func (b *lxdBackend) instancePath(name string) string {
+	return "/1.0/instances/" + url.PathEscape(name)
+}
+
+func (b *lxdBackend) Launch(ctx context.Context, spec *VMLaunchSpec) error {
+	config := map[string]string{}
+	profiles := b.config.Profiles
+	pool := b.config.StoragePool
+
+	if len(profiles) == 0 {
+		profiles = []string{"default"}
+	}
+
+	if len(pool) == 0 {
+		pool = defaultLXDStoragePool
+	}
+
+	if spec.CPU > 0 {
+		config["limits.cpu"] = strconv.Itoa(spec.CPU)
+	}
+
+	if spec.Memory > 0 {
+		config["limits.memory"] = fmt.Sprintf("%dMiB", spec.Memory)
+	}
+
+	if spec.hasUserData() {
+		userData, err := spec.userData()
+
+		if err != nil {
+			return err
+		}
+
+		config["user.user-data"] = string(userData)
+	}
+
+	devices := map[string]map[string]string{}
+
+	if spec.Disk > 0 {
+		devices["root"] = map[string]string{
+			"type": "disk",
+			"path": "/",
+			"pool": pool,
+			"size": fmt.Sprintf("%dMiB", spec.Disk),
+		}
+	}
+
+	instance := map[string]interface{}{
+		"name":     spec.Name,
+		"type":     b.instanceType(),
+		"source":   b.imageSource(spec.Image),
+		"config":   config,
+		"devices":  devices,
+		"profiles": profiles,
+	}
+
+	// The image of the machine type architecture, LXD fails if the host can't run it
+	if len(spec.Arch) > 0 {
+		instance["architecture"] = kernelArch(spec.Arch)
+	}
+
+	if _, err := b.request(ctx, http.MethodPost, "/1.0/instances", instance); err != nil {
+		return err
+	}
+
+	return b.Start(ctx, spec.Name)
+}
+
+func (b *lxdBackend) changeState(ctx context.Context, name, action string) error {
+	_, err := b.request(ctx, http.MethodPut, b.instancePath(name)+"/state", map[string]interface{}{
+		"action":  action,
+		"timeout": 30,
+	})
+
+	return err
+}
+
+func (b *lxdBackend) Start(ctx context.Context, name string) error {
+	return b.changeState(ctx, name, "start")
+}
+
+func (b *lxdBackend) Stop(ctx context.Context, name string) error {
+	return b.changeState(ctx, name, "stop")
+}
+
+func (b *lxdBackend) Delete(ctx context.Context, name string) error {
+	_, err := b.request(ctx, http.MethodDelete, b.instancePath(name), nil)
+
+	return err
+}
+
+func (b *lxdBackend) Status(ctx context.Context, name string) (*VMStatus, error) {
+	var state lxdInstanceState
+
+	response, err := b.request(ctx, http.MethodGet, b.instancePath(name)+"/state", nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(response.Metadata, &state); err != nil {
+		return nil, wrapError(kindInternal, err, errGetVMInfoFailed, name, err)
+	}
+
+	status := &VMStatus{
+		Addresses: []string{},
+	}
+
+	switch strings.ToUpper(state.Status) {
+	case "RUNNING":
+		status.State = MultipassNodeStateRunning
+	case "STOPPED":
+		status.State = MultipassNodeStateStopped
+	default:
+		glog.Infof(errVMStateUndefined, name, state.Status)
+
+		status.State = MultipassNodeStateUndefined
+	}
+
+	for device, network := range state.Network {
+		if device == "lo" {
+			continue
+		}
+
+		for _, address := range network.Addresses {
+			if address.Family == "inet" && address.Scope == "global" {
+				status.Addresses = append(status.Addresses, address.Address)
+			}
+		}
+	}
+
+	return status, nil
+}
+
+// output read the exec log recorded by LXD
+func (b *lxdBackend) output(ctx context.Context, path string) string {
+	req, err := http.NewRequest(http.MethodGet, "http://lxd"+path, nil)
+
+	if err != nil {
+		return ""
+	}
+
+	resp, err := b.client.Do(req.WithContext(ctx))
+
+	if err != nil {
+		return ""
+	}
+
+	defer resp.Body.Close()
+
+	out, _ := ioutil.ReadAll(resp.Body)
+
+	return strings.TrimSpace(string(out))
+}
+
+func (b *lxdBackend) Exec(ctx context.Context, name string, args ...string) (string, error) {
+	var op lxdOperation
+
+	response, err := b.request(ctx, http.MethodPost, b.instancePath(name)+"/exec", map[string]interface{}{
+		"command":            args,
+		"wait-for-websocket": false,
+		"interactive":        false,
+		"record-output":      true,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	if err = json.Unmarshal(response.Metadata, &op); err != nil {
+		return "", wrapError(kindInternal, err, errLXDRequestFailed, http.MethodPost, name, err)
+	}
+
+	var stdout, stderr string
+
+	if logs, ok := op.Metadata["output"].(map[string]interface{}); ok {
+		if path, ok := logs["1"].(string); ok {
+			stdout = b.output(ctx, path)
+		}
+
+		if path, ok := logs["2"].(string); ok {
+			stderr = b.output(ctx, path)
+		}
+	}
+
+	if code, ok := op.Metadata["return"].(float64); ok && code != 0 {
+		return stderr, newError(kindVM, errLXDExecFailed, strings.Join(args, " "), name, int(code), stderr)
+	}
+
+	return stdout, nil
+}
+
+func (b *lxdBackend) CopyFile(ctx context.Context, name, src, dst string) error {
+	content, err := ioutil.ReadFile(src)
+
+	if err != nil {
+		return wrapError(kindInternal, err, errLXDRequestFailed, http.MethodPost, src, err)
+	}
+
+	info, err := os.Stat(src)
+
+	if err != nil {
+		return wrapError(kindInternal, err, errLXDRequestFailed, http.MethodPost, src, err)
+	}
+
+	path := b.instancePath(name) + "/files?path=" + url.QueryEscape(dst)
+
+	_, err = b.do(ctx, http.MethodPost, path, bytes.NewReader(content), map[string]string{
+		"Content-Type": "application/octet-stream",
+		"X-LXD-type":   "file",
+		"X-LXD-uid":    "0",
+		"X-LXD-gid":    "0",
+		"X-LXD-mode":   fmt.Sprintf("%04o", info.Mode().Perm()),
+		"X-LXD-write":  "overwrite",
+	})
+
+	if err != nil {
+		return wrapError(kindVM, err, "%v", err)
+	}
+
+	return nil
+}
+
+func (b *lxdBackend) Mount(ctx context.Context, name, hostPath, guestPath string) error {
+	device := "mount" + strings.ReplaceAll(guestPath, "/", "-")
+
+	_, err := b.request(ctx, http.MethodPatch, b.instancePath(name), map[string]interface{}{
+		"devices": map[string]map[string]string{
+			device: {
+				"type":   "disk",
+				"source": hostPath,
+				"path":   guestPath,
+			},
+		},
+	})
+
+	return err
+}
+
+func (b *lxdBackend) List(ctx context.Context) ([]string, error) {
+	var instances []string
+
+	response, err := b.request(ctx, http.MethodGet, "/1.0/instances", nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(response.Metadata, &instances); err != nil {
+		return nil, wrapError(kindInternal, err, errLXDRequestFailed, http.MethodGet, "/1.0/instances", err)
+	}
+
+	names := make([]string, 0, len(instances))
+
+	for _, instance := range instances {
+		if name, err := url.PathUnescape(instance[strings.LastIndex(instance, "/")+1:]); err == nil {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+type fakeLXDInstance struct {
+	Name    string                       `json:"name"`
+	Type    string                       `json:"type"`
+	Arch    string                       `json:"architecture"`
+	Config  map[string]string            `json:"config"`
+	Devices map[string]map[string]string `json:"devices"`
+	Status  string                       `json:"status"`
+	Files   map[string]string            `json:"-"`
+}
+
+// fakeLXDServer is an in memory LXD REST API listening on a unix socket
+type fakeLXDServer struct {
+	sync.Mutex
+	server     *httptest.Server
+	socket     string
+	instances  map[string]*fakeLXDInstance
+	operations map[string]map[string]interface{}
+	logs       map[string]string
+	commands   [][]string
+}
+
+func newFakeLXDServer(t *testing.T) *fakeLXDServer {
+	dir, err := ioutil.TempDir("", "lxd")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &fakeLXDServer{
+		socket:     path.Join(dir, "unix.socket"),
+		instances:  make(map[string]*fakeLXDInstance),
+		operations: make(map[string]map[string]interface{}),
+		logs:       make(map[string]string),
+	}
+
+	listener, err := net.Listen("unix", f.socket)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.server = httptest.NewUnstartedServer(http.HandlerFunc(f.handle))
+	f.server.Listener = listener
+	f.server.Start()
+
+	t.Cleanup(func() {
+		f.server.Close()
+		os.RemoveAll(dir)
+	})
+
+	return f
+}
+
+func (f *fakeLXDServer) reply(w http.ResponseWriter, metadata interface{}) {
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"type":        "sync",
+		"status_code": 200,
+		"metadata":    metadata,
+	})
+}
+
+func (f *fakeLXDServer) error(w http.ResponseWriter, code int, message string) {
+	w.WriteHeader(code)
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"type":       "error",
+		"error_code": code,
+		"error":      message,
+	})
+}
+
+func (f *fakeLXDServer) async(w http.ResponseWriter, metadata map[string]interface{}) {
+	id := fmt.Sprintf("op-%d", len(f.operations))
+
+	f.operations[id] = map[string]interface{}{
+		"id":          id,
+		"status":      "Success",
+		"status_code": 200,
+		"metadata":    metadata,
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"type":      "async",
+		"operation": "/1.0/operations/" + id,
+	})
+}
+
+func (f *fakeLXDServer) handle(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/1.0/"), "/")
+
+	if parts[0] == "operations" && len(parts) == 3 {
+		if op := f.operations[parts[1]]; op != nil {
+			f.reply(w, op)
+		} else {
+			f.error(w, http.StatusNotFound, "Operation not found")
+		}
+
+		return
+	}
+
+	if parts[0] != "instances" {
+		f.error(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			names := []string{}
+
+			for name := range f.instances {
+				names = append(names, "/1.0/instances/"+name)
+			}
+
+			f.reply(w, names)
+		case http.MethodPost:
+			var instance fakeLXDInstance
+
+			json.NewDecoder(r.Body).Decode(&instance)
+
+			if f.instances[instance.Name] != nil {
+				f.error(w, http.StatusConflict, "Instance already exists")
+				return
+			}
+
+			instance.Status = "Stopped"
+			instance.Files = make(map[string]string)
+
+			f.instances[instance.Name] = &instance
+			f.async(w, nil)
+		}
+
+		return
+	}
+
+	instance := f.instances[parts[1]]
+
+	if len(parts) > 2 && parts[2] == "logs" {
+		fmt.Fprint(w, f.logs[r.URL.Path])
+		return
+	}
+
+	if instance == nil {
+		f.error(w, http.StatusNotFound, "Instance not found")
+		return
+	}
+
+	switch {
+	case len(parts) == 2 && r.Method == http.MethodDelete:
+		delete(f.instances, instance.Name)
+		f.async(w, nil)
+
+	case len(parts) == 2 && r.Method == http.MethodPatch:
+		var patch fakeLXDInstance
+
+		json.NewDecoder(r.Body).Decode(&patch)
+
+		for name, device := range patch.Devices {
+			instance.Devices[name] = device
+		}
+
+		f.reply(w, nil)
+
+	case parts[2] == "state" && r.Method == http.MethodPut:
+		var state map[string]interface{}
+
+		json.NewDecoder(r.Body).Decode(&state)
+
+		if state["action"] == "start" {
+			instance.Status = "Running"
+		} else {
+			instance.Status = "Stopped"
+		}
+
+		f.async(w, nil)
+
+	case parts[2] == "state":
+		f.reply(w, map[string]interface{}{
+			"status": instance.Status,
+			"network": map[string]interface{}{
+				"lo": map[string]interface{}{
+					"addresses": []map[string]string{{"family": "inet", "address": "127.0.0.1", "scope": "local"}},
+				},
+				"eth0": map[string]interface{}{
+					"addresses": []map[string]string{
+						{"family": "inet", "address": "10.0.0.2", "scope": "global"},
+						{"family": "inet6", "address": "fe80::1", "scope": "link"},
+					},
+				},
+			},
+		})
+
+	case parts[2] == "exec":
+		var exec struct {
+			Command []string `json:"command"`
+		}
+
+		json.NewDecoder(r.Body).Decode(&exec)
+
+		f.commands = append(f.commands, exec.Command)
+
+		id := len(f.commands)
+		stdout := fmt.Sprintf("/1.0/instances/%s/logs/exec_%d.stdout", instance.Name, id)
+		stderr := fmt.Sprintf("/1.0/instances/%s/logs/exec_%d.stderr", instance.Name, id)
+		code := 0
+
+		if exec.Command[0] == "false" {
+			code = 1
+			f.logs[stderr] = "failed"
+		} else {
+			f.logs[stdout] = strings.Join(exec.Command, " ")
+		}
+
+		f.async(w, map[string]interface{}{
+			"return": code,
+			"output": map[string]string{"1": stdout, "2": stderr},
+		})
+
+	case parts[2] == "files":
+		content, _ := ioutil.ReadAll(r.Body)
+
+		instance.Files[r.URL.Query().Get("path")] = string(content)
+
+		f.reply(w, nil)
+
+	default:
+		f.error(w, http.StatusNotFound, "not found")
+	}
+}
+
+func Test_lxdBackend(t *testing.T) {
+	f := newFakeLXDServer(t)
+	b := newLXDBackend(&LXDConfig{Socket: f.socket})
+	ctx := context.Background()
+
+	spec := &VMLaunchSpec{
+		Name:   testNodeName,
+		Memory: 2048,
+		CPU:    2,
+		Disk:   5120,
+		Arch:   "arm64",
+		CloudInit: map[string]interface{}{
+			"package_update": true,
+		},
+	}
+
+	if !assert.NoError(t, b.Launch(ctx, spec)) {
+		return
+	}
+
+	instance := f.instances[testNodeName]
+
+	assert.Equal(t, lxdInstanceVirtualMachine, instance.Type)
+	assert.Equal(t, "aarch64", instance.Arch)
+	assert.Equal(t, "2", instance.Config["limits.cpu"])
+	assert.Equal(t, "2048MiB", instance.Config["limits.memory"])
+	assert.Equal(t, "5120MiB", instance.Devices["root"]["size"])
+	assert.True(t, strings.HasPrefix(instance.Config["user.user-data"], "#cloud-config\n"))
+	assert.Contains(t, instance.Config["user.user-data"], "package_update: true")
+
+	err := b.Launch(ctx, spec)
+
+	assert.Equal(t, codes.AlreadyExists, kindOf(err).code)
+
+	status, err := b.Status(ctx, testNodeName)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, MultipassNodeStateRunning, status.State)
+		assert.Equal(t, []string{"10.0.0.2"}, status.Addresses)
+	}
+
+	out, err := b.Exec(ctx, testNodeName, "echo", "hello")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "echo hello", out)
+
+	out, err = b.Exec(ctx, testNodeName, "false")
+
+	assert.Error(t, err)
+	assert.Equal(t, "failed", out)
+
+	src, _ := ioutil.TempFile("", "lxd-file")
+	src.WriteString("#!/bin/bash")
+	src.Close()
+
+	defer os.Remove(src.Name())
+
+	if assert.NoError(t, b.CopyFile(ctx, testNodeName, src.Name(), "/tmp/script.sh")) {
+		assert.Equal(t, "#!/bin/bash", instance.Files["/tmp/script.sh"])
+	}
+
+	if assert.NoError(t, b.Mount(ctx, testNodeName, "/home", "/mnt/home")) {
+		assert.Equal(t, "/home", instance.Devices["mount-mnt-home"]["source"])
+	}
+
+	names, err := b.List(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{testNodeName}, names)
+
+	if assert.NoError(t, b.Stop(ctx, testNodeName)) {
+		status, _ = b.Status(ctx, testNodeName)
+
+		assert.Equal(t, MultipassNodeStateStopped, status.State)
+	}
+
+	assert.NoError(t, b.Delete(ctx, testNodeName))
+
+	_, err = b.Status(ctx, testNodeName)
+
+	assert.Equal(t, codes.NotFound, kindOf(err).code)
+}
+
+func Test_lxdBackend_unreachable(t *testing.T) {
+	b := newLXDBackend(&LXDConfig{Socket: "/nonexistent/unix.socket"})
+
+	_, err := b.Status(context.Background(), testNodeName)
+
+	if assert.Error(t, err) {
+		assert.Equal(t, cloudProviderError, errorClass(err))
+	}
+}
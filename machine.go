@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	nodeLabelInstanceType = "node.kubernetes.io/instance-type"
+	nodeLabelArch         = "kubernetes.io/arch"
+	nodeLabelOS           = "kubernetes.io/os"
+	nodeLabelHostname     = "kubernetes.io/hostname"
+	defaultMaxPods        = 110
+)
+
+// architecture returns the declared architecture or the host one
+func (m *MachineCharacteristic) architecture() string {
+	if len(m.Architecture) > 0 {
+		return m.Architecture
+	}
+
+	return runtime.GOARCH
+}
+
+// nodeLabels returns the labels stamped on each node using this machine type
+func (m *MachineCharacteristic) nodeLabels(machineType string) map[string]string {
+	labels := map[string]string{
+		nodeLabelArch: m.architecture(),
+		nodeLabelOS:   "linux",
+	}
+
+	if len(machineType) > 0 {
+		labels[nodeLabelInstanceType] = machineType
+	}
+
+	for k, v := range m.Labels {
+		labels[k] = v
+	}
+
+	return labels
+}
+
+// capacity returns the kubernetes resources exposed by this machine type
+func (m *MachineCharacteristic) capacity() apiv1.ResourceList {
+	capacity := apiv1.ResourceList{
+		apiv1.ResourceCPU:              *resource.NewQuantity(int64(m.Vcpu), resource.DecimalSI),
+		apiv1.ResourceMemory:           *resource.NewQuantity(int64(m.Memory)*1024*1024, resource.BinarySI),
+		apiv1.ResourceEphemeralStorage: *resource.NewQuantity(int64(m.Disk)*1024*1024, resource.BinarySI),
+		apiv1.ResourcePods:             *resource.NewQuantity(defaultMaxPods, resource.DecimalSI),
+	}
+
+	for name, value := range m.Resources {
+		capacity[apiv1.ResourceName(name)] = *resource.NewQuantity(value, resource.DecimalSI)
+	}
+
+	return capacity
+}
+
+// String describe the machine type
+func (m *MachineCharacteristic) String() string {
+	if len(m.Description) > 0 {
+		return m.Description
+	}
+
+	return fmt.Sprintf("%d vcpus, %dM memory, %dM disk", m.Vcpu, m.Memory, m.Disk)
+}
+
+// sortedMachineTypes returns machine types names ordered from the smallest to the largest
+func sortedMachineTypes(machines map[string]*MachineCharacteristic) []string {
+	machineTypes := make([]string, 0, len(machines))
+
+	for name := range machines {
+		machineTypes = append(machineTypes, name)
+	}
+
+	sort.Slice(machineTypes, func(i, j int) bool {
+		m1 := machines[machineTypes[i]]
+		m2 := machines[machineTypes[j]]
+
+		if m1.Vcpu != m2.Vcpu {
+			return m1.Vcpu < m2.Vcpu
+		}
+
+		if m1.Memory != m2.Memory {
+			return m1.Memory < m2.Memory
+		}
+
+		if m1.Disk != m2.Disk {
+			return m1.Disk < m2.Disk
+		}
+
+		return machineTypes[i] < machineTypes[j]
+	})
+
+	return machineTypes
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+var testMachines = map[string]*MachineCharacteristic{
+	"extra-large": {
+		Memory: 16384,
+		Vcpu:   8,
+		Disk:   51200,
+		Price:  0.4,
+	},
+	"large": {
+		Memory: 8192,
+		Vcpu:   4,
+		Disk:   20480,
+		Price:  0.2,
+	},
+	"medium": {
+		Memory: 4096,
+		Vcpu:   2,
+		Disk:   10240,
+		Price:  0.1,
+	},
+	"tiny": {
+		Memory: 2048,
+		Vcpu:   2,
+		Disk:   5120,
+		Price:  0.05,
+		Resources: map[string]int64{
+			"example.com/fpga": 1,
+		},
+		Labels: map[string]string{
+			"tier": "small",
+		},
+	},
+}
+
+func Test_sortedMachineTypes(t *testing.T) {
+	want := []string{
+		"tiny",
+		"medium",
+		"large",
+		"extra-large",
+	}
+
+	if got := sortedMachineTypes(testMachines); !reflect.DeepEqual(got, want) {
+		t.Errorf("sortedMachineTypes() = %v, want %v", got, want)
+	}
+}
+
+func Test_multipassNodeGroup_templateNode(t *testing.T) {
+	ng := newTestNodeGroup("tiny")
+
+	ng.NodeLabels = map[string]string{
+		"monitor": "true",
+	}
+
+	node := ng.templateNode()
+
+	assert.Equal(t, "tiny", node.Labels[nodeLabelInstanceType])
+	assert.Equal(t, "small", node.Labels["tier"])
+	assert.Equal(t, "true", node.Labels["monitor"])
+	assert.Equal(t, testGroupID, node.Labels[nodeLabelGroupName])
+	assert.Equal(t, ng.providerIDForNode(node.Name), node.Spec.ProviderID)
+
+	cpu := node.Status.Allocatable[apiv1.ResourceCPU]
+	memory := node.Status.Allocatable[apiv1.ResourceMemory]
+	fpga := node.Status.Allocatable["example.com/fpga"]
+
+	assert.Equal(t, int64(2), cpu.Value())
+	assert.Equal(t, int64(2048*1024*1024), memory.Value())
+	assert.Equal(t, int64(1), fpga.Value())
+}
@@ -44,7 +44,7 @@ func main() {
 	var cacheStats os.FileInfo
 
 	if tmpDir, err = os.UserCacheDir(); err != nil {
-		glog.Fatalf("Unable to find user cache", err)
+		glog.Fatalf("Unable to find user cache, reason: %v", err)
 	}
 
 	versionPtr := flag.Bool("version", false, "Give the version")
@@ -75,23 +75,67 @@ func main() {
 			glog.Fatalf("failed to open config file:%s, error:%v", *configPtr, err)
 		}
 
+		// Capabilities not declared in config file stay enabled
+		config.Capabilities = defaultCapabilities()
+
 		decoder := json.NewDecoder(file)
 		err = decoder.Decode(&config)
 		if err != nil {
 			glog.Fatalf("failed to decode config file:%s, error:%v", *configPtr, err)
 		}
 
-		if config.Optionals == nil {
-			config.Optionals = &MultipassServerOptionals{
-				Pricing:                  false,
-				GetAvailableMachineTypes: false,
-				NewNodeGroup:             false,
-				TemplateNodeInfo:         false,
-				Create:                   false,
-				Delete:                   false,
+		config.Capabilities.applyOptionals(config.Optionals)
+
+		commandRetryPolicy = config.Retry
+
+		if len(config.CloudInit) > 0 && len(config.CloudInitTemplate) > 0 {
+			glog.Fatalf("cloud-init and cloud-init-template are exclusive")
+		}
+
+		if err := validateCloudInitTemplate(config.CloudInitTemplate); err != nil {
+			glog.Fatalf("invalid cloud-init template, error:%v", err)
+		}
+
+		if err := config.Bootstrap.validate(); err != nil {
+			glog.Fatalf("invalid bootstrap configuration, error:%v", err)
+		}
+
+		for nodeGroupID, scaleDown := range config.ScaleDown {
+			if err := scaleDown.validate(nodeGroupID); err != nil {
+				glog.Fatalf("invalid scale down policy, error:%v", err)
+			}
+		}
+
+		for nodeGroupID, kubelet := range config.Kubelet {
+			if err := kubelet.validate(nodeGroupID, config.Bootstrap); err != nil {
+				glog.Fatalf("invalid kubelet configuration, error:%v", err)
+			}
+		}
+
+		for nodeGroupID, gates := range config.ReadinessGates {
+			for _, gate := range gates {
+				if err := gate.validate(nodeGroupID); err != nil {
+					glog.Fatalf("invalid readiness gate, error:%v", err)
+				}
 			}
 		}
 
+		if err := config.ControlPlane.validate(&config); err != nil {
+			glog.Fatalf("invalid control plane configuration, error:%v", err)
+		}
+
+		backend, err := newVMBackend(&config, *cachePtr)
+
+		if err != nil {
+			glog.Fatalf("failed to create the VM backend, error:%v", err)
+		}
+
+		hosts, err := newHypervisorHosts(&config, backend, *cachePtr)
+
+		if err != nil {
+			glog.Fatalf("failed to declare the hypervisor hosts, error:%v", err)
+		}
+
 		kubeAdmConfig := &apigrc.KubeAdmConfig{
 			KubeAdmAddress:        config.KubeAdm.Address,
 			KubeAdmToken:          config.KubeAdm.Token,
@@ -99,15 +143,24 @@ func main() {
 			KubeAdmExtraArguments: config.KubeAdm.ExtraArguments,
 		}
 
+		// Limits are replaced by the autoscaler ones on connect, without limits nothing is enforced
+		resourceLimiter := config.Limits
+
+		if resourceLimiter == nil {
+			resourceLimiter = &ResourceLimiter{
+				MinLimits: map[string]int64{},
+				MaxLimits: map[string]int64{},
+			}
+		}
+
 		if !phSaveState || !fileExists(phSavedState) {
 			phMultipassServer = &MultipassServer{
-				ResourceLimiter: &ResourceLimiter{
-					map[string]int64{ResourceNameCores: 1, ResourceNameMemory: 10000000},
-					map[string]int64{ResourceNameCores: 5, ResourceNameMemory: 100000000},
-				},
+				ResourceLimiter:      resourceLimiter,
 				Configuration:        config,
 				Groups:               make(map[string]*MultipassNodeGroup),
 				KubeAdmConfiguration: kubeAdmConfig,
+				Backend:              backend,
+				Hosts:                hosts,
 			}
 
 			if phSaveState {
@@ -116,7 +169,10 @@ func main() {
 				}
 			}
 		} else {
-			phMultipassServer = &MultipassServer{}
+			phMultipassServer = &MultipassServer{
+				Backend: backend,
+				Hosts:   hosts,
+			}
 
 			if err := phMultipassServer.load(phSavedState); err != nil {
 				log.Fatalf(errFailedToLoadServerState, err)
@@ -124,6 +180,7 @@ func main() {
 		}
 
 		phMultipassServer.CacheDir = *cachePtr
+		phMultipassServer.capabilities().log()
 
 		glog.Infof("Start listening server %s on %s", phVersion, config.Listen)
 
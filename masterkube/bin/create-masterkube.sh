@@ -449,13 +449,13 @@ if [ "$CUSTOM_IMAGE" = "YES" ]; then
         "image": "$LAUNCH_IMAGE_URL",
         "vm-provision": true,
         "kubeconfig": "$KUBECONFIG",
-        "optionals": {
-            "pricing": false,
-            "getAvailableMachineTypes": false,
-            "newNodeGroup": false,
-            "templateNodeInfo": false,
-            "createNodeGroup": false,
-            "deleteNodeGroup": false
+        "capabilities": {
+            "pricing": true,
+            "getAvailableMachineTypes": true,
+            "newNodeGroup": true,
+            "templateNodeInfo": true,
+            "create": true,
+            "delete": true
         },
         "kubeadm": {
             "address": "$MASTER_IP",
@@ -499,13 +499,13 @@ else
         "image": "focal",
         "vm-provision": true,
         "kubeconfig": "$KUBECONFIG",
-        "optionals": {
-            "pricing": false,
-            "getAvailableMachineTypes": false,
-            "newNodeGroup": false,
-            "templateNodeInfo": false,
-            "createNodeGroup": false,
-            "deleteNodeGroup": false
+        "capabilities": {
+            "pricing": true,
+            "getAvailableMachineTypes": true,
+            "newNodeGroup": true,
+            "templateNodeInfo": true,
+            "create": true,
+            "delete": true
         },
         "kubeadm": {
             "address": "$MASTER_IP",
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+// VMDiskInfo describe VM disk usage
+type VMDiskInfo struct {
+	Total string `json:"total"`
+	Used  string `json:"used"`
+}
+
+// VMMemoryInfo describe VM mem infos
+type VMMemoryInfo struct {
+	Total int `json:"total"`
+	Used  int `json:"used"`
+}
+
+// VMMountInfos describe VM mounts point between host and guest
+type VMMountInfos struct {
+	GIDMappings []string `json:"gid_mappings"`
+	UIDMappings []string `json:"uid_mappings"`
+	SourcePath  string   `json:"source_path"`
+}
+
+// VMInfos describe VM global infos
+type VMInfos struct {
+	Disks        map[string]*VMDiskInfo  `json:"disks"`
+	ImageHash    string                  `json:"image_hash"`
+	ImageRelease string                  `json:"image_release"`
+	Ipv4         []string                `json:"ipv4"`
+	Load         []float64               `json:"load"`
+	Memory       *VMMemoryInfo           `json:"memory"`
+	Mounts       map[string]VMMountInfos `json:"mounts"`
+	Release      string                  `json:"release"`
+	State        string                  `json:"state"`
+}
+
+// MultipassVMInfos describe all about VMs
+type MultipassVMInfos struct {
+	Errors []interface{}       `json:"errors"`
+	Info   map[string]*VMInfos `json:"info"`
+}
+
+// MultipassVMListItem describe a VM returned by multipass list
+type MultipassVMListItem struct {
+	Name    string   `json:"name"`
+	State   string   `json:"state"`
+	Ipv4    []string `json:"ipv4"`
+	Release string   `json:"release"`
+}
+
+// MultipassVMList describe all VMs returned by multipass list
+type MultipassVMList struct {
+	List []*MultipassVMListItem `json:"list"`
+}
+
+// multipassBackend drive the VMs with the multipass command line, locally or on a host reachable by ssh
+type multipassBackend struct {
+	cacheDir string
+	address  string
+	remote   *sshRemote
+}
+
+func newMultipassBackend(cacheDir string) *multipassBackend {
+	return &multipassBackend{
+		cacheDir: cacheDir,
+	}
+}
+
+// newRemoteMultipassBackend returns a backend running multipass on address over ssh
+func newRemoteMultipassBackend(cacheDir, address string, remote *sshRemote) *multipassBackend {
+	return &multipassBackend{
+		cacheDir: cacheDir,
+		address:  address,
+		remote:   remote,
+	}
+}
+
+// command returns the command line, wrapped in ssh for a remote host
+func (b *multipassBackend) command(args ...string) []string {
+	if b.remote == nil {
+		return args
+	}
+
+	return b.remote.command(b.address, args...)
+}
+
+// upload copy a local file on the remote host, it returns the file name seen by multipass and the cleanup
+func (b *multipassBackend) upload(ctx context.Context, fileName string) (string, func(), error) {
+	if b.remote == nil {
+		return fileName, func() {}, nil
+	}
+
+	// Relative to the ssh user home, readable by multipass snap
+	remoteName := path.Base(fileName)
+
+	if err := b.remote.copy(ctx, b.address, fileName, remoteName); err != nil {
+		return "", nil, err
+	}
+
+	return remoteName, func() {
+		if _, err := b.remote.exec(context.Background(), b.address, "rm", "-f", remoteName); err != nil {
+			glog.Warningf("Unable to remove: %s on host: %s, reason: %v", remoteName, b.address, err)
+		}
+	}, nil
+}
+
+// multipassState convert the multipass state name
+func multipassState(name, state string) MultipassNodeState {
+	switch strings.ToUpper(state) {
+	case "RUNNING":
+		return MultipassNodeStateRunning
+	case "STOPPED":
+		return MultipassNodeStateStopped
+	case "DELETED":
+		return MultipassNodeStateDeleted
+	}
+
+	glog.Infof(errVMStateUndefined, name, state)
+
+	return MultipassNodeStateUndefined
+}
+
+func (b *multipassBackend) Launch(ctx context.Context, spec *VMLaunchSpec) error {
+	var args = []string{
+		multipassCommandLine,
+		launchArgument,
+		nameArgument,
+		spec.Name,
+	}
+
+	/*
+		Append VM attributes Memory,cpus, hard drive size....
+	*/
+	if spec.Memory > 0 {
+		args = append(args, fmt.Sprintf("--mem=%dM", spec.Memory))
+	}
+
+	if spec.CPU > 0 {
+		args = append(args, fmt.Sprintf("--cpus=%d", spec.CPU))
+	}
+
+	if spec.Disk > 0 {
+		args = append(args, fmt.Sprintf("--disk=%dM", spec.Disk))
+	}
+
+	// If cloud-init file is present
+	if spec.hasUserData() {
+		cloudInitFile, err := writeCloudInitFile(b.cacheDir, spec)
+
+		if err != nil {
+			return err
+		}
+
+		defer os.Remove(cloudInitFile)
+
+		remoteFile, cleanup, err := b.upload(ctx, cloudInitFile)
+
+		if err != nil {
+			return err
+		}
+
+		defer cleanup()
+
+		args = append(args, fmt.Sprintf("--cloud-init=%s", remoteFile))
+	}
+
+	// If an image/url image
+	if len(spec.Image) > 0 {
+		args = append(args, spec.Image)
+	}
+
+	return shell(ctx, b.command(args...)...)
+}
+
+func (b *multipassBackend) Start(ctx context.Context, name string) error {
+	return shell(ctx, b.command(multipassCommandLine, startArgument, name)...)
+}
+
+func (b *multipassBackend) Stop(ctx context.Context, name string) error {
+	return shell(ctx, b.command(multipassCommandLine, stopArgument, name)...)
+}
+
+func (b *multipassBackend) Delete(ctx context.Context, name string) error {
+	return shell(ctx, b.command(multipassCommandLine, deleteArgument, purgeArgument, name)...)
+}
+
+func (b *multipassBackend) Status(ctx context.Context, name string) (*VMStatus, error) {
+	var out string
+	var err error
+	var vmInfos MultipassVMInfos
+
+	if out, err = pipe(ctx, b.command(multipassCommandLine, infoArgument, name, "--format=json")...); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal([]byte(out), &vmInfos); err != nil {
+		return nil, wrapError(kindInternal, err, errGetVMInfoFailed, name, err)
+	}
+
+	if vmInfo := vmInfos.Info[name]; vmInfo != nil {
+		return &VMStatus{
+			State:     multipassState(name, vmInfo.State),
+			Addresses: vmInfo.Ipv4,
+		}, nil
+	}
+
+	return nil, newError(kindNotFound, errMultiPassInfoNotFound, name)
+}
+
+func (b *multipassBackend) Exec(ctx context.Context, name string, args ...string) (string, error) {
+	return pipe(ctx, b.command(append([]string{multipassCommandLine, execArgument, name, dashDashArgument}, args...)...)...)
+}
+
+func (b *multipassBackend) CopyFile(ctx context.Context, name, src, dst string) error {
+	remoteSrc, cleanup, err := b.upload(ctx, src)
+
+	if err != nil {
+		return err
+	}
+
+	defer cleanup()
+
+	return shell(ctx, b.command(multipassCommandLine, copyFileArgument, remoteSrc, name+":"+dst)...)
+}
+
+func (b *multipassBackend) Mount(ctx context.Context, name, hostPath, guestPath string) error {
+	return shell(ctx, b.command(multipassCommandLine, mountArgument, hostPath, fmt.Sprintf("%s:%s", name, guestPath))...)
+}
+
+func (b *multipassBackend) List(ctx context.Context) ([]string, error) {
+	var out string
+	var err error
+	var vmList MultipassVMList
+
+	if out, err = pipe(ctx, b.command(multipassCommandLine, listArgument, "--format=json")...); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal([]byte(out), &vmList); err != nil {
+		return nil, wrapError(kindInternal, err, errUnmarshallingError, "multipass list", err)
+	}
+
+	names := make([]string, 0, len(vmList.List))
+
+	for _, vm := range vmList.List {
+		names = append(names, vm.Name)
+	}
+
+	return names, nil
+}
@@ -0,0 +1,2864 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.24.0
+// 	protoc        v3.13.0
+// source: multipass.proto
+
+package multipassd
+
+import (
+	context "context"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type LaunchProgress_ProgressTypes int32
+
+const (
+	LaunchProgress_IMAGE   LaunchProgress_ProgressTypes = 0
+	LaunchProgress_KERNEL  LaunchProgress_ProgressTypes = 1
+	LaunchProgress_INITRD  LaunchProgress_ProgressTypes = 2
+	LaunchProgress_EXTRACT LaunchProgress_ProgressTypes = 3
+	LaunchProgress_VERIFY  LaunchProgress_ProgressTypes = 4
+	LaunchProgress_WAITING LaunchProgress_ProgressTypes = 5
+)
+
+// Enum value maps for LaunchProgress_ProgressTypes.
+var (
+	LaunchProgress_ProgressTypes_name = map[int32]string{
+		0: "IMAGE",
+		1: "KERNEL",
+		2: "INITRD",
+		3: "EXTRACT",
+		4: "VERIFY",
+		5: "WAITING",
+	}
+	LaunchProgress_ProgressTypes_value = map[string]int32{
+		"IMAGE":   0,
+		"KERNEL":  1,
+		"INITRD":  2,
+		"EXTRACT": 3,
+		"VERIFY":  4,
+		"WAITING": 5,
+	}
+)
+
+func (x LaunchProgress_ProgressTypes) Enum() *LaunchProgress_ProgressTypes {
+	p := new(LaunchProgress_ProgressTypes)
+	*p = x
+	return p
+}
+
+func (x LaunchProgress_ProgressTypes) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LaunchProgress_ProgressTypes) Descriptor() protoreflect.EnumDescriptor {
+	return file_multipass_proto_enumTypes[0].Descriptor()
+}
+
+func (LaunchProgress_ProgressTypes) Type() protoreflect.EnumType {
+	return &file_multipass_proto_enumTypes[0]
+}
+
+func (x LaunchProgress_ProgressTypes) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LaunchProgress_ProgressTypes.Descriptor instead.
+func (LaunchProgress_ProgressTypes) EnumDescriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{1, 0}
+}
+
+type InstanceStatus_Status int32
+
+const (
+	InstanceStatus_RUNNING          InstanceStatus_Status = 0
+	InstanceStatus_STARTING         InstanceStatus_Status = 1
+	InstanceStatus_RESTARTING       InstanceStatus_Status = 2
+	InstanceStatus_STOPPED          InstanceStatus_Status = 3
+	InstanceStatus_DELETED          InstanceStatus_Status = 4
+	InstanceStatus_DELAYED_SHUTDOWN InstanceStatus_Status = 5
+	InstanceStatus_SUSPENDING       InstanceStatus_Status = 6
+	InstanceStatus_SUSPENDED        InstanceStatus_Status = 7
+	InstanceStatus_UNKNOWN          InstanceStatus_Status = 8
+)
+
+// Enum value maps for InstanceStatus_Status.
+var (
+	InstanceStatus_Status_name = map[int32]string{
+		0: "RUNNING",
+		1: "STARTING",
+		2: "RESTARTING",
+		3: "STOPPED",
+		4: "DELETED",
+		5: "DELAYED_SHUTDOWN",
+		6: "SUSPENDING",
+		7: "SUSPENDED",
+		8: "UNKNOWN",
+	}
+	InstanceStatus_Status_value = map[string]int32{
+		"RUNNING":          0,
+		"STARTING":         1,
+		"RESTARTING":       2,
+		"STOPPED":          3,
+		"DELETED":          4,
+		"DELAYED_SHUTDOWN": 5,
+		"SUSPENDING":       6,
+		"SUSPENDED":        7,
+		"UNKNOWN":          8,
+	}
+)
+
+func (x InstanceStatus_Status) Enum() *InstanceStatus_Status {
+	p := new(InstanceStatus_Status)
+	*p = x
+	return p
+}
+
+func (x InstanceStatus_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (InstanceStatus_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_multipass_proto_enumTypes[1].Descriptor()
+}
+
+func (InstanceStatus_Status) Type() protoreflect.EnumType {
+	return &file_multipass_proto_enumTypes[1]
+}
+
+func (x InstanceStatus_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use InstanceStatus_Status.Descriptor instead.
+func (InstanceStatus_Status) EnumDescriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{4, 0}
+}
+
+type LaunchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InstanceName      string `protobuf:"bytes,1,opt,name=instance_name,json=instanceName,proto3" json:"instance_name,omitempty"`
+	Image             string `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	KernelName        string `protobuf:"bytes,3,opt,name=kernel_name,json=kernelName,proto3" json:"kernel_name,omitempty"`
+	NumCores          int32  `protobuf:"varint,4,opt,name=num_cores,json=numCores,proto3" json:"num_cores,omitempty"`
+	MemSize           string `protobuf:"bytes,5,opt,name=mem_size,json=memSize,proto3" json:"mem_size,omitempty"`
+	DiskSpace         string `protobuf:"bytes,6,opt,name=disk_space,json=diskSpace,proto3" json:"disk_space,omitempty"`
+	TimeZone          string `protobuf:"bytes,7,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	CloudInitUserData string `protobuf:"bytes,8,opt,name=cloud_init_user_data,json=cloudInitUserData,proto3" json:"cloud_init_user_data,omitempty"`
+	RemoteName        string `protobuf:"bytes,9,opt,name=remote_name,json=remoteName,proto3" json:"remote_name,omitempty"`
+	VerbosityLevel    int32  `protobuf:"varint,11,opt,name=verbosity_level,json=verbosityLevel,proto3" json:"verbosity_level,omitempty"`
+}
+
+func (x *LaunchRequest) Reset() {
+	*x = LaunchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LaunchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LaunchRequest) ProtoMessage() {}
+
+func (x *LaunchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LaunchRequest.ProtoReflect.Descriptor instead.
+func (*LaunchRequest) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LaunchRequest) GetInstanceName() string {
+	if x != nil {
+		return x.InstanceName
+	}
+	return ""
+}
+
+func (x *LaunchRequest) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *LaunchRequest) GetKernelName() string {
+	if x != nil {
+		return x.KernelName
+	}
+	return ""
+}
+
+func (x *LaunchRequest) GetNumCores() int32 {
+	if x != nil {
+		return x.NumCores
+	}
+	return 0
+}
+
+func (x *LaunchRequest) GetMemSize() string {
+	if x != nil {
+		return x.MemSize
+	}
+	return ""
+}
+
+func (x *LaunchRequest) GetDiskSpace() string {
+	if x != nil {
+		return x.DiskSpace
+	}
+	return ""
+}
+
+func (x *LaunchRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *LaunchRequest) GetCloudInitUserData() string {
+	if x != nil {
+		return x.CloudInitUserData
+	}
+	return ""
+}
+
+func (x *LaunchRequest) GetRemoteName() string {
+	if x != nil {
+		return x.RemoteName
+	}
+	return ""
+}
+
+func (x *LaunchRequest) GetVerbosityLevel() int32 {
+	if x != nil {
+		return x.VerbosityLevel
+	}
+	return 0
+}
+
+type LaunchProgress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type            LaunchProgress_ProgressTypes `protobuf:"varint,1,opt,name=type,proto3,enum=multipass.LaunchProgress_ProgressTypes" json:"type,omitempty"`
+	PercentComplete string                       `protobuf:"bytes,2,opt,name=percent_complete,json=percentComplete,proto3" json:"percent_complete,omitempty"`
+}
+
+func (x *LaunchProgress) Reset() {
+	*x = LaunchProgress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LaunchProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LaunchProgress) ProtoMessage() {}
+
+func (x *LaunchProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LaunchProgress.ProtoReflect.Descriptor instead.
+func (*LaunchProgress) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LaunchProgress) GetType() LaunchProgress_ProgressTypes {
+	if x != nil {
+		return x.Type
+	}
+	return LaunchProgress_IMAGE
+}
+
+func (x *LaunchProgress) GetPercentComplete() string {
+	if x != nil {
+		return x.PercentComplete
+	}
+	return ""
+}
+
+type LaunchReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to CreateOneof:
+	//	*LaunchReply_VmInstanceName
+	//	*LaunchReply_LaunchProgress
+	//	*LaunchReply_CreateMessage
+	CreateOneof isLaunchReply_CreateOneof `protobuf_oneof:"create_oneof"`
+	LogLine     string                    `protobuf:"bytes,6,opt,name=log_line,json=logLine,proto3" json:"log_line,omitempty"`
+}
+
+func (x *LaunchReply) Reset() {
+	*x = LaunchReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LaunchReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LaunchReply) ProtoMessage() {}
+
+func (x *LaunchReply) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LaunchReply.ProtoReflect.Descriptor instead.
+func (*LaunchReply) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{2}
+}
+
+func (m *LaunchReply) GetCreateOneof() isLaunchReply_CreateOneof {
+	if m != nil {
+		return m.CreateOneof
+	}
+	return nil
+}
+
+func (x *LaunchReply) GetVmInstanceName() string {
+	if x, ok := x.GetCreateOneof().(*LaunchReply_VmInstanceName); ok {
+		return x.VmInstanceName
+	}
+	return ""
+}
+
+func (x *LaunchReply) GetLaunchProgress() *LaunchProgress {
+	if x, ok := x.GetCreateOneof().(*LaunchReply_LaunchProgress); ok {
+		return x.LaunchProgress
+	}
+	return nil
+}
+
+func (x *LaunchReply) GetCreateMessage() string {
+	if x, ok := x.GetCreateOneof().(*LaunchReply_CreateMessage); ok {
+		return x.CreateMessage
+	}
+	return ""
+}
+
+func (x *LaunchReply) GetLogLine() string {
+	if x != nil {
+		return x.LogLine
+	}
+	return ""
+}
+
+type isLaunchReply_CreateOneof interface {
+	isLaunchReply_CreateOneof()
+}
+
+type LaunchReply_VmInstanceName struct {
+	VmInstanceName string `protobuf:"bytes,1,opt,name=vm_instance_name,json=vmInstanceName,proto3,oneof"`
+}
+
+type LaunchReply_LaunchProgress struct {
+	LaunchProgress *LaunchProgress `protobuf:"bytes,2,opt,name=launch_progress,json=launchProgress,proto3,oneof"`
+}
+
+type LaunchReply_CreateMessage struct {
+	CreateMessage string `protobuf:"bytes,3,opt,name=create_message,json=createMessage,proto3,oneof"`
+}
+
+func (*LaunchReply_VmInstanceName) isLaunchReply_CreateOneof() {}
+
+func (*LaunchReply_LaunchProgress) isLaunchReply_CreateOneof() {}
+
+func (*LaunchReply_CreateMessage) isLaunchReply_CreateOneof() {}
+
+type InstanceNames struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InstanceName []string `protobuf:"bytes,1,rep,name=instance_name,json=instanceName,proto3" json:"instance_name,omitempty"`
+}
+
+func (x *InstanceNames) Reset() {
+	*x = InstanceNames{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InstanceNames) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstanceNames) ProtoMessage() {}
+
+func (x *InstanceNames) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstanceNames.ProtoReflect.Descriptor instead.
+func (*InstanceNames) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *InstanceNames) GetInstanceName() []string {
+	if x != nil {
+		return x.InstanceName
+	}
+	return nil
+}
+
+type InstanceStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status InstanceStatus_Status `protobuf:"varint,1,opt,name=status,proto3,enum=multipass.InstanceStatus_Status" json:"status,omitempty"`
+}
+
+func (x *InstanceStatus) Reset() {
+	*x = InstanceStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InstanceStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstanceStatus) ProtoMessage() {}
+
+func (x *InstanceStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstanceStatus.ProtoReflect.Descriptor instead.
+func (*InstanceStatus) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *InstanceStatus) GetStatus() InstanceStatus_Status {
+	if x != nil {
+		return x.Status
+	}
+	return InstanceStatus_RUNNING
+}
+
+type InfoRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InstanceNames        *InstanceNames `protobuf:"bytes,1,opt,name=instance_names,json=instanceNames,proto3" json:"instance_names,omitempty"`
+	VerbosityLevel       int32          `protobuf:"varint,3,opt,name=verbosity_level,json=verbosityLevel,proto3" json:"verbosity_level,omitempty"`
+	NoRuntimeInformation bool           `protobuf:"varint,4,opt,name=no_runtime_information,json=noRuntimeInformation,proto3" json:"no_runtime_information,omitempty"`
+}
+
+func (x *InfoRequest) Reset() {
+	*x = InfoRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InfoRequest) ProtoMessage() {}
+
+func (x *InfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InfoRequest.ProtoReflect.Descriptor instead.
+func (*InfoRequest) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *InfoRequest) GetInstanceNames() *InstanceNames {
+	if x != nil {
+		return x.InstanceNames
+	}
+	return nil
+}
+
+func (x *InfoRequest) GetVerbosityLevel() int32 {
+	if x != nil {
+		return x.VerbosityLevel
+	}
+	return 0
+}
+
+func (x *InfoRequest) GetNoRuntimeInformation() bool {
+	if x != nil {
+		return x.NoRuntimeInformation
+	}
+	return false
+}
+
+type InfoReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Info    []*InfoReply_Info `protobuf:"bytes,1,rep,name=info,proto3" json:"info,omitempty"`
+	LogLine string            `protobuf:"bytes,2,opt,name=log_line,json=logLine,proto3" json:"log_line,omitempty"`
+}
+
+func (x *InfoReply) Reset() {
+	*x = InfoReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InfoReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InfoReply) ProtoMessage() {}
+
+func (x *InfoReply) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InfoReply.ProtoReflect.Descriptor instead.
+func (*InfoReply) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *InfoReply) GetInfo() []*InfoReply_Info {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *InfoReply) GetLogLine() string {
+	if x != nil {
+		return x.LogLine
+	}
+	return ""
+}
+
+type ListRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	VerbosityLevel int32 `protobuf:"varint,1,opt,name=verbosity_level,json=verbosityLevel,proto3" json:"verbosity_level,omitempty"`
+	RequestIpv4    bool  `protobuf:"varint,2,opt,name=request_ipv4,json=requestIpv4,proto3" json:"request_ipv4,omitempty"`
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListRequest) GetVerbosityLevel() int32 {
+	if x != nil {
+		return x.VerbosityLevel
+	}
+	return 0
+}
+
+func (x *ListRequest) GetRequestIpv4() bool {
+	if x != nil {
+		return x.RequestIpv4
+	}
+	return false
+}
+
+type ListVMInstance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name           string          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	InstanceStatus *InstanceStatus `protobuf:"bytes,2,opt,name=instance_status,json=instanceStatus,proto3" json:"instance_status,omitempty"`
+	Ipv4           []string        `protobuf:"bytes,3,rep,name=ipv4,proto3" json:"ipv4,omitempty"`
+	Ipv6           string          `protobuf:"bytes,4,opt,name=ipv6,proto3" json:"ipv6,omitempty"`
+	CurrentRelease string          `protobuf:"bytes,5,opt,name=current_release,json=currentRelease,proto3" json:"current_release,omitempty"`
+}
+
+func (x *ListVMInstance) Reset() {
+	*x = ListVMInstance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListVMInstance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVMInstance) ProtoMessage() {}
+
+func (x *ListVMInstance) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVMInstance.ProtoReflect.Descriptor instead.
+func (*ListVMInstance) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListVMInstance) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListVMInstance) GetInstanceStatus() *InstanceStatus {
+	if x != nil {
+		return x.InstanceStatus
+	}
+	return nil
+}
+
+func (x *ListVMInstance) GetIpv4() []string {
+	if x != nil {
+		return x.Ipv4
+	}
+	return nil
+}
+
+func (x *ListVMInstance) GetIpv6() string {
+	if x != nil {
+		return x.Ipv6
+	}
+	return ""
+}
+
+func (x *ListVMInstance) GetCurrentRelease() string {
+	if x != nil {
+		return x.CurrentRelease
+	}
+	return ""
+}
+
+type ListReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Instances []*ListVMInstance `protobuf:"bytes,1,rep,name=instances,proto3" json:"instances,omitempty"`
+	LogLine   string            `protobuf:"bytes,2,opt,name=log_line,json=logLine,proto3" json:"log_line,omitempty"`
+}
+
+func (x *ListReply) Reset() {
+	*x = ListReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReply) ProtoMessage() {}
+
+func (x *ListReply) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReply.ProtoReflect.Descriptor instead.
+func (*ListReply) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListReply) GetInstances() []*ListVMInstance {
+	if x != nil {
+		return x.Instances
+	}
+	return nil
+}
+
+func (x *ListReply) GetLogLine() string {
+	if x != nil {
+		return x.LogLine
+	}
+	return ""
+}
+
+type TargetPathInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InstanceName string `protobuf:"bytes,1,opt,name=instance_name,json=instanceName,proto3" json:"instance_name,omitempty"`
+	TargetPath   string `protobuf:"bytes,2,opt,name=target_path,json=targetPath,proto3" json:"target_path,omitempty"`
+}
+
+func (x *TargetPathInfo) Reset() {
+	*x = TargetPathInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TargetPathInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TargetPathInfo) ProtoMessage() {}
+
+func (x *TargetPathInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TargetPathInfo.ProtoReflect.Descriptor instead.
+func (*TargetPathInfo) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TargetPathInfo) GetInstanceName() string {
+	if x != nil {
+		return x.InstanceName
+	}
+	return ""
+}
+
+func (x *TargetPathInfo) GetTargetPath() string {
+	if x != nil {
+		return x.TargetPath
+	}
+	return ""
+}
+
+type MountRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourcePath     string            `protobuf:"bytes,1,opt,name=source_path,json=sourcePath,proto3" json:"source_path,omitempty"`
+	TargetPaths    []*TargetPathInfo `protobuf:"bytes,2,rep,name=target_paths,json=targetPaths,proto3" json:"target_paths,omitempty"`
+	VerbosityLevel int32             `protobuf:"varint,4,opt,name=verbosity_level,json=verbosityLevel,proto3" json:"verbosity_level,omitempty"`
+}
+
+func (x *MountRequest) Reset() {
+	*x = MountRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MountRequest) ProtoMessage() {}
+
+func (x *MountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MountRequest.ProtoReflect.Descriptor instead.
+func (*MountRequest) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *MountRequest) GetSourcePath() string {
+	if x != nil {
+		return x.SourcePath
+	}
+	return ""
+}
+
+func (x *MountRequest) GetTargetPaths() []*TargetPathInfo {
+	if x != nil {
+		return x.TargetPaths
+	}
+	return nil
+}
+
+func (x *MountRequest) GetVerbosityLevel() int32 {
+	if x != nil {
+		return x.VerbosityLevel
+	}
+	return 0
+}
+
+type MountReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogLine      string `protobuf:"bytes,1,opt,name=log_line,json=logLine,proto3" json:"log_line,omitempty"`
+	ReplyMessage string `protobuf:"bytes,2,opt,name=reply_message,json=replyMessage,proto3" json:"reply_message,omitempty"`
+}
+
+func (x *MountReply) Reset() {
+	*x = MountReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MountReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MountReply) ProtoMessage() {}
+
+func (x *MountReply) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MountReply.ProtoReflect.Descriptor instead.
+func (*MountReply) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *MountReply) GetLogLine() string {
+	if x != nil {
+		return x.LogLine
+	}
+	return ""
+}
+
+func (x *MountReply) GetReplyMessage() string {
+	if x != nil {
+		return x.ReplyMessage
+	}
+	return ""
+}
+
+type SSHInfoRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InstanceName   []string `protobuf:"bytes,1,rep,name=instance_name,json=instanceName,proto3" json:"instance_name,omitempty"`
+	VerbosityLevel int32    `protobuf:"varint,2,opt,name=verbosity_level,json=verbosityLevel,proto3" json:"verbosity_level,omitempty"`
+}
+
+func (x *SSHInfoRequest) Reset() {
+	*x = SSHInfoRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SSHInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SSHInfoRequest) ProtoMessage() {}
+
+func (x *SSHInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SSHInfoRequest.ProtoReflect.Descriptor instead.
+func (*SSHInfoRequest) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SSHInfoRequest) GetInstanceName() []string {
+	if x != nil {
+		return x.InstanceName
+	}
+	return nil
+}
+
+func (x *SSHInfoRequest) GetVerbosityLevel() int32 {
+	if x != nil {
+		return x.VerbosityLevel
+	}
+	return 0
+}
+
+type SSHInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Port          int32  `protobuf:"varint,1,opt,name=port,proto3" json:"port,omitempty"`
+	PrivKeyBase64 string `protobuf:"bytes,2,opt,name=priv_key_base64,json=privKeyBase64,proto3" json:"priv_key_base64,omitempty"`
+	Host          string `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
+	Username      string `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+}
+
+func (x *SSHInfo) Reset() {
+	*x = SSHInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SSHInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SSHInfo) ProtoMessage() {}
+
+func (x *SSHInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SSHInfo.ProtoReflect.Descriptor instead.
+func (*SSHInfo) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SSHInfo) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *SSHInfo) GetPrivKeyBase64() string {
+	if x != nil {
+		return x.PrivKeyBase64
+	}
+	return ""
+}
+
+func (x *SSHInfo) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *SSHInfo) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type SSHInfoReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SshInfo map[string]*SSHInfo `protobuf:"bytes,1,rep,name=ssh_info,json=sshInfo,proto3" json:"ssh_info,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	LogLine string              `protobuf:"bytes,2,opt,name=log_line,json=logLine,proto3" json:"log_line,omitempty"`
+}
+
+func (x *SSHInfoReply) Reset() {
+	*x = SSHInfoReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SSHInfoReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SSHInfoReply) ProtoMessage() {}
+
+func (x *SSHInfoReply) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SSHInfoReply.ProtoReflect.Descriptor instead.
+func (*SSHInfoReply) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SSHInfoReply) GetSshInfo() map[string]*SSHInfo {
+	if x != nil {
+		return x.SshInfo
+	}
+	return nil
+}
+
+func (x *SSHInfoReply) GetLogLine() string {
+	if x != nil {
+		return x.LogLine
+	}
+	return ""
+}
+
+type StartRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InstanceNames  *InstanceNames `protobuf:"bytes,1,opt,name=instance_names,json=instanceNames,proto3" json:"instance_names,omitempty"`
+	VerbosityLevel int32          `protobuf:"varint,2,opt,name=verbosity_level,json=verbosityLevel,proto3" json:"verbosity_level,omitempty"`
+	Timeout        int32          `protobuf:"varint,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+}
+
+func (x *StartRequest) Reset() {
+	*x = StartRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartRequest) ProtoMessage() {}
+
+func (x *StartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartRequest.ProtoReflect.Descriptor instead.
+func (*StartRequest) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StartRequest) GetInstanceNames() *InstanceNames {
+	if x != nil {
+		return x.InstanceNames
+	}
+	return nil
+}
+
+func (x *StartRequest) GetVerbosityLevel() int32 {
+	if x != nil {
+		return x.VerbosityLevel
+	}
+	return 0
+}
+
+func (x *StartRequest) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+type StartReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogLine      string `protobuf:"bytes,1,opt,name=log_line,json=logLine,proto3" json:"log_line,omitempty"`
+	ReplyMessage string `protobuf:"bytes,2,opt,name=reply_message,json=replyMessage,proto3" json:"reply_message,omitempty"`
+}
+
+func (x *StartReply) Reset() {
+	*x = StartReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StartReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartReply) ProtoMessage() {}
+
+func (x *StartReply) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartReply.ProtoReflect.Descriptor instead.
+func (*StartReply) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *StartReply) GetLogLine() string {
+	if x != nil {
+		return x.LogLine
+	}
+	return ""
+}
+
+func (x *StartReply) GetReplyMessage() string {
+	if x != nil {
+		return x.ReplyMessage
+	}
+	return ""
+}
+
+type StopRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InstanceNames  *InstanceNames `protobuf:"bytes,1,opt,name=instance_names,json=instanceNames,proto3" json:"instance_names,omitempty"`
+	TimeMinutes    int32          `protobuf:"varint,2,opt,name=time_minutes,json=timeMinutes,proto3" json:"time_minutes,omitempty"`
+	CancelShutdown bool           `protobuf:"varint,3,opt,name=cancel_shutdown,json=cancelShutdown,proto3" json:"cancel_shutdown,omitempty"`
+	VerbosityLevel int32          `protobuf:"varint,4,opt,name=verbosity_level,json=verbosityLevel,proto3" json:"verbosity_level,omitempty"`
+}
+
+func (x *StopRequest) Reset() {
+	*x = StopRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StopRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopRequest) ProtoMessage() {}
+
+func (x *StopRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopRequest.ProtoReflect.Descriptor instead.
+func (*StopRequest) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *StopRequest) GetInstanceNames() *InstanceNames {
+	if x != nil {
+		return x.InstanceNames
+	}
+	return nil
+}
+
+func (x *StopRequest) GetTimeMinutes() int32 {
+	if x != nil {
+		return x.TimeMinutes
+	}
+	return 0
+}
+
+func (x *StopRequest) GetCancelShutdown() bool {
+	if x != nil {
+		return x.CancelShutdown
+	}
+	return false
+}
+
+func (x *StopRequest) GetVerbosityLevel() int32 {
+	if x != nil {
+		return x.VerbosityLevel
+	}
+	return 0
+}
+
+type StopReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogLine string `protobuf:"bytes,1,opt,name=log_line,json=logLine,proto3" json:"log_line,omitempty"`
+}
+
+func (x *StopReply) Reset() {
+	*x = StopReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StopReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopReply) ProtoMessage() {}
+
+func (x *StopReply) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopReply.ProtoReflect.Descriptor instead.
+func (*StopReply) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *StopReply) GetLogLine() string {
+	if x != nil {
+		return x.LogLine
+	}
+	return ""
+}
+
+type DeleteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InstanceNames  *InstanceNames `protobuf:"bytes,1,opt,name=instance_names,json=instanceNames,proto3" json:"instance_names,omitempty"`
+	Purge          bool           `protobuf:"varint,2,opt,name=purge,proto3" json:"purge,omitempty"`
+	VerbosityLevel int32          `protobuf:"varint,3,opt,name=verbosity_level,json=verbosityLevel,proto3" json:"verbosity_level,omitempty"`
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DeleteRequest) GetInstanceNames() *InstanceNames {
+	if x != nil {
+		return x.InstanceNames
+	}
+	return nil
+}
+
+func (x *DeleteRequest) GetPurge() bool {
+	if x != nil {
+		return x.Purge
+	}
+	return false
+}
+
+func (x *DeleteRequest) GetVerbosityLevel() int32 {
+	if x != nil {
+		return x.VerbosityLevel
+	}
+	return 0
+}
+
+type DeleteReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogLine         string   `protobuf:"bytes,1,opt,name=log_line,json=logLine,proto3" json:"log_line,omitempty"`
+	PurgedInstances []string `protobuf:"bytes,2,rep,name=purged_instances,json=purgedInstances,proto3" json:"purged_instances,omitempty"`
+}
+
+func (x *DeleteReply) Reset() {
+	*x = DeleteReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReply) ProtoMessage() {}
+
+func (x *DeleteReply) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReply.ProtoReflect.Descriptor instead.
+func (*DeleteReply) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DeleteReply) GetLogLine() string {
+	if x != nil {
+		return x.LogLine
+	}
+	return ""
+}
+
+func (x *DeleteReply) GetPurgedInstances() []string {
+	if x != nil {
+		return x.PurgedInstances
+	}
+	return nil
+}
+
+type InfoReply_Info struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name           string          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	InstanceStatus *InstanceStatus `protobuf:"bytes,2,opt,name=instance_status,json=instanceStatus,proto3" json:"instance_status,omitempty"`
+	ImageRelease   string          `protobuf:"bytes,3,opt,name=image_release,json=imageRelease,proto3" json:"image_release,omitempty"`
+	CurrentRelease string          `protobuf:"bytes,4,opt,name=current_release,json=currentRelease,proto3" json:"current_release,omitempty"`
+	Id             string          `protobuf:"bytes,5,opt,name=id,proto3" json:"id,omitempty"`
+	Load           string          `protobuf:"bytes,6,opt,name=load,proto3" json:"load,omitempty"`
+	MemoryUsage    int64           `protobuf:"varint,7,opt,name=memory_usage,json=memoryUsage,proto3" json:"memory_usage,omitempty"`
+	MemoryTotal    string          `protobuf:"bytes,8,opt,name=memory_total,json=memoryTotal,proto3" json:"memory_total,omitempty"`
+	DiskUsage      int64           `protobuf:"varint,9,opt,name=disk_usage,json=diskUsage,proto3" json:"disk_usage,omitempty"`
+	DiskTotal      string          `protobuf:"bytes,10,opt,name=disk_total,json=diskTotal,proto3" json:"disk_total,omitempty"`
+	Ipv4           []string        `protobuf:"bytes,11,rep,name=ipv4,proto3" json:"ipv4,omitempty"`
+}
+
+func (x *InfoReply_Info) Reset() {
+	*x = InfoReply_Info{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_multipass_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InfoReply_Info) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InfoReply_Info) ProtoMessage() {}
+
+func (x *InfoReply_Info) ProtoReflect() protoreflect.Message {
+	mi := &file_multipass_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InfoReply_Info.ProtoReflect.Descriptor instead.
+func (*InfoReply_Info) Descriptor() ([]byte, []int) {
+	return file_multipass_proto_rawDescGZIP(), []int{6, 0}
+}
+
+func (x *InfoReply_Info) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *InfoReply_Info) GetInstanceStatus() *InstanceStatus {
+	if x != nil {
+		return x.InstanceStatus
+	}
+	return nil
+}
+
+func (x *InfoReply_Info) GetImageRelease() string {
+	if x != nil {
+		return x.ImageRelease
+	}
+	return ""
+}
+
+func (x *InfoReply_Info) GetCurrentRelease() string {
+	if x != nil {
+		return x.CurrentRelease
+	}
+	return ""
+}
+
+func (x *InfoReply_Info) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *InfoReply_Info) GetLoad() string {
+	if x != nil {
+		return x.Load
+	}
+	return ""
+}
+
+func (x *InfoReply_Info) GetMemoryUsage() int64 {
+	if x != nil {
+		return x.MemoryUsage
+	}
+	return 0
+}
+
+func (x *InfoReply_Info) GetMemoryTotal() string {
+	if x != nil {
+		return x.MemoryTotal
+	}
+	return ""
+}
+
+func (x *InfoReply_Info) GetDiskUsage() int64 {
+	if x != nil {
+		return x.DiskUsage
+	}
+	return 0
+}
+
+func (x *InfoReply_Info) GetDiskTotal() string {
+	if x != nil {
+		return x.DiskTotal
+	}
+	return ""
+}
+
+func (x *InfoReply_Info) GetIpv4() []string {
+	if x != nil {
+		return x.Ipv4
+	}
+	return nil
+}
+
+var File_multipass_proto protoreflect.FileDescriptor
+
+var file_multipass_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x09, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x22, 0xda, 0x02, 0x0a,
+	0x0d, 0x4c, 0x61, 0x75, 0x6e, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23,
+	0x0a, 0x0d, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6b, 0x65, 0x72,
+	0x6e, 0x65, 0x6c, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x75,
+	0x6d, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6e,
+	0x75, 0x6d, 0x43, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x65, 0x6d, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x6d, 0x53, 0x69,
+	0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x6b, 0x5f, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x69, 0x73, 0x6b, 0x53, 0x70, 0x61, 0x63,
+	0x65, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x12, 0x2f,
+	0x0a, 0x14, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x5f, 0x69, 0x6e, 0x69, 0x74, 0x5f, 0x75, 0x73, 0x65,
+	0x72, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x63, 0x6c,
+	0x6f, 0x75, 0x64, 0x49, 0x6e, 0x69, 0x74, 0x55, 0x73, 0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x12,
+	0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x27, 0x0a, 0x0f, 0x76, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x69, 0x74, 0x79, 0x5f, 0x6c, 0x65,
+	0x76, 0x65, 0x6c, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x76, 0x65, 0x72, 0x62, 0x6f,
+	0x73, 0x69, 0x74, 0x79, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0xd2, 0x01, 0x0a, 0x0e, 0x4c, 0x61,
+	0x75, 0x6e, 0x63, 0x68, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x3b, 0x0a, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x27, 0x2e, 0x6d, 0x75, 0x6c,
+	0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e, 0x4c, 0x61, 0x75, 0x6e, 0x63, 0x68, 0x50, 0x72, 0x6f,
+	0x67, 0x72, 0x65, 0x73, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x54, 0x79,
+	0x70, 0x65, 0x73, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x65, 0x72,
+	0x63, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6d, 0x70,
+	0x6c, 0x65, 0x74, 0x65, 0x22, 0x58, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x54, 0x79, 0x70, 0x65, 0x73, 0x12, 0x09, 0x0a, 0x05, 0x49, 0x4d, 0x41, 0x47, 0x45, 0x10, 0x00,
+	0x12, 0x0a, 0x0a, 0x06, 0x4b, 0x45, 0x52, 0x4e, 0x45, 0x4c, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06,
+	0x49, 0x4e, 0x49, 0x54, 0x52, 0x44, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x45, 0x58, 0x54, 0x52,
+	0x41, 0x43, 0x54, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x56, 0x45, 0x52, 0x49, 0x46, 0x59, 0x10,
+	0x04, 0x12, 0x0b, 0x0a, 0x07, 0x57, 0x41, 0x49, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x05, 0x22, 0xd3,
+	0x01, 0x0a, 0x0b, 0x4c, 0x61, 0x75, 0x6e, 0x63, 0x68, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2a,
+	0x0a, 0x10, 0x76, 0x6d, 0x5f, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0e, 0x76, 0x6d, 0x49, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x44, 0x0a, 0x0f, 0x6c, 0x61,
+	0x75, 0x6e, 0x63, 0x68, 0x5f, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e,
+	0x4c, 0x61, 0x75, 0x6e, 0x63, 0x68, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x48, 0x00,
+	0x52, 0x0e, 0x6c, 0x61, 0x75, 0x6e, 0x63, 0x68, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x12, 0x27, 0x0a, 0x0e, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0d, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x6f, 0x67,
+	0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x6f, 0x67,
+	0x4c, 0x69, 0x6e, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x6f,
+	0x6e, 0x65, 0x6f, 0x66, 0x22, 0x34, 0x0a, 0x0d, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0xdc, 0x01, 0x0a, 0x0e, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x38, 0x0a,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x20, 0x2e,
+	0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x8f, 0x01, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12,
+	0x0c, 0x0a, 0x08, 0x53, 0x54, 0x41, 0x52, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x0e, 0x0a,
+	0x0a, 0x52, 0x45, 0x53, 0x54, 0x41, 0x52, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x0b, 0x0a,
+	0x07, 0x53, 0x54, 0x4f, 0x50, 0x50, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x45,
+	0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x04, 0x12, 0x14, 0x0a, 0x10, 0x44, 0x45, 0x4c, 0x41, 0x59,
+	0x45, 0x44, 0x5f, 0x53, 0x48, 0x55, 0x54, 0x44, 0x4f, 0x57, 0x4e, 0x10, 0x05, 0x12, 0x0e, 0x0a,
+	0x0a, 0x53, 0x55, 0x53, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x06, 0x12, 0x0d, 0x0a,
+	0x09, 0x53, 0x55, 0x53, 0x50, 0x45, 0x4e, 0x44, 0x45, 0x44, 0x10, 0x07, 0x12, 0x0b, 0x0a, 0x07,
+	0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x08, 0x22, 0xad, 0x01, 0x0a, 0x0b, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0e, 0x69, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e, 0x49, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x52, 0x0d, 0x69, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x76, 0x65,
+	0x72, 0x62, 0x6f, 0x73, 0x69, 0x74, 0x79, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0e, 0x76, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x69, 0x74, 0x79, 0x4c, 0x65,
+	0x76, 0x65, 0x6c, 0x12, 0x34, 0x0a, 0x16, 0x6e, 0x6f, 0x5f, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d,
+	0x65, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x14, 0x6e, 0x6f, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x49, 0x6e,
+	0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xc0, 0x03, 0x0a, 0x09, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2d, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73,
+	0x73, 0x2e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x6f, 0x67, 0x5f, 0x6c, 0x69,
+	0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x6f, 0x67, 0x4c, 0x69, 0x6e,
+	0x65, 0x1a, 0xe8, 0x02, 0x0a, 0x04, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x42,
+	0x0a, 0x0f, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70,
+	0x61, 0x73, 0x73, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x0e, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x72, 0x65, 0x6c, 0x65,
+	0x61, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6d, 0x61, 0x67, 0x65,
+	0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x74, 0x5f, 0x72, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6c, 0x6f, 0x61, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x75,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6d, 0x65, 0x6d, 0x6f,
+	0x72, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x65, 0x6d, 0x6f, 0x72,
+	0x79, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6d,
+	0x65, 0x6d, 0x6f, 0x72, 0x79, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x69,
+	0x73, 0x6b, 0x5f, 0x75, 0x73, 0x61, 0x67, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x64, 0x69, 0x73, 0x6b, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x69, 0x73,
+	0x6b, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64,
+	0x69, 0x73, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x70, 0x76, 0x34,
+	0x18, 0x0b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x69, 0x70, 0x76, 0x34, 0x22, 0x59, 0x0a, 0x0b,
+	0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x76,
+	0x65, 0x72, 0x62, 0x6f, 0x73, 0x69, 0x74, 0x79, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x76, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x69, 0x74, 0x79, 0x4c,
+	0x65, 0x76, 0x65, 0x6c, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f,
+	0x69, 0x70, 0x76, 0x34, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x49, 0x70, 0x76, 0x34, 0x22, 0xb9, 0x01, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74,
+	0x56, 0x4d, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x42,
+	0x0a, 0x0f, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70,
+	0x61, 0x73, 0x73, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x0e, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x70, 0x76, 0x34, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x04, 0x69, 0x70, 0x76, 0x34, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x70, 0x76, 0x36, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69, 0x70, 0x76, 0x36, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x6c, 0x65,
+	0x61, 0x73, 0x65, 0x22, 0x5f, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x12, 0x37, 0x0a, 0x09, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x56, 0x4d, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x09,
+	0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x6f, 0x67,
+	0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x6f, 0x67,
+	0x4c, 0x69, 0x6e, 0x65, 0x22, 0x56, 0x0a, 0x0e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x61,
+	0x74, 0x68, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x69,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x61, 0x74, 0x68, 0x22, 0x96, 0x01, 0x0a,
+	0x0c, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a,
+	0x0b, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x3c,
+	0x0a, 0x0c, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73,
+	0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x61, 0x74, 0x68, 0x49, 0x6e, 0x66, 0x6f, 0x52,
+	0x0b, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12, 0x27, 0x0a, 0x0f,
+	0x76, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x69, 0x74, 0x79, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x76, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x69, 0x74, 0x79,
+	0x4c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0x4c, 0x0a, 0x0a, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x6f, 0x67, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x12, 0x23,
+	0x0a, 0x0d, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x22, 0x5e, 0x0a, 0x0e, 0x53, 0x53, 0x48, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x76, 0x65,
+	0x72, 0x62, 0x6f, 0x73, 0x69, 0x74, 0x79, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0e, 0x76, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x69, 0x74, 0x79, 0x4c, 0x65,
+	0x76, 0x65, 0x6c, 0x22, 0x75, 0x0a, 0x07, 0x53, 0x53, 0x48, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12,
+	0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f,
+	0x72, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x70, 0x72, 0x69, 0x76, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x62,
+	0x61, 0x73, 0x65, 0x36, 0x34, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x72, 0x69,
+	0x76, 0x4b, 0x65, 0x79, 0x42, 0x61, 0x73, 0x65, 0x36, 0x34, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f,
+	0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x1a,
+	0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0xba, 0x01, 0x0a, 0x0c, 0x53,
+	0x53, 0x48, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x3f, 0x0a, 0x08, 0x73,
+	0x73, 0x68, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e,
+	0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e, 0x53, 0x53, 0x48, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e, 0x53, 0x73, 0x68, 0x49, 0x6e, 0x66, 0x6f, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x07, 0x73, 0x73, 0x68, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x19, 0x0a, 0x08,
+	0x6c, 0x6f, 0x67, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x6c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x1a, 0x4e, 0x0a, 0x0c, 0x53, 0x73, 0x68, 0x49, 0x6e,
+	0x66, 0x6f, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x28, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69,
+	0x70, 0x61, 0x73, 0x73, 0x2e, 0x53, 0x53, 0x48, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x92, 0x01, 0x0a, 0x0c, 0x53, 0x74, 0x61, 0x72,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0e, 0x69, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e, 0x49, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x52, 0x0d, 0x69, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x76, 0x65, 0x72,
+	0x62, 0x6f, 0x73, 0x69, 0x74, 0x79, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0e, 0x76, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x69, 0x74, 0x79, 0x4c, 0x65, 0x76,
+	0x65, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x22, 0x4c, 0x0a, 0x0a,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x6f,
+	0x67, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x6f,
+	0x67, 0x4c, 0x69, 0x6e, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x5f, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65,
+	0x70, 0x6c, 0x79, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xc3, 0x01, 0x0a, 0x0b, 0x53,
+	0x74, 0x6f, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0e, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x52, 0x0d, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x74,
+	0x69, 0x6d, 0x65, 0x5f, 0x6d, 0x69, 0x6e, 0x75, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x4d, 0x69, 0x6e, 0x75, 0x74, 0x65, 0x73, 0x12, 0x27,
+	0x0a, 0x0f, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x5f, 0x73, 0x68, 0x75, 0x74, 0x64, 0x6f, 0x77,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x53,
+	0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x76, 0x65, 0x72, 0x62, 0x6f,
+	0x73, 0x69, 0x74, 0x79, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0e, 0x76, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x69, 0x74, 0x79, 0x4c, 0x65, 0x76, 0x65, 0x6c,
+	0x22, 0x26, 0x0a, 0x09, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x19, 0x0a,
+	0x08, 0x6c, 0x6f, 0x67, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x22, 0x8f, 0x01, 0x0a, 0x0d, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0e, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x52, 0x0d, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x70,
+	0x75, 0x72, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x70, 0x75, 0x72, 0x67,
+	0x65, 0x12, 0x27, 0x0a, 0x0f, 0x76, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x69, 0x74, 0x79, 0x5f, 0x6c,
+	0x65, 0x76, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x76, 0x65, 0x72, 0x62,
+	0x6f, 0x73, 0x69, 0x74, 0x79, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0x53, 0x0a, 0x0b, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x6f, 0x67,
+	0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x6f, 0x67,
+	0x4c, 0x69, 0x6e, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x75, 0x72, 0x67, 0x65, 0x64, 0x5f, 0x69,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f,
+	0x70, 0x75, 0x72, 0x67, 0x65, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x32,
+	0xe0, 0x03, 0x0a, 0x03, 0x52, 0x70, 0x63, 0x12, 0x3c, 0x0a, 0x06, 0x6c, 0x61, 0x75, 0x6e, 0x63,
+	0x68, 0x12, 0x18, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e, 0x4c, 0x61,
+	0x75, 0x6e, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x6d, 0x75,
+	0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e, 0x4c, 0x61, 0x75, 0x6e, 0x63, 0x68, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x30, 0x01, 0x12, 0x36, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x16, 0x2e,
+	0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73,
+	0x73, 0x2e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x30, 0x01, 0x12, 0x36, 0x0a,
+	0x04, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x16, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73,
+	0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e,
+	0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x30, 0x01, 0x12, 0x39, 0x0a, 0x05, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x17,
+	0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e, 0x4d, 0x6f, 0x75, 0x6e, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70,
+	0x61, 0x73, 0x73, 0x2e, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x30, 0x01,
+	0x12, 0x40, 0x0a, 0x08, 0x73, 0x73, 0x68, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x19, 0x2e, 0x6d,
+	0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e, 0x53, 0x53, 0x48, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70,
+	0x61, 0x73, 0x73, 0x2e, 0x53, 0x53, 0x48, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x30, 0x01, 0x12, 0x39, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x17, 0x2e, 0x6d, 0x75,
+	0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73,
+	0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x30, 0x01, 0x12, 0x36, 0x0a,
+	0x04, 0x73, 0x74, 0x6f, 0x70, 0x12, 0x16, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73,
+	0x73, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e,
+	0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x30, 0x01, 0x12, 0x3b, 0x0a, 0x05, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x12, 0x18,
+	0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x6d, 0x75, 0x6c, 0x74, 0x69,
+	0x70, 0x61, 0x73, 0x73, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x30, 0x01, 0x42, 0x4c, 0x5a, 0x4a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x46, 0x72, 0x65, 0x64, 0x37, 0x38, 0x32, 0x39, 0x30, 0x2f, 0x6b, 0x75, 0x62, 0x65, 0x72,
+	0x6e, 0x65, 0x74, 0x65, 0x73, 0x2d, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x2d,
+	0x61, 0x75, 0x74, 0x6f, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x72, 0x2f, 0x6d, 0x75, 0x6c, 0x74, 0x69,
+	0x70, 0x61, 0x73, 0x73, 0x64, 0x3b, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x73, 0x73, 0x64,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_multipass_proto_rawDescOnce sync.Once
+	file_multipass_proto_rawDescData = file_multipass_proto_rawDesc
+)
+
+func file_multipass_proto_rawDescGZIP() []byte {
+	file_multipass_proto_rawDescOnce.Do(func() {
+		file_multipass_proto_rawDescData = protoimpl.X.CompressGZIP(file_multipass_proto_rawDescData)
+	})
+	return file_multipass_proto_rawDescData
+}
+
+var file_multipass_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_multipass_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_multipass_proto_goTypes = []interface{}{
+	(LaunchProgress_ProgressTypes)(0), // 0: multipass.LaunchProgress.ProgressTypes
+	(InstanceStatus_Status)(0),        // 1: multipass.InstanceStatus.Status
+	(*LaunchRequest)(nil),             // 2: multipass.LaunchRequest
+	(*LaunchProgress)(nil),            // 3: multipass.LaunchProgress
+	(*LaunchReply)(nil),               // 4: multipass.LaunchReply
+	(*InstanceNames)(nil),             // 5: multipass.InstanceNames
+	(*InstanceStatus)(nil),            // 6: multipass.InstanceStatus
+	(*InfoRequest)(nil),               // 7: multipass.InfoRequest
+	(*InfoReply)(nil),                 // 8: multipass.InfoReply
+	(*ListRequest)(nil),               // 9: multipass.ListRequest
+	(*ListVMInstance)(nil),            // 10: multipass.ListVMInstance
+	(*ListReply)(nil),                 // 11: multipass.ListReply
+	(*TargetPathInfo)(nil),            // 12: multipass.TargetPathInfo
+	(*MountRequest)(nil),              // 13: multipass.MountRequest
+	(*MountReply)(nil),                // 14: multipass.MountReply
+	(*SSHInfoRequest)(nil),            // 15: multipass.SSHInfoRequest
+	(*SSHInfo)(nil),                   // 16: multipass.SSHInfo
+	(*SSHInfoReply)(nil),              // 17: multipass.SSHInfoReply
+	(*StartRequest)(nil),              // 18: multipass.StartRequest
+	(*StartReply)(nil),                // 19: multipass.StartReply
+	(*StopRequest)(nil),               // 20: multipass.StopRequest
+	(*StopReply)(nil),                 // 21: multipass.StopReply
+	(*DeleteRequest)(nil),             // 22: multipass.DeleteRequest
+	(*DeleteReply)(nil),               // 23: multipass.DeleteReply
+	(*InfoReply_Info)(nil),            // 24: multipass.InfoReply.Info
+	nil,                               // 25: multipass.SSHInfoReply.SshInfoEntry
+}
+var file_multipass_proto_depIdxs = []int32{
+	0,  // 0: multipass.LaunchProgress.type:type_name -> multipass.LaunchProgress.ProgressTypes
+	3,  // 1: multipass.LaunchReply.launch_progress:type_name -> multipass.LaunchProgress
+	1,  // 2: multipass.InstanceStatus.status:type_name -> multipass.InstanceStatus.Status
+	5,  // 3: multipass.InfoRequest.instance_names:type_name -> multipass.InstanceNames
+	24, // 4: multipass.InfoReply.info:type_name -> multipass.InfoReply.Info
+	6,  // 5: multipass.ListVMInstance.instance_status:type_name -> multipass.InstanceStatus
+	10, // 6: multipass.ListReply.instances:type_name -> multipass.ListVMInstance
+	12, // 7: multipass.MountRequest.target_paths:type_name -> multipass.TargetPathInfo
+	25, // 8: multipass.SSHInfoReply.ssh_info:type_name -> multipass.SSHInfoReply.SshInfoEntry
+	5,  // 9: multipass.StartRequest.instance_names:type_name -> multipass.InstanceNames
+	5,  // 10: multipass.StopRequest.instance_names:type_name -> multipass.InstanceNames
+	5,  // 11: multipass.DeleteRequest.instance_names:type_name -> multipass.InstanceNames
+	6,  // 12: multipass.InfoReply.Info.instance_status:type_name -> multipass.InstanceStatus
+	16, // 13: multipass.SSHInfoReply.SshInfoEntry.value:type_name -> multipass.SSHInfo
+	2,  // 14: multipass.Rpc.launch:input_type -> multipass.LaunchRequest
+	7,  // 15: multipass.Rpc.info:input_type -> multipass.InfoRequest
+	9,  // 16: multipass.Rpc.list:input_type -> multipass.ListRequest
+	13, // 17: multipass.Rpc.mount:input_type -> multipass.MountRequest
+	15, // 18: multipass.Rpc.ssh_info:input_type -> multipass.SSHInfoRequest
+	18, // 19: multipass.Rpc.start:input_type -> multipass.StartRequest
+	20, // 20: multipass.Rpc.stop:input_type -> multipass.StopRequest
+	22, // 21: multipass.Rpc.delet:input_type -> multipass.DeleteRequest
+	4,  // 22: multipass.Rpc.launch:output_type -> multipass.LaunchReply
+	8,  // 23: multipass.Rpc.info:output_type -> multipass.InfoReply
+	11, // 24: multipass.Rpc.list:output_type -> multipass.ListReply
+	14, // 25: multipass.Rpc.mount:output_type -> multipass.MountReply
+	17, // 26: multipass.Rpc.ssh_info:output_type -> multipass.SSHInfoReply
+	19, // 27: multipass.Rpc.start:output_type -> multipass.StartReply
+	21, // 28: multipass.Rpc.stop:output_type -> multipass.StopReply
+	23, // 29: multipass.Rpc.delet:output_type -> multipass.DeleteReply
+	22, // [22:30] is the sub-list for method output_type
+	14, // [14:22] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_multipass_proto_init() }
+func file_multipass_proto_init() {
+	if File_multipass_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_multipass_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LaunchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LaunchProgress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LaunchReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InstanceNames); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InstanceStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InfoReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListVMInstance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TargetPathInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MountRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MountReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SSHInfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SSHInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SSHInfoReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StartRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StartReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StopRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StopReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_multipass_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InfoReply_Info); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_multipass_proto_msgTypes[2].OneofWrappers = []interface{}{
+		(*LaunchReply_VmInstanceName)(nil),
+		(*LaunchReply_LaunchProgress)(nil),
+		(*LaunchReply_CreateMessage)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_multipass_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   24,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_multipass_proto_goTypes,
+		DependencyIndexes: file_multipass_proto_depIdxs,
+		EnumInfos:         file_multipass_proto_enumTypes,
+		MessageInfos:      file_multipass_proto_msgTypes,
+	}.Build()
+	File_multipass_proto = out.File
+	file_multipass_proto_rawDesc = nil
+	file_multipass_proto_goTypes = nil
+	file_multipass_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// RpcClient is the client API for Rpc service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type RpcClient interface {
+	Launch(ctx context.Context, in *LaunchRequest, opts ...grpc.CallOption) (Rpc_LaunchClient, error)
+	Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (Rpc_InfoClient, error)
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (Rpc_ListClient, error)
+	Mount(ctx context.Context, in *MountRequest, opts ...grpc.CallOption) (Rpc_MountClient, error)
+	SshInfo(ctx context.Context, in *SSHInfoRequest, opts ...grpc.CallOption) (Rpc_SshInfoClient, error)
+	Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (Rpc_StartClient, error)
+	Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (Rpc_StopClient, error)
+	Delet(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (Rpc_DeletClient, error)
+}
+
+type rpcClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRpcClient(cc grpc.ClientConnInterface) RpcClient {
+	return &rpcClient{cc}
+}
+
+func (c *rpcClient) Launch(ctx context.Context, in *LaunchRequest, opts ...grpc.CallOption) (Rpc_LaunchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Rpc_serviceDesc.Streams[0], "/multipass.Rpc/launch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &rpcLaunchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Rpc_LaunchClient interface {
+	Recv() (*LaunchReply, error)
+	grpc.ClientStream
+}
+
+type rpcLaunchClient struct {
+	grpc.ClientStream
+}
+
+func (x *rpcLaunchClient) Recv() (*LaunchReply, error) {
+	m := new(LaunchReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *rpcClient) Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (Rpc_InfoClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Rpc_serviceDesc.Streams[1], "/multipass.Rpc/info", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &rpcInfoClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Rpc_InfoClient interface {
+	Recv() (*InfoReply, error)
+	grpc.ClientStream
+}
+
+type rpcInfoClient struct {
+	grpc.ClientStream
+}
+
+func (x *rpcInfoClient) Recv() (*InfoReply, error) {
+	m := new(InfoReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *rpcClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (Rpc_ListClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Rpc_serviceDesc.Streams[2], "/multipass.Rpc/list", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &rpcListClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Rpc_ListClient interface {
+	Recv() (*ListReply, error)
+	grpc.ClientStream
+}
+
+type rpcListClient struct {
+	grpc.ClientStream
+}
+
+func (x *rpcListClient) Recv() (*ListReply, error) {
+	m := new(ListReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *rpcClient) Mount(ctx context.Context, in *MountRequest, opts ...grpc.CallOption) (Rpc_MountClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Rpc_serviceDesc.Streams[3], "/multipass.Rpc/mount", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &rpcMountClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Rpc_MountClient interface {
+	Recv() (*MountReply, error)
+	grpc.ClientStream
+}
+
+type rpcMountClient struct {
+	grpc.ClientStream
+}
+
+func (x *rpcMountClient) Recv() (*MountReply, error) {
+	m := new(MountReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *rpcClient) SshInfo(ctx context.Context, in *SSHInfoRequest, opts ...grpc.CallOption) (Rpc_SshInfoClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Rpc_serviceDesc.Streams[4], "/multipass.Rpc/ssh_info", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &rpcSshInfoClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Rpc_SshInfoClient interface {
+	Recv() (*SSHInfoReply, error)
+	grpc.ClientStream
+}
+
+type rpcSshInfoClient struct {
+	grpc.ClientStream
+}
+
+func (x *rpcSshInfoClient) Recv() (*SSHInfoReply, error) {
+	m := new(SSHInfoReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *rpcClient) Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (Rpc_StartClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Rpc_serviceDesc.Streams[5], "/multipass.Rpc/start", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &rpcStartClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Rpc_StartClient interface {
+	Recv() (*StartReply, error)
+	grpc.ClientStream
+}
+
+type rpcStartClient struct {
+	grpc.ClientStream
+}
+
+func (x *rpcStartClient) Recv() (*StartReply, error) {
+	m := new(StartReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *rpcClient) Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (Rpc_StopClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Rpc_serviceDesc.Streams[6], "/multipass.Rpc/stop", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &rpcStopClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Rpc_StopClient interface {
+	Recv() (*StopReply, error)
+	grpc.ClientStream
+}
+
+type rpcStopClient struct {
+	grpc.ClientStream
+}
+
+func (x *rpcStopClient) Recv() (*StopReply, error) {
+	m := new(StopReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *rpcClient) Delet(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (Rpc_DeletClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Rpc_serviceDesc.Streams[7], "/multipass.Rpc/delet", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &rpcDeletClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Rpc_DeletClient interface {
+	Recv() (*DeleteReply, error)
+	grpc.ClientStream
+}
+
+type rpcDeletClient struct {
+	grpc.ClientStream
+}
+
+func (x *rpcDeletClient) Recv() (*DeleteReply, error) {
+	m := new(DeleteReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RpcServer is the server API for Rpc service.
+type RpcServer interface {
+	Launch(*LaunchRequest, Rpc_LaunchServer) error
+	Info(*InfoRequest, Rpc_InfoServer) error
+	List(*ListRequest, Rpc_ListServer) error
+	Mount(*MountRequest, Rpc_MountServer) error
+	SshInfo(*SSHInfoRequest, Rpc_SshInfoServer) error
+	Start(*StartRequest, Rpc_StartServer) error
+	Stop(*StopRequest, Rpc_StopServer) error
+	Delet(*DeleteRequest, Rpc_DeletServer) error
+}
+
+// UnimplementedRpcServer can be embedded to have forward compatible implementations.
+type UnimplementedRpcServer struct {
+}
+
+func (*UnimplementedRpcServer) Launch(*LaunchRequest, Rpc_LaunchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Launch not implemented")
+}
+func (*UnimplementedRpcServer) Info(*InfoRequest, Rpc_InfoServer) error {
+	return status.Errorf(codes.Unimplemented, "method Info not implemented")
+}
+func (*UnimplementedRpcServer) List(*ListRequest, Rpc_ListServer) error {
+	return status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (*UnimplementedRpcServer) Mount(*MountRequest, Rpc_MountServer) error {
+	return status.Errorf(codes.Unimplemented, "method Mount not implemented")
+}
+func (*UnimplementedRpcServer) SshInfo(*SSHInfoRequest, Rpc_SshInfoServer) error {
+	return status.Errorf(codes.Unimplemented, "method SshInfo not implemented")
+}
+func (*UnimplementedRpcServer) Start(*StartRequest, Rpc_StartServer) error {
+	return status.Errorf(codes.Unimplemented, "method Start not implemented")
+}
+func (*UnimplementedRpcServer) Stop(*StopRequest, Rpc_StopServer) error {
+	return status.Errorf(codes.Unimplemented, "method Stop not implemented")
+}
+func (*UnimplementedRpcServer) Delet(*DeleteRequest, Rpc_DeletServer) error {
+	return status.Errorf(codes.Unimplemented, "method Delet not implemented")
+}
+
+func RegisterRpcServer(s *grpc.Server, srv RpcServer) {
+	s.RegisterService(&_Rpc_serviceDesc, srv)
+}
+
+func _Rpc_Launch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LaunchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RpcServer).Launch(m, &rpcLaunchServer{stream})
+}
+
+type Rpc_LaunchServer interface {
+	Send(*LaunchReply) error
+	grpc.ServerStream
+}
+
+type rpcLaunchServer struct {
+	grpc.ServerStream
+}
+
+func (x *rpcLaunchServer) Send(m *LaunchReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Rpc_Info_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InfoRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RpcServer).Info(m, &rpcInfoServer{stream})
+}
+
+type Rpc_InfoServer interface {
+	Send(*InfoReply) error
+	grpc.ServerStream
+}
+
+type rpcInfoServer struct {
+	grpc.ServerStream
+}
+
+func (x *rpcInfoServer) Send(m *InfoReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Rpc_List_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RpcServer).List(m, &rpcListServer{stream})
+}
+
+type Rpc_ListServer interface {
+	Send(*ListReply) error
+	grpc.ServerStream
+}
+
+type rpcListServer struct {
+	grpc.ServerStream
+}
+
+func (x *rpcListServer) Send(m *ListReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Rpc_Mount_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MountRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RpcServer).Mount(m, &rpcMountServer{stream})
+}
+
+type Rpc_MountServer interface {
+	Send(*MountReply) error
+	grpc.ServerStream
+}
+
+type rpcMountServer struct {
+	grpc.ServerStream
+}
+
+func (x *rpcMountServer) Send(m *MountReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Rpc_SshInfo_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SSHInfoRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RpcServer).SshInfo(m, &rpcSshInfoServer{stream})
+}
+
+type Rpc_SshInfoServer interface {
+	Send(*SSHInfoReply) error
+	grpc.ServerStream
+}
+
+type rpcSshInfoServer struct {
+	grpc.ServerStream
+}
+
+func (x *rpcSshInfoServer) Send(m *SSHInfoReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Rpc_Start_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StartRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RpcServer).Start(m, &rpcStartServer{stream})
+}
+
+type Rpc_StartServer interface {
+	Send(*StartReply) error
+	grpc.ServerStream
+}
+
+type rpcStartServer struct {
+	grpc.ServerStream
+}
+
+func (x *rpcStartServer) Send(m *StartReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Rpc_Stop_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StopRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RpcServer).Stop(m, &rpcStopServer{stream})
+}
+
+type Rpc_StopServer interface {
+	Send(*StopReply) error
+	grpc.ServerStream
+}
+
+type rpcStopServer struct {
+	grpc.ServerStream
+}
+
+func (x *rpcStopServer) Send(m *StopReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Rpc_Delet_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DeleteRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RpcServer).Delet(m, &rpcDeletServer{stream})
+}
+
+type Rpc_DeletServer interface {
+	Send(*DeleteReply) error
+	grpc.ServerStream
+}
+
+type rpcDeletServer struct {
+	grpc.ServerStream
+}
+
+func (x *rpcDeletServer) Send(m *DeleteReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Rpc_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "multipass.Rpc",
+	HandlerType: (*RpcServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "launch",
+			Handler:       _Rpc_Launch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "info",
+			Handler:       _Rpc_Info_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "list",
+			Handler:       _Rpc_List_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "mount",
+			Handler:       _Rpc_Mount_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ssh_info",
+			Handler:       _Rpc_SshInfo_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "start",
+			Handler:       _Rpc_Start_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "stop",
+			Handler:       _Rpc_Stop_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "delet",
+			Handler:       _Rpc_Delet_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "multipass.proto",
+}
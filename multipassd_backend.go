@@ -0,0 +1,545 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Fred78290/kubernetes-multipass-autoscaler/multipassd"
+	"github.com/golang/glog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	backendMultipassd = "multipassd"
+
+	defaultMultipassdAddress = "unix:/var/snap/multipass/common/multipass_socket"
+)
+
+// MultipassdConfig declare how to reach the multipass daemon
+type MultipassdConfig struct {
+	Address    string `json:"address"`     // Optional, daemon address, unix:/path or host:port, the snap socket by default
+	ClientCert string `json:"client-cert"` // Optional, client certificate registered in multipassd, the connection is always TLS
+	ClientKey  string `json:"client-key"`  // Optional, client certificate key
+}
+
+// multipassdBackend talk to multipassd over gRPC, the multipass command line is used when the daemon can't be reached
+type multipassdBackend struct {
+	sync.Mutex
+	config   *MultipassdConfig
+	cacheDir string
+	conn     *grpc.ClientConn
+	client   multipassd.RpcClient
+	fallback VMBackend
+	degraded bool // the command line is used until the daemon answers
+}
+
+func newMultipassdBackend(config *MultipassdConfig, cacheDir string) *multipassdBackend {
+	if config == nil {
+		config = &MultipassdConfig{}
+	}
+
+	return &multipassdBackend{
+		config:   config,
+		cacheDir: cacheDir,
+		fallback: newMultipassBackend(cacheDir),
+	}
+}
+
+// rpc returns the daemon client, the connection is lazy and shared for the process life, gRPC reconnect it when the daemon restart
+func (b *multipassdBackend) rpc() (multipassd.RpcClient, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.client != nil {
+		return b.client, nil
+	}
+
+	address := b.config.Address
+	options := []grpc.DialOption{}
+
+	if len(address) == 0 {
+		address = defaultMultipassdAddress
+	}
+
+	if strings.HasPrefix(address, "unix:") {
+		socket := strings.TrimPrefix(address, "unix:")
+
+		options = append(options, grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+
+			return dialer.DialContext(ctx, "unix", socket)
+		}))
+	}
+
+	// multipassd serve TLS with a self signed certificate, even on its unix socket
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: true,
+	}
+
+	if len(b.config.ClientCert) > 0 {
+		cert, err := tls.LoadX509KeyPair(b.config.ClientCert, b.config.ClientKey)
+
+		if err != nil {
+			return nil, wrapError(kindInvalidArgument, err, errMultipassdConnectFailed, address, err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	options = append(options, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+
+	conn, err := grpc.Dial(address, options...)
+
+	if err != nil {
+		return nil, wrapError(kindVM, err, errMultipassdConnectFailed, address, err)
+	}
+
+	b.conn = conn
+	b.client = multipassd.NewRpcClient(conn)
+
+	return b.client, nil
+}
+
+// unreachable tells if the command line must be used, the daemon is down or doesn't know the call.
+// Nothing is remembered, the next call try again the daemon, it could be restarted or upgraded
+func unreachable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Unimplemented:
+		return true
+	}
+
+	return false
+}
+
+// multipassdError classify the daemon error
+func multipassdError(err error, name string) error {
+	s, _ := status.FromError(err)
+
+	switch {
+	case s.Code() == codes.NotFound || strings.Contains(s.Message(), "does not exist"):
+		return wrapError(kindNotFound, err, errVMNotFound, name)
+	case s.Code() == codes.AlreadyExists || strings.Contains(s.Message(), "already exists"):
+		return wrapError(kindAlreadyExists, err, errVMAlreadyCreated, name)
+	case s.Code() == codes.DeadlineExceeded || s.Code() == codes.Canceled:
+		return wrapError(kindTimeout, err, errMultipassdCallFailed, name, s.Message())
+	case s.Code() == codes.Unavailable:
+		return wrapError(kindTransient, err, errMultipassdCallFailed, name, s.Message())
+	}
+
+	return wrapError(kindVM, err, errMultipassdCallFailed, name, s.Message())
+}
+
+// reconnect don't wait the connection backoff, the next call redial the daemon at once
+func (b *multipassdBackend) reconnect() {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.conn != nil {
+		b.conn.ResetConnectBackoff()
+	}
+}
+
+// degrade log once at error level that the command line replace the daemon, until it answers again
+func (b *multipassdBackend) degrade(err error) {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.degraded {
+		glog.V(5).Infof("multipassd unreachable, use multipass command line, reason: %v", err)
+	} else {
+		glog.Errorf("multipassd unreachable, the multipass command line is used until it answers, reason: %v", err)
+	}
+
+	b.degraded = true
+}
+
+// restore log that the daemon answers again
+func (b *multipassdBackend) restore() {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.degraded {
+		glog.Infof("multipassd answers again, the multipass command line is no more used")
+	}
+
+	b.degraded = false
+}
+
+// call run the daemon call, or the fallback when the daemon is unreachable. The errors already classified by rpc are returned as is
+func (b *multipassdBackend) call(name string, rpc func(multipassd.RpcClient) error, fallback func() error) error {
+	client, err := b.rpc()
+
+	if err == nil {
+		if err = rpc(client); err == nil {
+			b.restore()
+
+			return nil
+		}
+	}
+
+	if kindOf(err) != nil {
+		return err
+	}
+
+	if status.Code(err) == codes.Unavailable {
+		b.reconnect()
+	}
+
+	if unreachable(err) {
+		b.degrade(err)
+
+		return fallback()
+	}
+
+	return multipassdError(err, name)
+}
+
+// receive read the reply stream until the end, log lines are traced
+func receive(recv func() (string, error)) error {
+	for {
+		logLine, err := recv()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if len(logLine) > 0 {
+			glog.V(5).Infof("multipassd: %s", logLine)
+		}
+	}
+}
+
+func instanceNames(name string) *multipassd.InstanceNames {
+	return &multipassd.InstanceNames{
+		InstanceName: []string{name},
+	}
+}
+
+func (b *multipassdBackend) Launch(ctx context.Context, spec *VMLaunchSpec) error {
+	request := &multipassd.LaunchRequest{
+		InstanceName: spec.Name,
+		Image:        spec.Image,
+		NumCores:     int32(spec.CPU),
+	}
+
+	// remote:image like multipass launch, URL are kept as is
+	if !strings.Contains(spec.Image, "://") && strings.Contains(spec.Image, ":") {
+		parts := strings.SplitN(spec.Image, ":", 2)
+
+		request.RemoteName = parts[0]
+		request.Image = parts[1]
+	}
+
+	if spec.Memory > 0 {
+		request.MemSize = fmt.Sprintf("%dM", spec.Memory)
+	}
+
+	if spec.Disk > 0 {
+		request.DiskSpace = fmt.Sprintf("%dM", spec.Disk)
+	}
+
+	if spec.hasUserData() {
+		userData, err := spec.userData()
+
+		if err != nil {
+			return err
+		}
+
+		request.CloudInitUserData = string(userData)
+	}
+
+	return b.call(spec.Name, func(client multipassd.RpcClient) error {
+		stream, err := client.Launch(ctx, request)
+
+		if err != nil {
+			return err
+		}
+
+		// The daemon could be launching the VM, the command line would launch it twice
+		if err = receive(func() (string, error) {
+			reply, err := stream.Recv()
+
+			return reply.GetLogLine(), err
+		}); err != nil {
+			return multipassdError(err, spec.Name)
+		}
+
+		return nil
+	}, func() error {
+		return b.fallback.Launch(ctx, spec)
+	})
+}
+
+func (b *multipassdBackend) Start(ctx context.Context, name string) error {
+	return b.call(name, func(client multipassd.RpcClient) error {
+		stream, err := client.Start(ctx, &multipassd.StartRequest{InstanceNames: instanceNames(name)})
+
+		if err != nil {
+			return err
+		}
+
+		return receive(func() (string, error) {
+			reply, err := stream.Recv()
+
+			return reply.GetLogLine(), err
+		})
+	}, func() error {
+		return b.fallback.Start(ctx, name)
+	})
+}
+
+func (b *multipassdBackend) Stop(ctx context.Context, name string) error {
+	return b.call(name, func(client multipassd.RpcClient) error {
+		stream, err := client.Stop(ctx, &multipassd.StopRequest{InstanceNames: instanceNames(name)})
+
+		if err != nil {
+			return err
+		}
+
+		return receive(func() (string, error) {
+			reply, err := stream.Recv()
+
+			return reply.GetLogLine(), err
+		})
+	}, func() error {
+		return b.fallback.Stop(ctx, name)
+	})
+}
+
+func (b *multipassdBackend) Delete(ctx context.Context, name string) error {
+	return b.call(name, func(client multipassd.RpcClient) error {
+		stream, err := client.Delet(ctx, &multipassd.DeleteRequest{InstanceNames: instanceNames(name), Purge: true})
+
+		if err != nil {
+			return err
+		}
+
+		return receive(func() (string, error) {
+			reply, err := stream.Recv()
+
+			return reply.GetLogLine(), err
+		})
+	}, func() error {
+		return b.fallback.Delete(ctx, name)
+	})
+}
+
+func (b *multipassdBackend) Status(ctx context.Context, name string) (*VMStatus, error) {
+	var vmStatus *VMStatus
+
+	err := b.call(name, func(client multipassd.RpcClient) error {
+		stream, err := client.Info(ctx, &multipassd.InfoRequest{InstanceNames: instanceNames(name)})
+
+		if err != nil {
+			return err
+		}
+
+		return receive(func() (string, error) {
+			reply, err := stream.Recv()
+
+			for _, info := range reply.GetInfo() {
+				if info.GetName() == name {
+					vmStatus = &VMStatus{
+						State:     multipassdState(name, info.GetInstanceStatus().GetStatus()),
+						Addresses: info.GetIpv4(),
+					}
+				}
+			}
+
+			return reply.GetLogLine(), err
+		})
+	}, func() (err error) {
+		vmStatus, err = b.fallback.Status(ctx, name)
+
+		return err
+	})
+
+	if err == nil && vmStatus == nil {
+		err = newError(kindNotFound, errMultiPassInfoNotFound, name)
+	}
+
+	return vmStatus, err
+}
+
+// multipassdState convert the daemon instance status
+func multipassdState(name string, status multipassd.InstanceStatus_Status) MultipassNodeState {
+	switch status {
+	case multipassd.InstanceStatus_RUNNING:
+		return MultipassNodeStateRunning
+	case multipassd.InstanceStatus_STOPPED:
+		return MultipassNodeStateStopped
+	case multipassd.InstanceStatus_DELETED:
+		return MultipassNodeStateDeleted
+	}
+
+	glog.Infof(errVMStateUndefined, name, status)
+
+	return MultipassNodeStateUndefined
+}
+
+// remote returns the ssh access to the instance given by the daemon, the caller must remove the key file
+func (b *multipassdBackend) remote(ctx context.Context, client multipassd.RpcClient, name string) (*sshRemote, string, error) {
+	var info *multipassd.SSHInfo
+
+	stream, err := client.SshInfo(ctx, &multipassd.SSHInfoRequest{InstanceName: []string{name}})
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = receive(func() (string, error) {
+		reply, err := stream.Recv()
+
+		if found := reply.GetSshInfo()[name]; found != nil {
+			info = found
+		}
+
+		return reply.GetLogLine(), err
+	})
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if info == nil {
+		return nil, "", status.Error(codes.NotFound, name)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(info.GetPrivKeyBase64())
+
+	if err != nil {
+		return nil, "", wrapError(kindInternal, err, errMultipassdCallFailed, name, err)
+	}
+
+	keyFile, err := ioutil.TempFile(b.cacheDir, "ssh-key-")
+
+	if err != nil {
+		return nil, "", wrapError(kindInternal, err, errTempFile, err)
+	}
+
+	defer keyFile.Close()
+
+	if _, err = keyFile.Write(key); err != nil {
+		os.Remove(keyFile.Name())
+
+		return nil, "", wrapError(kindInternal, err, errTempFile, err)
+	}
+
+	return &sshRemote{
+		user: info.GetUsername(),
+		key:  keyFile.Name(),
+		port: int(info.GetPort()),
+	}, info.GetHost(), nil
+}
+
+// Exec run the command with ssh, multipassd only give the ssh access to the instance
+func (b *multipassdBackend) Exec(ctx context.Context, name string, args ...string) (string, error) {
+	var out string
+
+	err := b.call(name, func(client multipassd.RpcClient) error {
+		remote, host, err := b.remote(ctx, client, name)
+
+		if err != nil {
+			return err
+		}
+
+		defer os.Remove(remote.key)
+
+		out, err = remote.exec(ctx, host, args...)
+
+		return err
+	}, func() (err error) {
+		out, err = b.fallback.Exec(ctx, name, args...)
+
+		return err
+	})
+
+	return out, err
+}
+
+func (b *multipassdBackend) CopyFile(ctx context.Context, name, src, dst string) error {
+	return b.call(name, func(client multipassd.RpcClient) error {
+		remote, host, err := b.remote(ctx, client, name)
+
+		if err != nil {
+			return err
+		}
+
+		defer os.Remove(remote.key)
+
+		return remote.copy(ctx, host, src, dst)
+	}, func() error {
+		return b.fallback.CopyFile(ctx, name, src, dst)
+	})
+}
+
+func (b *multipassdBackend) Mount(ctx context.Context, name, hostPath, guestPath string) error {
+	return b.call(name, func(client multipassd.RpcClient) error {
+		stream, err := client.Mount(ctx, &multipassd.MountRequest{
+			SourcePath: hostPath,
+			TargetPaths: []*multipassd.TargetPathInfo{
+				{
+					InstanceName: name,
+					TargetPath:   guestPath,
+				},
+			},
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return receive(func() (string, error) {
+			reply, err := stream.Recv()
+
+			return reply.GetLogLine(), err
+		})
+	}, func() error {
+		return b.fallback.Mount(ctx, name, hostPath, guestPath)
+	})
+}
+
+func (b *multipassdBackend) List(ctx context.Context) ([]string, error) {
+	var names []string
+
+	err := b.call("", func(client multipassd.RpcClient) error {
+		stream, err := client.List(ctx, &multipassd.ListRequest{})
+
+		if err != nil {
+			return err
+		}
+
+		names = []string{}
+
+		return receive(func() (string, error) {
+			reply, err := stream.Recv()
+
+			for _, instance := range reply.GetInstances() {
+				names = append(names, instance.GetName())
+			}
+
+			return reply.GetLogLine(), err
+		})
+	}, func() (err error) {
+		names, err = b.fallback.List(ctx)
+
+		return err
+	})
+
+	return names, err
+}
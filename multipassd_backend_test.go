@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Fred78290/kubernetes-multipass-autoscaler/multipassd"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// fakeMultipassd is an in memory multipassd listening on a unix socket, mount is not implemented
+type fakeMultipassd struct {
+	multipassd.UnimplementedRpcServer
+	sync.Mutex
+	socket    string
+	broken    bool // the launch stream is broken after the first log line
+	requests  map[string]*multipassd.LaunchRequest
+	instances map[string]multipassd.InstanceStatus_Status
+}
+
+func newSelfSignedCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "multipassd"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}
+}
+
+func newFakeMultipassd(t *testing.T) *fakeMultipassd {
+	dir, err := ioutil.TempDir("", "multipassd")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &fakeMultipassd{
+		socket:    path.Join(dir, "multipass_socket"),
+		requests:  make(map[string]*multipassd.LaunchRequest),
+		instances: make(map[string]multipassd.InstanceStatus_Status),
+	}
+
+	listener, err := net.Listen("unix", f.socket)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Like multipassd, TLS with a self signed certificate on the unix socket
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{newSelfSignedCertificate(t)},
+	})))
+
+	multipassd.RegisterRpcServer(server, f)
+
+	go server.Serve(listener)
+
+	t.Cleanup(func() {
+		server.Stop()
+		os.RemoveAll(dir)
+	})
+
+	return f
+}
+
+func (f *fakeMultipassd) setStatus(names *multipassd.InstanceNames, status multipassd.InstanceStatus_Status) error {
+	f.Lock()
+	defer f.Unlock()
+
+	for _, name := range names.GetInstanceName() {
+		if _, found := f.instances[name]; !found {
+			return grpcNotFound(name)
+		}
+
+		f.instances[name] = status
+	}
+
+	return nil
+}
+
+func grpcNotFound(name string) error {
+	return status.Errorf(codes.NotFound, "instance \"%s\" does not exist", name)
+}
+
+func (f *fakeMultipassd) Launch(request *multipassd.LaunchRequest, stream multipassd.Rpc_LaunchServer) error {
+	f.Lock()
+	defer f.Unlock()
+
+	if _, found := f.instances[request.InstanceName]; found {
+		return status.Errorf(codes.InvalidArgument, "instance \"%s\" already exists", request.InstanceName)
+	}
+
+	f.requests[request.InstanceName] = request
+	f.instances[request.InstanceName] = multipassd.InstanceStatus_RUNNING
+
+	stream.Send(&multipassd.LaunchReply{LogLine: "Retrieving image"})
+
+	if f.broken {
+		return status.Error(codes.Unavailable, "transport is closing")
+	}
+
+	return stream.Send(&multipassd.LaunchReply{
+		CreateOneof: &multipassd.LaunchReply_VmInstanceName{VmInstanceName: request.InstanceName},
+	})
+}
+
+func (f *fakeMultipassd) Info(request *multipassd.InfoRequest, stream multipassd.Rpc_InfoServer) error {
+	f.Lock()
+	defer f.Unlock()
+
+	reply := &multipassd.InfoReply{}
+
+	for _, name := range request.GetInstanceNames().GetInstanceName() {
+		state, found := f.instances[name]
+
+		if !found {
+			return grpcNotFound(name)
+		}
+
+		info := &multipassd.InfoReply_Info{
+			Name:           name,
+			InstanceStatus: &multipassd.InstanceStatus{Status: state},
+		}
+
+		if state == multipassd.InstanceStatus_RUNNING {
+			info.Ipv4 = []string{"10.0.0.4"}
+		}
+
+		reply.Info = append(reply.Info, info)
+	}
+
+	return stream.Send(reply)
+}
+
+func (f *fakeMultipassd) List(request *multipassd.ListRequest, stream multipassd.Rpc_ListServer) error {
+	f.Lock()
+	defer f.Unlock()
+
+	reply := &multipassd.ListReply{}
+
+	for name, state := range f.instances {
+		reply.Instances = append(reply.Instances, &multipassd.ListVMInstance{
+			Name:           name,
+			InstanceStatus: &multipassd.InstanceStatus{Status: state},
+		})
+	}
+
+	return stream.Send(reply)
+}
+
+func (f *fakeMultipassd) Start(request *multipassd.StartRequest, stream multipassd.Rpc_StartServer) error {
+	return f.setStatus(request.GetInstanceNames(), multipassd.InstanceStatus_RUNNING)
+}
+
+func (f *fakeMultipassd) Stop(request *multipassd.StopRequest, stream multipassd.Rpc_StopServer) error {
+	return f.setStatus(request.GetInstanceNames(), multipassd.InstanceStatus_STOPPED)
+}
+
+func (f *fakeMultipassd) Delet(request *multipassd.DeleteRequest, stream multipassd.Rpc_DeletServer) error {
+	f.Lock()
+	defer f.Unlock()
+
+	for _, name := range request.GetInstanceNames().GetInstanceName() {
+		if _, found := f.instances[name]; !found {
+			return grpcNotFound(name)
+		}
+
+		delete(f.instances, name)
+	}
+
+	return stream.Send(&multipassd.DeleteReply{PurgedInstances: request.GetInstanceNames().GetInstanceName()})
+}
+
+func Test_multipassdBackend(t *testing.T) {
+	f := newFakeMultipassd(t)
+	fallback := newFakeBackend()
+	b := newMultipassdBackend(&MultipassdConfig{Address: "unix:" + f.socket}, os.TempDir())
+	ctx := context.Background()
+
+	b.fallback = fallback
+	b.degraded = true
+
+	spec := &VMLaunchSpec{
+		Name:   testNodeName,
+		Memory: 2048,
+		CPU:    2,
+		Disk:   5120,
+		Image:  "daily:focal",
+		CloudInit: map[string]interface{}{
+			"package_update": true,
+		},
+	}
+
+	if !assert.NoError(t, b.Launch(ctx, spec)) {
+		return
+	}
+
+	request := f.requests[testNodeName]
+
+	assert.False(t, b.degraded, "the daemon answers over TLS")
+	assert.Empty(t, fallback.calls)
+
+	assert.Equal(t, int32(2), request.NumCores)
+	assert.Equal(t, "2048M", request.MemSize)
+	assert.Equal(t, "5120M", request.DiskSpace)
+	assert.Equal(t, "daily", request.RemoteName)
+	assert.Equal(t, "focal", request.Image)
+	assert.Contains(t, request.CloudInitUserData, "package_update: true")
+
+	assert.Equal(t, codes.AlreadyExists, kindOf(b.Launch(ctx, spec)).code)
+
+	status, err := b.Status(ctx, testNodeName)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, MultipassNodeStateRunning, status.State)
+		assert.Equal(t, []string{"10.0.0.4"}, status.Addresses)
+	}
+
+	names, err := b.List(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{testNodeName}, names)
+
+	if assert.NoError(t, b.Stop(ctx, testNodeName)) {
+		status, _ = b.Status(ctx, testNodeName)
+
+		assert.Equal(t, MultipassNodeStateStopped, status.State)
+	}
+
+	if assert.NoError(t, b.Start(ctx, testNodeName)) {
+		status, _ = b.Status(ctx, testNodeName)
+
+		assert.Equal(t, MultipassNodeStateRunning, status.State)
+	}
+
+	// The fake daemon doesn't implement mount, the command line is used
+	fallback.Launch(ctx, &VMLaunchSpec{Name: testNodeName})
+
+	assert.NoError(t, b.Mount(ctx, testNodeName, "/home", "/mnt/home"))
+	assert.Contains(t, fallback.calls, "mount")
+
+	assert.NoError(t, b.Delete(ctx, testNodeName))
+
+	_, err = b.Status(ctx, testNodeName)
+
+	assert.Equal(t, codes.NotFound, kindOf(err).code)
+	assert.Equal(t, codes.NotFound, kindOf(b.Stop(ctx, testNodeName)).code)
+}
+
+func Test_multipassdBackend_fallback(t *testing.T) {
+	fallback := newFakeBackend()
+	b := newMultipassdBackend(&MultipassdConfig{Address: "unix:/nonexistent/multipass_socket"}, os.TempDir())
+	ctx := context.Background()
+
+	b.fallback = fallback
+
+	assert.NoError(t, b.Launch(ctx, &VMLaunchSpec{Name: testNodeName}))
+
+	status, err := b.Status(ctx, testNodeName)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, MultipassNodeStateRunning, status.State)
+	}
+
+	assert.NoError(t, b.Delete(ctx, testNodeName))
+	assert.Equal(t, []string{"launch", "status", "delete"}, fallback.calls)
+	assert.True(t, b.degraded)
+}
+
+func Test_multipassdBackend_brokenLaunch(t *testing.T) {
+	f := newFakeMultipassd(t)
+	fallback := newFakeBackend()
+	b := newMultipassdBackend(&MultipassdConfig{Address: "unix:" + f.socket}, os.TempDir())
+
+	b.fallback = fallback
+	f.broken = true
+
+	err := b.Launch(context.Background(), &VMLaunchSpec{Name: testNodeName})
+
+	if assert.Error(t, err) {
+		assert.Equal(t, codes.Unavailable, kindOf(err).code)
+		assert.Empty(t, fallback.calls, "the VM is launched twice")
+	}
+
+	// The connection is kept for the next calls
+	names, err := b.List(context.Background())
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{testNodeName}, names)
+		assert.Empty(t, fallback.calls)
+	}
+}
@@ -2,16 +2,15 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"os"
 	"os/exec"
 	"strconv"
 	"strings"
 	"time"
 
-	"gopkg.in/yaml.v2"
+	apigrpc "github.com/Fred78290/kubernetes-multipass-autoscaler/grpc"
 	apiv1 "k8s.io/api/core/v1"
 
 	"github.com/golang/glog"
@@ -53,6 +52,8 @@ const (
 	stopArgument            string = "stop"
 	startArgument           string = "start"
 	infoArgument            string = "info"
+	mountArgument           string = "mount"
+	listArgument            string = "list"
 	// MultipassNodeStateNotCreated not created state
 	MultipassNodeStateNotCreated MultipassNodeState = 0
 
@@ -69,64 +70,51 @@ const (
 	MultipassNodeStateUndefined MultipassNodeState = 4
 )
 
-// MultipassNode Describe a multipass VM
-type MultipassNode struct {
-	ProviderID       string             `json:"providerID"`
-	NodeName         string             `json:"name"`
-	NodeIndex        int                `json:"index"`
-	Memory           int                `json:"memory"`
-	CPU              int                `json:"cpu"`
-	Disk             int                `json:"disk"`
-	Addresses        []string           `json:"addresses"`
-	State            MultipassNodeState `json:"state"`
-	AutoProvisionned bool               `json:"auto"`
-}
-
-// VMDiskInfo describe VM disk usage
-type VMDiskInfo struct {
-	Total string `json:"total"`
-	Used  string `json:"used"`
+// MultipassNodeErrorInfo describe why the VM could not be created
+type MultipassNodeErrorInfo struct {
+	Class   apigrpc.InstanceErrorClass `json:"class"`
+	Code    string                     `json:"code"`
+	Message string                     `json:"message"`
 }
 
-// VMMemoryInfo describe VM mem infos
-type VMMemoryInfo struct {
-	Total int `json:"total"`
-	Used  int `json:"used"`
+// MultipassNode Describe a multipass VM
+type MultipassNode struct {
+	ProviderID       string                  `json:"providerID"`
+	NodeName         string                  `json:"name"`
+	NodeIndex        int                     `json:"index"`
+	Memory           int                     `json:"memory"`
+	CPU              int                     `json:"cpu"`
+	Disk             int                     `json:"disk"`
+	Addresses        []string                `json:"addresses"`
+	State            MultipassNodeState      `json:"state"`
+	Host             string                  `json:"host,omitempty"`
+	HibernatedAt     *time.Time              `json:"hibernated-at,omitempty"`
+	AutoProvisionned bool                    `json:"auto"`
+	ErrorInfo        *MultipassNodeErrorInfo `json:"error,omitempty"`
 }
 
-// VMMountInfos describe VM mounts point between host and guest
-type VMMountInfos struct {
-	GIDMappings []string `json:"gid_mappings"`
-	UIDMappings []string `json:"uid_mappings"`
-	SourcePath  string   `json:"source_path"`
+// failed tells if the VM was never created because of an error
+func (vm *MultipassNode) failed() bool {
+	return vm.ErrorInfo != nil
 }
 
-// VMInfos describe VM global infos
-type VMInfos struct {
-	Disks        map[string]*VMDiskInfo  `json:"disks"`
-	ImageHash    string                  `json:"image_hash"`
-	ImageRelease string                  `json:"image_release"`
-	Ipv4         []string                `json:"ipv4"`
-	Load         []float64               `json:"load"`
-	Memory       *VMMemoryInfo           `json:"memory"`
-	Mounts       map[string]VMMountInfos `json:"mounts"`
-	Release      string                  `json:"release"`
-	State        string                  `json:"state"`
-}
+// backend returns the backend of the host running the VM
+func (vm *MultipassNode) backend(extras *nodeCreationExtra) (VMBackend, error) {
+	if backend := extras.hosts.backend(vm.Host, extras.backend); backend != nil {
+		return backend, nil
+	}
 
-// MultipassVMInfos describe all about VMs
-type MultipassVMInfos struct {
-	Errors []interface{}       `json:"errors"`
-	Info   map[string]*VMInfos `json:"info"`
+	return nil, newError(kindFailedPrecondition, errHostNotDeclared, vm.Host, vm.NodeName)
 }
 
-func pipe(args ...string) (string, error) {
+// runCommand execute the command once and returns stdout, stderr
+func runCommand(ctx context.Context, args ...string) (string, string, error) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
 
 	glog.V(5).Infof("Shell:%v", args)
 
-	cmd := exec.Command(args[0], args[1:]...)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
 
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -134,65 +122,71 @@ func pipe(args ...string) (string, error) {
 	if err := cmd.Run(); err != nil {
 		s := strings.TrimSpace(stderr.String())
 
-		return s, fmt.Errorf("%s, %s", err.Error(), s)
+		if ctx.Err() != nil {
+			return "", s, wrapError(kindTimeout, ctx.Err(), errCommandCanceled, strings.Join(args, " "), ctx.Err())
+		}
+
+		return "", s, wrapError(nil, err, errCommandFailed, err, s)
 	}
 
-	return strings.TrimSpace(stdout.String()), nil
+	return strings.TrimSpace(stdout.String()), strings.TrimSpace(stderr.String()), nil
 }
 
-func shell(args ...string) error {
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
+func pipe(ctx context.Context, args ...string) (string, error) {
+	var out string
 
-	glog.V(5).Infof("Shell:%v", args)
+	err := commandRetryPolicy.retry(ctx, args, func() (string, error) {
+		var stderr string
+		var err error
 
-	cmd := exec.Command(args[0], args[1:]...)
+		if out, stderr, err = runCommand(ctx, args...); err != nil {
+			out = stderr
+		}
 
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+		return stderr, err
+	})
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("%s, %s", err.Error(), strings.TrimSpace(stderr.String()))
-	}
+	return out, err
+}
 
-	return nil
+func shell(ctx context.Context, args ...string) error {
+	return commandRetryPolicy.retry(ctx, args, func() (string, error) {
+		_, stderr, err := runCommand(ctx, args...)
+
+		return stderr, err
+	})
 }
 
-func (vm *MultipassNode) prepareKubelet(extras *nodeCreationExtra) error {
-	var out string
-	var err error
-	var srcName = fmt.Sprintf("%s/set-kubelet-default-%s.sh", extras.cacheDir, vm.NodeName)
-	var dstName = fmt.Sprintf("/tmp/set-kubelet-default-%s.sh", vm.NodeName)
-
-	kubeletDefault := []string{
-		"#!/bin/bash",
-		". /etc/default/kubelet",
-		fmt.Sprintf("echo \"KUBELET_EXTRA_ARGS=\\\"$KUBELET_EXTRA_ARGS --provider-id=%s\\\"\" > /etc/default/kubelet", vm.ProviderID),
-		"systemctl restart kubelet",
+// prepareKubelet set the kubelet flags of the node group in /etc/default/kubelet, the previous values are replaced
+func (vm *MultipassNode) prepareKubelet(ctx context.Context, extras *nodeCreationExtra) error {
+	flags, err := vm.kubeletFlags(ctx, extras)
+
+	if err == nil {
+		err = vm.setKubeletDefault(ctx, extras, flags)
 	}
 
-	if err = ioutil.WriteFile(srcName, []byte(strings.Join(kubeletDefault, "\n")), 0755); err != nil {
-		return fmt.Errorf(errKubeletNotConfigured, vm.NodeName, out, err)
+	if err != nil {
+		return wrapError(kindVM, err, errKubeletNotConfigured, vm.NodeName, "", err)
 	}
 
-	defer os.Remove(srcName)
+	backend, err := vm.backend(extras)
 
-	if out, err = pipe(multipassCommandLine, copyFileArgument, srcName, vm.NodeName+":"+dstName); err != nil {
-		return fmt.Errorf(errKubeletNotConfigured, vm.NodeName, out, err)
+	if err != nil {
+		return err
 	}
 
-	if out, err = pipe(multipassCommandLine, execArgument, vm.NodeName, dashDashArgument, sudoArgument, "bash", dstName); err != nil {
-		return fmt.Errorf(errKubeletNotConfigured, vm.NodeName, out, err)
+	if out, err := backend.Exec(ctx, vm.NodeName, sudoArgument, "systemctl", "restart", "kubelet"); err != nil {
+		return wrapError(kindVM, err, errKubeletNotConfigured, vm.NodeName, out, err)
 	}
 
 	return nil
 }
 
-func (vm *MultipassNode) waitReady(kubeconfig string) error {
+// waitReady poll the node until it's ready or the context is done
+func (vm *MultipassNode) waitReady(ctx context.Context, kubeconfig string, timeouts *MultipassServerTimeouts) error {
 	glog.V(5).Infof("multipassNode::waitReady, node:%s", vm.NodeName)
 
-	// Max 60s
-	for index := 0; index < 12; index++ {
+	for {
 		var out string
 		var err error
 		var arg = []string{
@@ -206,14 +200,20 @@ func (vm *MultipassNode) waitReady(kubeconfig string) error {
 			kubeconfig,
 		}
 
-		if out, err = pipe(arg...); err != nil {
+		infoCtx, cancel := context.WithTimeout(ctx, timeouts.info())
+
+		out, err = pipe(infoCtx, arg...)
+
+		cancel()
+
+		if err != nil {
 			return err
 		}
 
 		var nodeInfo apiv1.Node
 
 		if err = json.Unmarshal([]byte(out), &nodeInfo); err != nil {
-			return fmt.Errorf(errUnmarshallingError, vm.NodeName, err)
+			return wrapError(kindInternal, err, errUnmarshallingError, vm.NodeName, err)
 		}
 
 		for _, status := range nodeInfo.Status.Conditions {
@@ -225,18 +225,16 @@ func (vm *MultipassNode) waitReady(kubeconfig string) error {
 
 		glog.Infof("The kubernetes node:%s is not ready", vm.NodeName)
 
-		time.Sleep(5 * time.Second)
+		select {
+		case <-ctx.Done():
+			return newError(kindKubernetes, errNodeIsNotReady, vm.NodeName)
+		case <-time.After(5 * time.Second):
+		}
 	}
-
-	return fmt.Errorf(errNodeIsNotReady, vm.NodeName)
 }
 
-func (vm *MultipassNode) kubeAdmJoin(extras *nodeCreationExtra) error {
+func (vm *MultipassNode) kubeAdmJoin(ctx context.Context, extras *nodeCreationExtra) error {
 	args := []string{
-		multipassCommandLine,
-		execArgument,
-		vm.NodeName,
-		dashDashArgument,
 		sudoArgument,
 		kubeadmArgument,
 		joinArgument,
@@ -247,20 +245,30 @@ func (vm *MultipassNode) kubeAdmJoin(extras *nodeCreationExtra) error {
 		extras.kubeCACert,
 	}
 
+	if extras.controlPlane {
+		args = append(args, controlPlaneArgument, certificateKeyArgument, extras.certificateKey)
+	}
+
 	// Append extras arguments
 	if len(extras.kubeExtraArgs) > 0 {
 		args = append(args, extras.kubeExtraArgs...)
 	}
 
-	if err := shell(args...); err != nil {
-		return fmt.Errorf(errKubeAdmJoinFailed, vm.NodeName, err)
+	backend, err := vm.backend(extras)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = backend.Exec(ctx, vm.NodeName, args...); err != nil {
+		return wrapError(kindKubernetes, err, errKubeAdmJoinFailed, vm.NodeName, err)
 	}
 
 	return nil
 }
 
-func (vm *MultipassNode) setNodeLabels(extras *nodeCreationExtra) error {
-	if len(extras.nodeLabels)+len(extras.systemLabels) > 0 {
+func (vm *MultipassNode) setNodeLabels(ctx context.Context, extras *nodeCreationExtra) error {
+	if len(extras.nodeLabels)+len(extras.systemLabels) > 0 || len(vm.Host) > 0 {
 
 		args := []string{
 			kubectlCommandLine,
@@ -278,14 +286,23 @@ func (vm *MultipassNode) setNodeLabels(extras *nodeCreationExtra) error {
 			args = append(args, fmt.Sprintf("%s=%s", k, v))
 		}
 
+		if len(vm.Host) > 0 {
+			args = append(args, fmt.Sprintf("%s=%s", nodeLabelZone, vm.Host))
+		}
+
 		args = append(args, kubeConfigArgument)
 		args = append(args, extras.kubeConfig)
 
-		if err := shell(args...); err != nil {
-			return fmt.Errorf(errKubeCtlIgnoredError, vm.NodeName, err)
+		if err := shell(ctx, args...); err != nil {
+			return wrapError(kindKubernetes, err, errKubeCtlIgnoredError, vm.NodeName, err)
 		}
 	}
 
+	return vm.annotateNode(ctx, extras)
+}
+
+// annotateNode stamp the node group and the node index on the node
+func (vm *MultipassNode) annotateNode(ctx context.Context, extras *nodeCreationExtra) error {
 	args := []string{
 		kubectlCommandLine,
 		annotateArgument,
@@ -299,126 +316,122 @@ func (vm *MultipassNode) setNodeLabels(extras *nodeCreationExtra) error {
 		extras.kubeConfig,
 	}
 
-	if err := shell(args...); err != nil {
-		return fmt.Errorf(errKubeCtlIgnoredError, vm.NodeName, err)
+	if err := shell(ctx, args...); err != nil {
+		return wrapError(kindKubernetes, err, errKubeCtlIgnoredError, vm.NodeName, err)
 	}
 
 	return nil
 }
 
-func (vm *MultipassNode) mountPoints(extras *nodeCreationExtra) {
-	if extras.mountPoints != nil && len(extras.mountPoints) > 0 {
-		for hostPath, guestPath := range extras.mountPoints {
-			if err := shell(multipassCommandLine, "mount", hostPath, fmt.Sprintf("%s:%s", vm.NodeName, guestPath)); err != nil {
-				glog.Warningf(errUnableToMountPath, hostPath, guestPath, vm.NodeName, err)
-			}
-		}
+// drain evict the pods and cordon the node, errors are ignored
+func (vm *MultipassNode) drain(ctx context.Context, extras *nodeCreationExtra) {
+	args := []string{
+		kubectlCommandLine,
+		drainArgument,
+		vm.NodeName,
+		deleteLocalArgument,
+		forceArgument,
+		ignoreDaemonsetArgument,
+		kubeConfigArgument,
+		extras.kubeConfig,
 	}
-}
 
-func (vm *MultipassNode) writeCloudFile(extras *nodeCreationExtra) (*os.File, error) {
-	var cloudInitFile *os.File
-	var err error
-	var b []byte
-
-	if extras.cloudInit != nil && len(extras.cloudInit) > 0 {
-		fName := fmt.Sprintf("%s/cloud-init-%s.yaml", extras.cacheDir, vm.NodeName)
-		cloudInitFile, err = os.Create(fName)
+	if err := shell(ctx, args...); err != nil {
+		glog.Errorf(errKubeCtlIgnoredError, vm.NodeName, err)
+	}
+}
 
-		glog.Infof("Create cloud file: %s", fName)
+func (vm *MultipassNode) mountPoints(ctx context.Context, extras *nodeCreationExtra) {
+	if extras.mountPoints != nil && len(extras.mountPoints) > 0 {
+		backend, err := vm.backend(extras)
 
-		if err == nil {
-			if b, err = yaml.Marshal(extras.cloudInit); err == nil {
-				if _, err = cloudInitFile.Write(b); err != nil {
-					err = fmt.Errorf(errCloudInitWriteError, err)
-				}
-			} else {
-				err = fmt.Errorf(errCloudInitMarshallError, err)
-			}
-		} else {
-			err = fmt.Errorf(errTempFile, err)
+		if err != nil {
+			glog.Warning(err.Error())
+			return
 		}
 
-		if err != nil {
-			os.Remove(fName)
+		for hostPath, guestPath := range extras.mountPoints {
+			if err := backend.Mount(ctx, vm.NodeName, hostPath, guestPath); err != nil {
+				glog.Warningf(errUnableToMountPath, hostPath, guestPath, vm.NodeName, err)
+			}
 		}
 	}
-
-	return cloudInitFile, err
 }
 
-func (vm *MultipassNode) launchVM(extras *nodeCreationExtra) error {
+func (vm *MultipassNode) launchVM(ctx context.Context, extras *nodeCreationExtra) error {
 	glog.V(5).Infof("multipassNode::launchVM, node:%s", vm.NodeName)
 
-	var cloudInitFile *os.File
 	var err error
 	var status MultipassNodeState
+	var backend VMBackend
+	var cloudInit map[string]interface{}
+	var scripts []*CloudInitScript
 
 	glog.Infof("Launch VM:%s for nodegroup: %s", vm.NodeName, extras.nodegroupID)
 
 	if vm.AutoProvisionned {
 		if vm.State != MultipassNodeStateNotCreated {
-			err = fmt.Errorf(errVMAlreadyCreated, vm.NodeName)
-		} else if cloudInitFile, err = vm.writeCloudFile(extras); err == nil {
-			var args = []string{
-				multipassCommandLine,
-				launchArgument,
-				nameArgument,
-				vm.NodeName,
-			}
-
-			/*
-				Append VM attributes Memory,cpus, hard drive size....
-			*/
-			if vm.Memory > 0 {
-				args = append(args, fmt.Sprintf("--mem=%dM", vm.Memory))
-			}
-
-			if vm.CPU > 0 {
-				args = append(args, fmt.Sprintf("--cpus=%d", vm.CPU))
-			}
-
-			if vm.Disk > 0 {
-				args = append(args, fmt.Sprintf("--disk=%dM", vm.Disk))
+			err = newError(kindAlreadyExists, errVMAlreadyCreated, vm.NodeName)
+		} else if cloudInit, err = vm.userData(extras); err != nil {
+			err = wrapError(kindInternal, err, errCloudInitFailCreation, vm.NodeName, err)
+		} else if scripts, err = vm.userScripts(extras); err != nil {
+			err = wrapError(kindInternal, err, errCloudInitFailCreation, vm.NodeName, err)
+		} else if backend, err = vm.backend(extras); err == nil {
+			spec := &VMLaunchSpec{
+				Name:      vm.NodeName,
+				Memory:    vm.Memory,
+				CPU:       vm.CPU,
+				Disk:      vm.Disk,
+				Image:     extras.image,
+				Arch:      extras.arch,
+				CloudInit: cloudInit,
+				Scripts:   scripts,
 			}
 
-			// If cloud-init file is present
-			if cloudInitFile != nil {
-				args = append(args, fmt.Sprintf("--cloud-init=%s", cloudInitFile.Name()))
-			}
+			launchCtx, cancel := context.WithTimeout(ctx, extras.timeouts.launch())
 
-			// If an image/url image
-			if len(extras.image) > 0 {
-				args = append(args, extras.image)
-			}
+			defer cancel()
 
 			// Launch the VM and wait until finish launched
-			if err = shell(args...); err != nil {
-				err = fmt.Errorf(errUnableToLaunchVM, vm.NodeName, err)
+			if err = backend.Launch(launchCtx, spec); err != nil {
+				err = wrapError(kindVM, err, errUnableToLaunchVM, vm.NodeName, err)
 			} else {
 				// Add mount point
-				vm.mountPoints(extras)
+				vm.mountPoints(launchCtx, extras)
 
-				if status, err = vm.statusVM(); err != nil {
+				if status, err = vm.statusVM(ctx, extras); err != nil {
 					glog.Error(err.Error())
 				} else if status == MultipassNodeStateRunning {
-					// If the VM is running call kubeadm join
+					// If the VM is running call kubeadm join or wait the cloud-init join
 					if extras.vmprovision {
-						if err = vm.prepareKubelet(extras); err == nil {
-							if err = vm.kubeAdmJoin(extras); err == nil {
-								if err = vm.waitReady(extras.kubeConfig); err == nil {
-									err = vm.setNodeLabels(extras)
+						joinCtx, cancel := context.WithTimeout(ctx, extras.timeouts.join())
+
+						defer cancel()
+
+						bootstrapper := extras.clusterBootstrapper()
+
+						if extras.bootstrap.cloudInit() {
+							err = vm.waitBootstrap(joinCtx, extras)
+						} else if err = bootstrapper.ConfigureKubelet(joinCtx, vm, extras); err == nil {
+							if err = bootstrapper.Join(joinCtx, vm, extras); err == nil {
+								if err = bootstrapper.WaitReady(joinCtx, vm, extras); err == nil {
+									err = vm.setNodeLabels(joinCtx, extras)
 								}
 							}
 						}
+
+						// The gates have their own timeouts
+						if err == nil {
+							err = vm.waitReadinessGates(ctx, extras)
+						}
 					}
 				} else {
-					err = fmt.Errorf(errKubeAdmJoinNotRunning, vm.NodeName)
+					err = newError(kindVM, errKubeAdmJoinNotRunning, vm.NodeName)
 				}
 			}
 		}
 	} else {
-		err = fmt.Errorf(errVMNotProvisionnedByMe, vm.NodeName)
+		err = newError(kindFailedPrecondition, errVMNotProvisionnedByMe, vm.NodeName)
 	}
 
 	if err == nil {
@@ -430,7 +443,7 @@ func (vm *MultipassNode) launchVM(extras *nodeCreationExtra) error {
 	return err
 }
 
-func (vm *MultipassNode) startVM(kubeconfig string) error {
+func (vm *MultipassNode) startVM(ctx context.Context, extras *nodeCreationExtra) error {
 	glog.V(5).Infof("multipassNode::startVM, node:%s", vm.NodeName)
 
 	var err error
@@ -439,19 +452,30 @@ func (vm *MultipassNode) startVM(kubeconfig string) error {
 	glog.Infof("Start VM:%s", vm.NodeName)
 
 	if !vm.AutoProvisionned {
-		err = fmt.Errorf(errVMNotProvisionnedByMe, vm.NodeName)
-	} else if state, err = vm.statusVM(); err == nil {
+		err = newError(kindFailedPrecondition, errVMNotProvisionnedByMe, vm.NodeName)
+	} else if state, err = vm.statusVM(ctx, extras); err == nil {
 		if state == MultipassNodeStateStopped {
-			if err = shell(multipassCommandLine, startArgument, vm.NodeName); err != nil {
+			// statusVM succeeded, the host is declared
+			backend, _ := vm.backend(extras)
+
+			startCtx, cancel := context.WithTimeout(ctx, extras.timeouts.start())
+
+			defer cancel()
+
+			if err = backend.Start(startCtx, vm.NodeName); err == nil {
 				args := []string{
 					kubectlCommandLine,
 					uncordonArgument,
 					vm.NodeName,
 					kubeConfigArgument,
-					kubeconfig,
+					extras.kubeConfig,
 				}
 
-				if err = shell(args...); err != nil {
+				drainCtx, cancel := context.WithTimeout(ctx, extras.timeouts.drain())
+
+				defer cancel()
+
+				if err = shell(drainCtx, args...); err != nil {
 					glog.Errorf(errKubeCtlIgnoredError, vm.NodeName, err)
 
 					err = nil
@@ -459,10 +483,10 @@ func (vm *MultipassNode) startVM(kubeconfig string) error {
 
 				vm.State = MultipassNodeStateRunning
 			} else {
-				err = fmt.Errorf(errStartVMFailed, vm.NodeName, err)
+				err = wrapError(kindVM, err, errStartVMFailed, vm.NodeName, err)
 			}
 		} else if state != MultipassNodeStateRunning {
-			err = fmt.Errorf(errStartVMFailed, vm.NodeName, fmt.Sprintf("Unexpected state: %d", state))
+			err = newError(kindVM, errStartVMFailed, vm.NodeName, fmt.Sprintf("Unexpected state: %d", state))
 		}
 	}
 
@@ -472,10 +496,10 @@ func (vm *MultipassNode) startVM(kubeconfig string) error {
 		glog.Errorf("Unable to start VM:%s. Reason: %v", vm.NodeName, err)
 	}
 
-	return nil
+	return err
 }
 
-func (vm *MultipassNode) stopVM(kubeconfig string) error {
+func (vm *MultipassNode) stopVM(ctx context.Context, extras *nodeCreationExtra) error {
 	glog.V(5).Infof("multipassNode::stopVM, node:%s", vm.NodeName)
 
 	var err error
@@ -484,29 +508,40 @@ func (vm *MultipassNode) stopVM(kubeconfig string) error {
 	glog.Infof("Stop VM:%s", vm.NodeName)
 
 	if !vm.AutoProvisionned {
-		err = fmt.Errorf(errVMNotProvisionnedByMe, vm.NodeName)
-	} else if state, err = vm.statusVM(); err == nil {
+		err = newError(kindFailedPrecondition, errVMNotProvisionnedByMe, vm.NodeName)
+	} else if state, err = vm.statusVM(ctx, extras); err == nil {
 
 		if state == MultipassNodeStateRunning {
+			// statusVM succeeded, the host is declared
+			backend, _ := vm.backend(extras)
+
 			args := []string{
 				kubectlCommandLine,
 				cordonArgument,
 				vm.NodeName,
 				kubeConfigArgument,
-				kubeconfig,
+				extras.kubeConfig,
 			}
 
-			if err = shell(args...); err != nil {
+			drainCtx, cancel := context.WithTimeout(ctx, extras.timeouts.drain())
+
+			defer cancel()
+
+			if err = shell(drainCtx, args...); err != nil {
 				glog.Errorf(errKubeCtlIgnoredError, vm.NodeName, err)
 			}
 
-			if err = shell(multipassCommandLine, stopArgument, vm.NodeName); err == nil {
+			stopCtx, cancel := context.WithTimeout(ctx, extras.timeouts.stop())
+
+			defer cancel()
+
+			if err = backend.Stop(stopCtx, vm.NodeName); err == nil {
 				vm.State = MultipassNodeStateStopped
 			} else {
-				err = fmt.Errorf(errStopVMFailed, vm.NodeName, err)
+				err = wrapError(kindVM, err, errStopVMFailed, vm.NodeName, err)
 			}
 		} else if state != MultipassNodeStateStopped {
-			err = fmt.Errorf(errStopVMFailed, vm.NodeName, fmt.Sprintf("Unexpected state: %d", state))
+			err = newError(kindVM, errStopVMFailed, vm.NodeName, fmt.Sprintf("Unexpected state: %d", state))
 		}
 	}
 
@@ -519,65 +554,72 @@ func (vm *MultipassNode) stopVM(kubeconfig string) error {
 	return err
 }
 
-func (vm *MultipassNode) deleteVM(kubeconfig string) error {
+func (vm *MultipassNode) deleteVM(ctx context.Context, extras *nodeCreationExtra) error {
 	glog.V(5).Infof("multipassNode::deleteVM, node:%s", vm.NodeName)
 
 	var err error
 	var state MultipassNodeState
 
 	if vm.AutoProvisionned {
-		state, err = vm.statusVM()
+		state, err = vm.statusVM(ctx, extras)
+
+		// A control plane member leave etcd first, the VM is kept if it fails
+		if err == nil && extras.controlPlane && state == MultipassNodeStateRunning {
+			err = vm.removeEtcdMember(ctx, extras)
+		}
 
 		if err == nil {
+			// statusVM succeeded, the host is declared
+			backend, _ := vm.backend(extras)
 
-			args := []string{
-				kubectlCommandLine,
-				drainArgument,
-				vm.NodeName,
-				deleteLocalArgument,
-				forceArgument,
-				ignoreDaemonsetArgument,
-				kubeConfigArgument,
-				kubeconfig,
-			}
+			drainCtx, cancel := context.WithTimeout(ctx, extras.timeouts.drain())
 
-			if err = shell(args...); err != nil {
-				glog.Errorf(errKubeCtlIgnoredError, vm.NodeName, err)
-			}
+			defer cancel()
+
+			vm.drain(drainCtx, extras)
 
-			args = []string{
+			args := []string{
 				kubectlCommandLine,
 				deleteArgument,
 				nodeArgument,
 				vm.NodeName,
 				kubeConfigArgument,
-				kubeconfig,
+				extras.kubeConfig,
 			}
 
-			if err = shell(args...); err != nil {
+			infoCtx, cancel := context.WithTimeout(ctx, extras.timeouts.info())
+
+			defer cancel()
+
+			if err = shell(infoCtx, args...); err != nil {
 				glog.Errorf(errKubeCtlIgnoredError, vm.NodeName, err)
 			}
 
+			// The delete budget starts once the node is drained
+			deleteCtx, cancel := context.WithTimeout(ctx, extras.timeouts.delete())
+
+			defer cancel()
+
 			if state == MultipassNodeStateRunning {
-				if err = shell(multipassCommandLine, stopArgument, vm.NodeName); err == nil {
+				if err = backend.Stop(deleteCtx, vm.NodeName); err == nil {
 					vm.State = MultipassNodeStateStopped
 
-					if err = shell(multipassCommandLine, deleteArgument, purgeArgument, vm.NodeName); err == nil {
+					if err = backend.Delete(deleteCtx, vm.NodeName); err == nil {
 						vm.State = MultipassNodeStateDeleted
 					} else {
-						err = fmt.Errorf(errDeleteVMFailed, vm.NodeName, err)
+						err = wrapError(kindVM, err, errDeleteVMFailed, vm.NodeName, err)
 					}
 				} else {
-					err = fmt.Errorf(errStopVMFailed, vm.NodeName, err)
+					err = wrapError(kindVM, err, errStopVMFailed, vm.NodeName, err)
 				}
-			} else if err = shell(multipassCommandLine, deleteArgument, purgeArgument, vm.NodeName); err == nil {
+			} else if err = backend.Delete(deleteCtx, vm.NodeName); err == nil {
 				vm.State = MultipassNodeStateDeleted
 			} else {
-				err = fmt.Errorf(errDeleteVMFailed, vm.NodeName, err)
+				err = wrapError(kindVM, err, errDeleteVMFailed, vm.NodeName, err)
 			}
 		}
 	} else {
-		err = fmt.Errorf(errVMNotProvisionnedByMe, vm.NodeName)
+		err = newError(kindFailedPrecondition, errVMNotProvisionnedByMe, vm.NodeName)
 	}
 
 	if err == nil {
@@ -589,43 +631,30 @@ func (vm *MultipassNode) deleteVM(kubeconfig string) error {
 	return err
 }
 
-func (vm *MultipassNode) statusVM() (MultipassNodeState, error) {
+func (vm *MultipassNode) statusVM(ctx context.Context, extras *nodeCreationExtra) (MultipassNodeState, error) {
 	glog.V(5).Infof("multipassNode::statusVM, node:%s", vm.NodeName)
 
-	// Get VM infos
-	var out string
-	var err error
-	var vmInfos MultipassVMInfos
+	infoCtx, cancel := context.WithTimeout(ctx, extras.timeouts.info())
+
+	defer cancel()
 
-	if out, err = pipe(multipassCommandLine, infoArgument, vm.NodeName, "--format=json"); err != nil {
+	backend, err := vm.backend(extras)
+
+	if err != nil {
 		glog.Errorf(errGetVMInfoFailed, vm.NodeName, err)
 		return MultipassNodeStateUndefined, err
 	}
 
-	if err = json.Unmarshal([]byte(out), &vmInfos); err != nil {
+	// Get VM infos
+	status, err := backend.Status(infoCtx, vm.NodeName)
+
+	if err != nil {
 		glog.Errorf(errGetVMInfoFailed, vm.NodeName, err)
 		return MultipassNodeStateUndefined, err
 	}
 
-	if vmInfo := vmInfos.Info[vm.NodeName]; vmInfo != nil {
-		vm.Addresses = vmInfo.Ipv4
-
-		switch strings.ToUpper(vmInfo.State) {
-		case "RUNNING":
-			vm.State = MultipassNodeStateRunning
-		case "STOPPED":
-			vm.State = MultipassNodeStateStopped
-		case "DELETED":
-			vm.State = MultipassNodeStateDeleted
-		default:
-			vm.State = MultipassNodeStateUndefined
-			glog.Infof(errVMStateUndefined, vm.NodeName, vmInfo.State)
-		}
-
-		vm.Addresses = vmInfo.Ipv4
-
-		return vm.State, nil
-	}
+	vm.State = status.State
+	vm.Addresses = status.Addresses
 
-	return MultipassNodeStateUndefined, fmt.Errorf(errMultiPassInfoNotFound, vm.NodeName)
+	return vm.State, nil
 }
@@ -1,13 +1,16 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"sync"
 
 	"github.com/golang/glog"
 	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // NodeGroupState describe the nodegroup status
@@ -33,6 +36,7 @@ type MultipassNodeGroup struct {
 	sync.Mutex
 	NodeGroupIdentifier  string                    `json:"identifier"`
 	ServiceIdentifier    string                    `json:"service"`
+	MachineType          string                    `json:"machine-type"`
 	Machine              *MachineCharacteristic    `json:"machine"`
 	Status               NodeGroupState            `json:"status"`
 	MinNodeSize          int                       `json:"minSize"`
@@ -42,27 +46,52 @@ type MultipassNodeGroup struct {
 	SystemLabels         map[string]string         `json:"systemLabels"`
 	AutoProvision        bool                      `json:"auto-provision"`
 	LastCreatedNodeIndex int                       `json:"node-index"`
+	WarmPoolSize         int                       `json:"warm-pool-size"`
+	WarmNodes            map[string]*MultipassNode `json:"warm-nodes"`
+	ScaleDown            *ScaleDownPolicy          `json:"scale-down,omitempty"`
+	HibernatedNodes      map[string]*MultipassNode `json:"hibernated-nodes"`
+	ControlPlane         bool                      `json:"control-plane,omitempty"`
+	Kubelet              *KubeletConfig            `json:"kubelet,omitempty"`
 	PendingNodes         map[string]*MultipassNode `json:"-"`
+	PendingWarmNodes     map[string]*MultipassNode `json:"pending-warm-nodes,omitempty"`
 	PendingNodesWG       sync.WaitGroup            `json:"-"`
+	standbyLock          sync.RWMutex              // guard the warm and hibernated maps, filled in background while the group lock is held by a scale up
 }
 
 type nodeCreationExtra struct {
-	nodegroupID   string
-	kubeHost      string
-	kubeToken     string
-	kubeCACert    string
-	kubeExtraArgs []string
-	kubeConfig    string
-	image         string
-	cloudInit     map[string]interface{}
-	mountPoints   map[string]string
-	nodeLabels    map[string]string
-	systemLabels  map[string]string
-	vmprovision   bool
-	cacheDir      string
+	nodegroupID        string
+	kubeHost           string
+	kubeToken          string
+	kubeCACert         string
+	kubeExtraArgs      []string
+	kubeConfig         string
+	image              string
+	arch               string
+	cloudInit          map[string]interface{}
+	cloudInitTemplate  string
+	cloudInitVariables map[string]string
+	cloudInitScripts   []*CloudInitScript
+	machineCloudInit   map[string]interface{}
+	nodeGroupCloudInit map[string]interface{}
+	machineType        string
+	mountPoints        map[string]string
+	nodeLabels         map[string]string
+	systemLabels       map[string]string
+	vmprovision        bool
+	cacheDir           string
+	timeouts           *MultipassServerTimeouts
+	backend            VMBackend
+	hosts              *hypervisorHosts
+	placement          *hostPlacement
+	bootstrap          *BootstrapConfig
+	bootstrapper       Bootstrapper
+	controlPlane       bool
+	certificateKey     string
+	kubelet            *KubeletConfig
+	readinessGates     []*ReadinessGate
 }
 
-func (g *MultipassNodeGroup) cleanup(kubeconfig string) error {
+func (g *MultipassNodeGroup) cleanup(ctx context.Context, extras *nodeCreationExtra) error {
 	glog.V(5).Infof("MultipassNodeGroup::cleanup, nodeGroupID:%s", g.NodeGroupIdentifier)
 
 	var lastError error
@@ -74,12 +103,28 @@ func (g *MultipassNodeGroup) cleanup(kubeconfig string) error {
 	glog.V(5).Infof("MultipassNodeGroup::cleanup, nodeGroupID:%s, iterate node to delete", g.NodeGroupIdentifier)
 
 	for _, node := range g.Nodes {
-		if lastError = node.deleteVM(kubeconfig); lastError != nil {
+		if node.failed() {
+			continue
+		}
+
+		if lastError = node.deleteVM(ctx, extras); lastError != nil {
 			glog.Errorf(errNodeGroupCleanupFailOnVM, g.NodeGroupIdentifier, node.NodeName, lastError)
 		}
 	}
 
+	for _, node := range g.standbyNodes() {
+		if lastError = node.deleteVM(ctx, extras); lastError != nil {
+			glog.Errorf(errNodeGroupCleanupFailOnVM, g.NodeGroupIdentifier, node.NodeName, lastError)
+		}
+	}
+
+	g.standbyLock.Lock()
+	defer g.standbyLock.Unlock()
+
 	g.Nodes = make(map[string]*MultipassNode)
+	g.WarmNodes = make(map[string]*MultipassNode)
+	g.HibernatedNodes = make(map[string]*MultipassNode)
+	g.PendingWarmNodes = make(map[string]*MultipassNode)
 	g.PendingNodes = make(map[string]*MultipassNode)
 	g.Status = NodegroupDeleted
 
@@ -92,7 +137,26 @@ func (g *MultipassNodeGroup) targetSize() int {
 	return len(g.PendingNodes) + len(g.Nodes)
 }
 
-func (g *MultipassNodeGroup) setNodeGroupSize(newSize int, extras *nodeCreationExtra) error {
+// allocatedNodes returns a snapshot of the nodes and the pending nodes, the caller must not hold the node group lock
+func (g *MultipassNodeGroup) allocatedNodes() ([]*MultipassNode, []*MultipassNode) {
+	g.Lock()
+	defer g.Unlock()
+
+	nodes := make([]*MultipassNode, 0, len(g.Nodes))
+	pendingNodes := make([]*MultipassNode, 0, len(g.PendingNodes))
+
+	for _, node := range g.Nodes {
+		nodes = append(nodes, node)
+	}
+
+	for _, node := range g.PendingNodes {
+		pendingNodes = append(pendingNodes, node)
+	}
+
+	return nodes, pendingNodes
+}
+
+func (g *MultipassNodeGroup) setNodeGroupSize(ctx context.Context, newSize int, extras *nodeCreationExtra) error {
 	glog.V(5).Infof("MultipassNodeGroup::setNodeGroupSize, nodeGroupID:%s", g.NodeGroupIdentifier)
 
 	var err error
@@ -102,9 +166,9 @@ func (g *MultipassNodeGroup) setNodeGroupSize(newSize int, extras *nodeCreationE
 	delta := newSize - g.targetSize()
 
 	if delta < 0 {
-		err = g.deleteNodes(delta, extras)
+		err = g.deleteNodes(ctx, delta, extras)
 	} else if delta > 0 {
-		err = g.addNodes(delta, extras)
+		err = g.addNodes(ctx, delta, extras)
 	}
 
 	g.Unlock()
@@ -112,33 +176,33 @@ func (g *MultipassNodeGroup) setNodeGroupSize(newSize int, extras *nodeCreationE
 	return err
 }
 
-func (g *MultipassNodeGroup) refresh() {
+func (g *MultipassNodeGroup) refresh(ctx context.Context, extras *nodeCreationExtra) {
 	glog.V(5).Infof("MultipassNodeGroup::refresh, nodeGroupID:%s", g.NodeGroupIdentifier)
 
 	for _, node := range g.Nodes {
-		node.statusVM()
+		if !node.failed() {
+			node.statusVM(ctx, extras)
+		}
 	}
+
+	g.pruneHibernated(ctx, extras)
 }
 
 // delta must be negative!!!!
-func (g *MultipassNodeGroup) deleteNodes(delta int, extras *nodeCreationExtra) error {
+func (g *MultipassNodeGroup) deleteNodes(ctx context.Context, delta int, extras *nodeCreationExtra) error {
 	glog.V(5).Infof("MultipassNodeGroup::deleteNodes, nodeGroupID:%s", g.NodeGroupIdentifier)
 
-	startIndex := len(g.Nodes) - 1
-	endIndex := startIndex + delta
 	tempNodes := make([]*MultipassNode, 0, -delta)
 
-	for nodeIndex := startIndex; nodeIndex >= endIndex; nodeIndex-- {
-		nodeName := g.nodeName(nodeIndex)
-
-		if node := g.Nodes[nodeName]; node != nil {
-			if err := node.deleteVM(extras.kubeConfig); err != nil {
+	for _, node := range g.lastNodes(delta) {
+		if !node.failed() {
+			if err := g.removeNode(ctx, extras, node); err != nil {
 				glog.Errorf(errUnableToDeleteVM, node.NodeName, err)
 				return err
 			}
-
-			tempNodes = append(tempNodes, node)
 		}
+
+		tempNodes = append(tempNodes, node)
 	}
 
 	for _, node := range tempNodes {
@@ -148,10 +212,167 @@ func (g *MultipassNodeGroup) deleteNodes(delta int, extras *nodeCreationExtra) e
 	return nil
 }
 
-func (g *MultipassNodeGroup) addNodes(delta int, extras *nodeCreationExtra) error {
+// lastNodes returns the nodes removed when the node group shrink by delta, the last created first.
+// Warm and hibernated VMs take node indexes, the nodes are ordered by index, not looked up by name
+func (g *MultipassNodeGroup) lastNodes(delta int) []*MultipassNode {
+	nodes := make([]*MultipassNode, 0, len(g.Nodes))
+
+	for _, node := range g.Nodes {
+		nodes = append(nodes, node)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].NodeIndex == nodes[j].NodeIndex {
+			return nodes[i].NodeName > nodes[j].NodeName
+		}
+
+		return nodes[i].NodeIndex > nodes[j].NodeIndex
+	})
+
+	if -delta < len(nodes) {
+		nodes = nodes[:-delta]
+	}
+
+	return nodes
+}
+
+// standbyNode tells if the node is a stopped VM, warm or hibernated, not part of the node group
+func (g *MultipassNodeGroup) standbyNode(nodeName string) bool {
+	g.standbyLock.RLock()
+	defer g.standbyLock.RUnlock()
+
+	return g.WarmNodes[nodeName] != nil || g.PendingWarmNodes[nodeName] != nil || g.HibernatedNodes[nodeName] != nil
+}
+
+// standbyNodes returns a snapshot of the warm, pending warm and hibernated VMs
+func (g *MultipassNodeGroup) standbyNodes() []*MultipassNode {
+	g.standbyLock.RLock()
+	defer g.standbyLock.RUnlock()
+
+	nodes := make([]*MultipassNode, 0, len(g.WarmNodes)+len(g.PendingWarmNodes)+len(g.HibernatedNodes))
+
+	for _, standby := range []map[string]*MultipassNode{g.WarmNodes, g.PendingWarmNodes, g.HibernatedNodes} {
+		for _, node := range standby {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes
+}
+
+// startStoppedNodes start and uncordon up to delta VMs taken from stopped, it returns the number of nodes added to the group
+func (g *MultipassNodeGroup) startStoppedNodes(ctx context.Context, delta int, stopped map[string]*MultipassNode, extras *nodeCreationExtra) int {
+	var started int
+
+	g.standbyLock.RLock()
+
+	nodes := make([]*MultipassNode, 0, len(stopped))
+
+	for _, node := range stopped {
+		nodes = append(nodes, node)
+	}
+
+	g.standbyLock.RUnlock()
+
+	for _, node := range nodes {
+		nodeName := node.NodeName
+
+		if started >= delta || g.Status != NodegroupCreated {
+			break
+		}
+
+		g.standbyLock.Lock()
+		delete(stopped, nodeName)
+		g.standbyLock.Unlock()
+
+		err := node.startVM(ctx, extras)
+
+		if err == nil && extras.vmprovision {
+			joinCtx, cancel := context.WithTimeout(ctx, extras.timeouts.join())
+
+			err = node.waitReady(joinCtx, extras.kubeConfig, extras.timeouts)
+
+			cancel()
+
+			if err == nil {
+				err = node.waitReadinessGates(ctx, extras)
+			}
+		}
+
+		// Like a new node, the node failing its gates is reported as a failed instance
+		if kindOf(err) == kindNotReady {
+			glog.Errorf(errUnableToStartStoppedVM, nodeName, g.NodeGroupIdentifier, err)
+
+			node.HibernatedAt = nil
+
+			g.failReadinessGates(ctx, extras, node, err)
+
+			started++
+
+			continue
+		}
+
+		if err != nil {
+			glog.Errorf(errUnableToStartStoppedVM, nodeName, g.NodeGroupIdentifier, err)
+
+			if e := node.deleteVM(ctx, extras); e != nil {
+				glog.Errorf(errUnableToDeleteVM, nodeName, e)
+			}
+
+			continue
+		}
+
+		glog.Infof("Stopped VM:%s added to nodegroup: %s", nodeName, g.NodeGroupIdentifier)
+
+		node.HibernatedAt = nil
+
+		g.Nodes[nodeName] = node
+		started++
+	}
+
+	return started
+}
+
+// newNode returns the next node of the group, placed on host
+func (g *MultipassNodeGroup) newNode(host string) *MultipassNode {
+	g.LastCreatedNodeIndex++
+
+	nodeName := g.nodeName(g.LastCreatedNodeIndex)
+
+	return &MultipassNode{
+		ProviderID:       g.providerIDForNode(nodeName),
+		NodeName:         nodeName,
+		NodeIndex:        g.LastCreatedNodeIndex,
+		Memory:           g.Machine.Memory,
+		CPU:              g.Machine.Vcpu,
+		Disk:             g.Machine.Disk,
+		Host:             host,
+		AutoProvisionned: true,
+	}
+}
+
+func (g *MultipassNodeGroup) addNodes(ctx context.Context, delta int, extras *nodeCreationExtra) error {
 	glog.V(5).Infof("MultipassNodeGroup::addNodes, nodeGroupID:%s", g.NodeGroupIdentifier)
 
+	// Hibernated then warm VMs are started before launching new ones
+	if delta -= g.startStoppedNodes(ctx, delta, g.HibernatedNodes, extras); delta <= 0 {
+		return nil
+	}
+
+	if delta -= g.startStoppedNodes(ctx, delta, g.WarmNodes, extras); delta <= 0 {
+		return nil
+	}
+
 	tempNodes := make([]*MultipassNode, 0, delta)
+	hosts := make([]string, delta)
+
+	for index := range hosts {
+		var err error
+
+		if hosts[index], err = extras.placement.place(g.Machine); err != nil {
+			return err
+		}
+	}
 
 	g.PendingNodesWG.Add(delta)
 
@@ -161,19 +382,7 @@ func (g *MultipassNodeGroup) addNodes(delta int, extras *nodeCreationExtra) erro
 			break
 		}
 
-		g.LastCreatedNodeIndex++
-
-		nodeName := g.nodeName(g.LastCreatedNodeIndex)
-
-		node := &MultipassNode{
-			ProviderID:       g.providerIDForNode(nodeName),
-			NodeName:         nodeName,
-			NodeIndex:        g.LastCreatedNodeIndex,
-			Memory:           g.Machine.Memory,
-			CPU:              g.Machine.Vcpu,
-			Disk:             g.Machine.Disk,
-			AutoProvisionned: true,
-		}
+		node := g.newNode(hosts[nodeIndex])
 
 		tempNodes = append(tempNodes, node)
 
@@ -190,14 +399,34 @@ func (g *MultipassNodeGroup) addNodes(delta int, extras *nodeCreationExtra) erro
 			break
 		}
 
-		if err := node.launchVM(extras); err != nil {
+		err := node.launchVM(ctx, extras)
+
+		// The node failing its readiness gates is reported as a failed instance, the next ones are launched
+		if kindOf(err) == kindNotReady {
+			glog.Errorf(errUnableToLaunchVM, node.NodeName, err)
+
+			g.failReadinessGates(ctx, extras, node, err)
+
+			delete(g.PendingNodes, node.NodeName)
+
+			g.PendingNodesWG.Done()
+
+			continue
+		}
+
+		if err != nil {
 			glog.Errorf(errUnableToLaunchVM, node.NodeName, err)
 
 			for _, node := range tempNodes {
+				// The launched and failed nodes are not pending anymore
+				if g.PendingNodes[node.NodeName] == nil {
+					continue
+				}
+
 				delete(g.PendingNodes, node.NodeName)
 
-				if status, _ := node.statusVM(); status != MultipassNodeStateNotCreated {
-					if e := node.deleteVM(extras.kubeConfig); e != nil {
+				if status, _ := node.statusVM(ctx, extras); status != MultipassNodeStateNotCreated {
+					if e := node.deleteVM(ctx, extras); e != nil {
 						glog.Errorf(errUnableToDeleteVM, node.NodeName, e)
 					}
 				}
@@ -217,7 +446,25 @@ func (g *MultipassNodeGroup) addNodes(delta int, extras *nodeCreationExtra) erro
 	return nil
 }
 
-func (g *MultipassNodeGroup) autoDiscoveryNodes(scaleDownDisabled bool, kubeconfig string) error {
+// addFailedNodes register delta nodes that could not be created, the autoscaler will see them
+// as instances in error and will delete them
+func (g *MultipassNodeGroup) addFailedNodes(delta int, errorInfo *MultipassNodeErrorInfo) {
+	glog.V(5).Infof("MultipassNodeGroup::addFailedNodes, nodeGroupID:%s", g.NodeGroupIdentifier)
+
+	g.Lock()
+	defer g.Unlock()
+
+	for nodeIndex := 0; nodeIndex < delta; nodeIndex++ {
+		node := g.newNode("")
+
+		node.State = MultipassNodeStateNotCreated
+		node.ErrorInfo = errorInfo
+
+		g.Nodes[node.NodeName] = node
+	}
+}
+
+func (g *MultipassNodeGroup) autoDiscoveryNodes(ctx context.Context, scaleDownDisabled bool, extras *nodeCreationExtra) error {
 	var lastNodeIndex = 0
 	var nodeInfos apiv1.NodeList
 	var out string
@@ -229,15 +476,19 @@ func (g *MultipassNodeGroup) autoDiscoveryNodes(scaleDownDisabled bool, kubeconf
 		outputArgument,
 		jsonArgument,
 		kubeConfigArgument,
-		kubeconfig,
+		extras.kubeConfig,
 	}
 
-	if out, err = pipe(arg...); err != nil {
+	infoCtx, cancel := context.WithTimeout(ctx, extras.timeouts.info())
+
+	defer cancel()
+
+	if out, err = pipe(infoCtx, arg...); err != nil {
 		return err
 	}
 
 	if err = json.Unmarshal([]byte(out), &nodeInfos); err != nil {
-		return fmt.Errorf(errUnmarshallingError, "MultipassNodeGroup::autoDiscoveryNodes", err)
+		return wrapError(kindInternal, err, errUnmarshallingError, "MultipassNodeGroup::autoDiscoveryNodes", err)
 	}
 
 	formerNodes := g.Nodes
@@ -255,7 +506,7 @@ func (g *MultipassNodeGroup) autoDiscoveryNodes(scaleDownDisabled bool, kubeconf
 			if out == g.NodeGroupIdentifier {
 				glog.Infof("Discover node:%s matching nodegroup:%s", providerID, g.NodeGroupIdentifier)
 
-				if nodeID, err = nodeNameFromProviderID(g.ServiceIdentifier, providerID); err == nil {
+				if nodeID, err = nodeNameFromProviderID(g.ServiceIdentifier, providerID); err == nil && !g.standbyNode(nodeID) {
 					node := formerNodes[nodeID]
 
 					runningIP := ""
@@ -287,6 +538,11 @@ func (g *MultipassNodeGroup) autoDiscoveryNodes(scaleDownDisabled bool, kubeconf
 							},
 						}
 
+						// The zone label tells the hypervisor host running the node
+						if zone := nodeInfo.Labels[nodeLabelZone]; extras.hosts.declared(zone) {
+							node.Host = zone
+						}
+
 						arg = []string{
 							kubectlCommandLine,
 							annotateArgument,
@@ -297,10 +553,10 @@ func (g *MultipassNodeGroup) autoDiscoveryNodes(scaleDownDisabled bool, kubeconf
 							fmt.Sprintf("%s=%d", annotationNodeIndex, node.NodeIndex),
 							overwriteArgument,
 							kubeConfigArgument,
-							kubeconfig,
+							extras.kubeConfig,
 						}
 
-						if err := shell(arg...); err != nil {
+						if err := shell(infoCtx, arg...); err != nil {
 							glog.Errorf(errKubeCtlIgnoredError, nodeInfo.Name, err)
 						}
 
@@ -312,10 +568,10 @@ func (g *MultipassNodeGroup) autoDiscoveryNodes(scaleDownDisabled bool, kubeconf
 							fmt.Sprintf("%s=%s", nodeLabelGroupName, g.NodeGroupIdentifier),
 							overwriteArgument,
 							kubeConfigArgument,
-							kubeconfig,
+							extras.kubeConfig,
 						}
 
-						if err := shell(arg...); err != nil {
+						if err := shell(infoCtx, arg...); err != nil {
 							glog.Errorf(errKubeCtlIgnoredError, nodeInfo.Name, err)
 						}
 					}
@@ -324,7 +580,7 @@ func (g *MultipassNodeGroup) autoDiscoveryNodes(scaleDownDisabled bool, kubeconf
 
 					g.Nodes[nodeID] = node
 
-					node.statusVM()
+					node.statusVM(ctx, extras)
 				}
 			}
 		}
@@ -333,12 +589,14 @@ func (g *MultipassNodeGroup) autoDiscoveryNodes(scaleDownDisabled bool, kubeconf
 	return nil
 }
 
-func (g *MultipassNodeGroup) deleteNodeByName(kubeconfig, nodeName string) error {
+func (g *MultipassNodeGroup) deleteNodeByName(ctx context.Context, extras *nodeCreationExtra, nodeName string) error {
 	glog.V(5).Infof("MultipassNodeGroup::deleteNodeByName, nodeGroupID:%s, nodeName:%s", g.NodeGroupIdentifier, nodeName)
 
 	if node := g.Nodes[nodeName]; node != nil {
 
-		if err := node.deleteVM(kubeconfig); err != nil {
+		if node.failed() {
+			glog.Infof("Forget failed node:%s, reason: %s", nodeName, node.ErrorInfo.Message)
+		} else if err := g.removeNode(ctx, extras, node); err != nil {
 			glog.Errorf(errUnableToDeleteVM, node.NodeName, err)
 			return err
 		}
@@ -348,13 +606,76 @@ func (g *MultipassNodeGroup) deleteNodeByName(kubeconfig, nodeName string) error
 		return nil
 	}
 
-	return fmt.Errorf(errNodeNotFoundInNodeGroup, nodeName, g.NodeGroupIdentifier)
+	return newError(kindNotFound, errNodeNotFoundInNodeGroup, nodeName, g.NodeGroupIdentifier)
 }
 
-func (g *MultipassNodeGroup) deleteNodeGroup(kubeConfig string) error {
+func (g *MultipassNodeGroup) deleteNodeGroup(ctx context.Context, extras *nodeCreationExtra) error {
 	glog.V(5).Infof("MultipassNodeGroup::deleteNodeGroup, nodeGroupID:%s", g.NodeGroupIdentifier)
 
-	return g.cleanup(kubeConfig)
+	return g.cleanup(ctx, extras)
+}
+
+// nodeLabels returns all the labels that will be stamped on a node of this group
+func (g *MultipassNodeGroup) nodeLabels() map[string]string {
+	labels := make(map[string]string)
+
+	if g.Machine != nil {
+		for k, v := range g.Machine.nodeLabels(g.MachineType) {
+			labels[k] = v
+		}
+	}
+
+	for k, v := range g.SystemLabels {
+		labels[k] = v
+	}
+
+	for k, v := range g.NodeLabels {
+		labels[k] = v
+	}
+
+	labels[nodeLabelGroupName] = g.NodeGroupIdentifier
+
+	return labels
+}
+
+// templateNode build a node as if it was just started in this group
+func (g *MultipassNodeGroup) templateNode() *apiv1.Node {
+	nodeName := g.nodeName(g.LastCreatedNodeIndex + 1)
+	labels := g.nodeLabels()
+
+	labels[nodeLabelHostname] = nodeName
+
+	node := &apiv1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   nodeName,
+			Labels: labels,
+			Annotations: map[string]string{
+				nodeLabelGroupName:             g.NodeGroupIdentifier,
+				annotationNodeAutoProvisionned: "true",
+				annotationNodeIndex:            strconv.Itoa(g.LastCreatedNodeIndex + 1),
+			},
+		},
+		Spec: apiv1.NodeSpec{
+			ProviderID:    g.providerIDForNode(nodeName),
+			Unschedulable: false,
+		},
+		Status: apiv1.NodeStatus{
+			Conditions: []apiv1.NodeCondition{
+				{
+					Type:   apiv1.NodeReady,
+					Status: apiv1.ConditionTrue,
+				},
+			},
+		},
+	}
+
+	if g.Machine != nil {
+		node.Status.Capacity = g.Machine.capacity()
+		node.Status.Allocatable = g.Kubelet.allocatable(node.Status.Capacity)
+		node.Status.Capacity[apiv1.ResourcePods] = node.Status.Allocatable[apiv1.ResourcePods]
+	}
+
+	return node
 }
 
 func (g *MultipassNodeGroup) nodeName(vmIndex int) string {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"os"
@@ -53,6 +54,12 @@ var testNode = []nodeTest{
 	},
 }
 
+var testExtras = &nodeCreationExtra{
+	kubeConfig:  kubeconfig,
+	nodegroupID: testGroupID,
+	backend:     newMultipassBackend(""),
+}
+
 func newTestConfig() (*MultipassServerConfig, error) {
 	var config MultipassServerConfig
 
@@ -68,6 +75,7 @@ func newTestConfig() (*MultipassServerConfig, error) {
 
 func Test_multipassNode_launchVM(t *testing.T) {
 	config, err := newTestConfig()
+	cacheDir, _ := os.UserCacheDir()
 
 	if assert.NoError(t, err) {
 		for _, tt := range testNode {
@@ -100,10 +108,11 @@ func Test_multipassNode_launchVM(t *testing.T) {
 					nodeLabels:    nodeLabels,
 					systemLabels:  make(map[string]string),
 					vmprovision:   config.VMProvision,
-					cacheDir:      os.UserCacheDir(),
+					cacheDir:      cacheDir,
+					backend:       newMultipassBackend(cacheDir),
 				}
 
-				if err := vm.launchVM(extras); (err != nil) != tt.wantErr {
+				if err := vm.launchVM(context.Background(), extras); (err != nil) != tt.wantErr {
 					t.Errorf("multipassNode.launchVM() error = %v, wantErr %v", err, tt.wantErr)
 				}
 			})
@@ -123,7 +132,7 @@ func Test_multipassNode_startVM(t *testing.T) {
 				State:            MultipassNodeStateNotCreated,
 				AutoProvisionned: true,
 			}
-			if err := vm.startVM(kubeconfig); (err != nil) != tt.wantErr {
+			if err := vm.startVM(context.Background(), testExtras); (err != nil) != tt.wantErr {
 				t.Errorf("multipassNode.startVM() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -142,7 +151,7 @@ func Test_multipassNode_stopVM(t *testing.T) {
 				State:            MultipassNodeStateNotCreated,
 				AutoProvisionned: true,
 			}
-			if err := vm.stopVM(kubeconfig); (err != nil) != tt.wantErr {
+			if err := vm.stopVM(context.Background(), testExtras); (err != nil) != tt.wantErr {
 				t.Errorf("multipassNode.stopVM() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -161,7 +170,7 @@ func Test_multipassNode_deleteVM(t *testing.T) {
 				State:            MultipassNodeStateNotCreated,
 				AutoProvisionned: true,
 			}
-			if err := vm.deleteVM(kubeconfig); (err != nil) != tt.wantErr {
+			if err := vm.deleteVM(context.Background(), testExtras); (err != nil) != tt.wantErr {
 				t.Errorf("multipassNode.deleteVM() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -180,7 +189,7 @@ func Test_multipassNode_statusVM(t *testing.T) {
 				State:            MultipassNodeStateNotCreated,
 				AutoProvisionned: true,
 			}
-			got, err := vm.statusVM()
+			got, err := vm.statusVM(context.Background(), testExtras)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("multipassNode.statusVM() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -194,6 +203,7 @@ func Test_multipassNode_statusVM(t *testing.T) {
 
 func Test_multipassNodeGroup_addNode(t *testing.T) {
 	config, err := newTestConfig()
+	cacheDir, _ := os.UserCacheDir()
 
 	if assert.NoError(t, err) {
 		extras := &nodeCreationExtra{
@@ -209,7 +219,8 @@ func Test_multipassNodeGroup_addNode(t *testing.T) {
 			nodeLabels:    testNodeGroup.NodeLabels,
 			systemLabels:  testNodeGroup.SystemLabels,
 			vmprovision:   config.VMProvision,
-			cacheDir:      os.UserCacheDir(),
+			cacheDir:      cacheDir,
+			backend:       newMultipassBackend(cacheDir),
 		}
 
 		tests := []struct {
@@ -228,7 +239,7 @@ func Test_multipassNodeGroup_addNode(t *testing.T) {
 
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
-				if err := tt.ng.addNodes(tt.delta, extras); (err != nil) != tt.wantErr {
+				if err := tt.ng.addNodes(context.Background(), tt.delta, extras); (err != nil) != tt.wantErr {
 					t.Errorf("MultipassNodeGroup.addNode() error = %v, wantErr %v", err, tt.wantErr)
 				}
 			})
@@ -280,7 +291,7 @@ func Test_multipassNodeGroup_deleteNode(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := tt.ng.deleteNodeByName(kubeconfig, tt.nodeName); (err != nil) != tt.wantErr {
+			if err := tt.ng.deleteNodeByName(context.Background(), testExtras, tt.nodeName); (err != nil) != tt.wantErr {
 				t.Errorf("MultipassNodeGroup.deleteNode() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -330,7 +341,7 @@ func Test_multipassNodeGroup_deleteNodeGroup(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := tt.ng.deleteNodeGroup(kubeconfig); (err != nil) != tt.wantErr {
+			if err := tt.ng.deleteNodeGroup(context.Background(), testExtras); (err != nil) != tt.wantErr {
 				t.Errorf("MultipassNodeGroup.deleteNodeGroup() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
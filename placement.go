@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	apigrpc "github.com/Fred78290/kubernetes-multipass-autoscaler/grpc"
+	"github.com/golang/glog"
+)
+
+const (
+	placementSpread  = "spread"
+	placementBinPack = "binpack"
+
+	nodeLabelZone = "topology.kubernetes.io/zone"
+
+	errorCodeNoHostAvailable = "NoHostAvailable"
+)
+
+// HostPlacement declare how the new nodes are placed on the hypervisor hosts
+type HostPlacement struct {
+	Strategy     string              `json:"strategy"`      // Optional, spread or binpack, spread by default
+	Affinity     map[string][]string `json:"affinity"`      // Optional, per node group, the only hosts allowed to run its nodes
+	AntiAffinity map[string][]string `json:"anti-affinity"` // Optional, per node group, the hosts never running its nodes
+}
+
+// hostAllocation count what is allocated on a host
+type hostAllocation struct {
+	nodes  int
+	memory int
+}
+
+// hostPlacement choose the host of the new nodes of a node group
+type hostPlacement struct {
+	strategy    string
+	candidates  []*HypervisorHost
+	allocations map[string]*hostAllocation
+}
+
+// validate checks the strategy and that affinities reference declared hosts
+func (p *HostPlacement) validate(hosts []*HypervisorHost) error {
+	if p == nil {
+		return nil
+	}
+
+	switch p.Strategy {
+	case "", placementSpread, placementBinPack:
+	default:
+		return newError(kindInvalidArgument, errUnknownPlacementStrategy, p.Strategy)
+	}
+
+	declared := make(map[string]bool)
+
+	for _, host := range hosts {
+		declared[host.Name] = true
+	}
+
+	for _, affinities := range []map[string][]string{p.Affinity, p.AntiAffinity} {
+		for nodeGroupID, names := range affinities {
+			for _, name := range names {
+				if !declared[name] {
+					return newError(kindInvalidArgument, errPlacementHostNotDeclared, name, nodeGroupID)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// allowed tells if the node group could run nodes on host
+func (p *HostPlacement) allowed(nodeGroupID, host string) bool {
+	if p == nil {
+		return true
+	}
+
+	for _, name := range p.AntiAffinity[nodeGroupID] {
+		if name == host {
+			return false
+		}
+	}
+
+	if affinity := p.Affinity[nodeGroupID]; len(affinity) > 0 {
+		for _, name := range affinity {
+			if name == host {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// newHostPlacement returns the placement of the node group new nodes, nil when no hosts are declared
+func (s *MultipassServer) newHostPlacement(nodeGroup *MultipassNodeGroup) *hostPlacement {
+	if len(s.Configuration.Hosts) == 0 {
+		return nil
+	}
+
+	policy := s.Configuration.Placement
+
+	p := &hostPlacement{
+		strategy:    placementSpread,
+		candidates:  make([]*HypervisorHost, 0, len(s.Configuration.Hosts)),
+		allocations: make(map[string]*hostAllocation),
+	}
+
+	if policy != nil && len(policy.Strategy) > 0 {
+		p.strategy = policy.Strategy
+	}
+
+	for _, host := range s.Configuration.Hosts {
+		if policy.allowed(nodeGroup.NodeGroupIdentifier, host.Name) {
+			p.candidates = append(p.candidates, host)
+		}
+	}
+
+	for _, group := range s.Groups {
+		nodes, pendingNodes := group.allocatedNodes()
+
+		for _, node := range nodes {
+			if !node.failed() && node.State != MultipassNodeStateDeleted {
+				p.allocate(node.Host, node.Memory)
+			}
+		}
+
+		for _, node := range pendingNodes {
+			p.allocate(node.Host, node.Memory)
+		}
+
+		// Stopped warm and hibernated VMs keep their host resources
+		for _, node := range group.standbyNodes() {
+			p.allocate(node.Host, node.Memory)
+		}
+	}
+
+	return p
+}
+
+func (p *hostPlacement) allocation(host string) *hostAllocation {
+	allocation := p.allocations[host]
+
+	if allocation == nil {
+		allocation = &hostAllocation{}
+		p.allocations[host] = allocation
+	}
+
+	return allocation
+}
+
+func (p *hostPlacement) allocate(host string, memory int) {
+	allocation := p.allocation(host)
+
+	allocation.nodes++
+	allocation.memory += memory
+}
+
+// freeMemory returns the memory not allocated on the host, unlimited when the host memory is not declared, only used to check the fit
+func (p *hostPlacement) freeMemory(host *HypervisorHost) int {
+	if host.Memory <= 0 {
+		return math.MaxInt32
+	}
+
+	return host.Memory - p.allocation(host.Name).memory
+}
+
+// better tells if host is a better choice than chosen for the strategy, ties keep the declaration order
+func (p *hostPlacement) better(host, chosen *HypervisorHost) bool {
+	if p.strategy == placementBinPack {
+		// The free memory is unknown, fill the host running the most VMs
+		if host.Memory <= 0 || chosen.Memory <= 0 {
+			return p.allocation(host.Name).nodes > p.allocation(chosen.Name).nodes
+		}
+
+		return p.freeMemory(host) < p.freeMemory(chosen)
+	}
+
+	return p.allocation(host.Name).nodes < p.allocation(chosen.Name).nodes
+}
+
+// place choose the host of a new node and allocate it, empty when no hosts are declared
+func (p *hostPlacement) place(machine *MachineCharacteristic) (string, error) {
+	var chosen *HypervisorHost
+	var memory int
+
+	if p == nil {
+		return "", nil
+	}
+
+	if machine != nil {
+		memory = machine.Memory
+	}
+
+	for _, host := range p.candidates {
+		if p.freeMemory(host) < memory {
+			continue
+		}
+
+		if chosen == nil || p.better(host, chosen) {
+			chosen = host
+		}
+	}
+
+	if chosen == nil {
+		return "", newError(kindFailedPrecondition, errNoHostAvailable, memory)
+	}
+
+	p.allocate(chosen.Name, memory)
+
+	return chosen.Name, nil
+}
+
+// checkPlacement returns an error info if delta nodes of the node group can't be placed on the hosts
+func (s *MultipassServer) checkPlacement(nodeGroup *MultipassNodeGroup, delta int) *MultipassNodeErrorInfo {
+	placement := s.newHostPlacement(nodeGroup)
+
+	for index := 0; index < delta; index++ {
+		if _, err := placement.place(nodeGroup.Machine); err != nil {
+			message := fmt.Sprintf(errUnableToPlaceNodes, delta, nodeGroup.NodeGroupIdentifier, err)
+
+			glog.Errorf(message)
+
+			return &MultipassNodeErrorInfo{
+				Class:   apigrpc.InstanceErrorClass_ERROR_OUT_OF_RESOURCES,
+				Code:    errorCodeNoHostAvailable,
+				Message: message,
+			}
+		}
+	}
+
+	return nil
+}
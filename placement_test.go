@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	apigrpc "github.com/Fred78290/kubernetes-multipass-autoscaler/grpc"
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+var testHosts = []*HypervisorHost{
+	{Name: "host-1", Memory: 16384},
+	{Name: "host-2", Memory: 32768},
+	{Name: "host-3"},
+}
+
+func newTestPlacementServer(t *testing.T, placement *HostPlacement) (*MultipassServer, *MultipassNodeGroup) {
+	ng := newTestNodeGroup("large", withMaxNodeSize(10))
+
+	s, _, err := newTestServerWithConfig(ng, MultipassServerConfig{
+		ProviderID: testProviderID,
+		Machines:   testMachines,
+		Hosts:      testHosts,
+		Placement:  placement,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s, ng
+}
+
+func Test_hostPlacement_place(t *testing.T) {
+	tests := []struct {
+		name      string
+		placement *HostPlacement
+		want      []string
+	}{
+		{
+			name: "Spread",
+			want: []string{"host-2", "host-3", "host-1", "host-2", "host-3"},
+		},
+		{
+			name:      "BinPack",
+			placement: &HostPlacement{Strategy: placementBinPack},
+			want:      []string{"host-1", "host-2", "host-2", "host-2", "host-2"},
+		},
+		{
+			name: "Affinity",
+			placement: &HostPlacement{
+				Affinity: map[string][]string{testGroupID: {"host-1", "host-2"}},
+			},
+			want: []string{"host-2", "host-1", "host-2", "host-2", "host-2"},
+		},
+		{
+			name: "AntiAffinity",
+			placement: &HostPlacement{
+				Strategy:     placementBinPack,
+				AntiAffinity: map[string][]string{testGroupID: {"host-1", "host-2"}},
+			},
+			want: []string{"host-3", "host-3", "host-3", "host-3", "host-3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ng := newTestPlacementServer(t, tt.placement)
+
+			// An existing node already allocated on host-1
+			ng.Nodes["existing"] = &MultipassNode{Host: "host-1", Memory: 4096, State: MultipassNodeStateRunning}
+
+			placement := s.newHostPlacement(ng)
+			got := make([]string, 0, len(tt.want))
+
+			for range tt.want {
+				host, err := placement.place(ng.Machine)
+
+				assert.NoError(t, err)
+
+				got = append(got, host)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_hostPlacement_binPackWithoutMemory(t *testing.T) {
+	p := &hostPlacement{
+		strategy:    placementBinPack,
+		candidates:  []*HypervisorHost{{Name: "host-1", Memory: 65536}, {Name: "host-2"}, {Name: "host-3"}},
+		allocations: make(map[string]*hostAllocation),
+	}
+
+	p.allocate("host-3", 2048)
+
+	for range []int{1, 2, 3} {
+		host, err := p.place(testMachines["large"])
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "host-3", host, "the host running the most VMs is filled")
+		}
+	}
+}
+
+func Test_hostPlacement_concurrentScaleUp(t *testing.T) {
+	s, ng := newTestPlacementServer(t, nil)
+	done := make(chan struct{})
+
+	// A node group growing in background is read under its lock
+	go func() {
+		defer close(done)
+
+		for index := 0; index < 50; index++ {
+			ng.Lock()
+			ng.PendingNodes[ng.nodeName(index)] = &MultipassNode{NodeName: ng.nodeName(index), Host: "host-2", Memory: 1024}
+			ng.Unlock()
+		}
+	}()
+
+	for index := 0; index < 50; index++ {
+		s.newHostPlacement(ng)
+	}
+
+	<-done
+
+	assert.Equal(t, 50, s.newHostPlacement(ng).allocation("host-2").nodes)
+}
+
+func Test_hostPlacement_exhausted(t *testing.T) {
+	s, ng := newTestPlacementServer(t, &HostPlacement{
+		Affinity: map[string][]string{testGroupID: {"host-1"}},
+	})
+
+	errorInfo := s.checkPlacement(ng, 3)
+
+	if assert.NotNil(t, errorInfo) {
+		assert.Equal(t, apigrpc.InstanceErrorClass_ERROR_OUT_OF_RESOURCES, errorInfo.Class)
+		assert.Equal(t, errorCodeNoHostAvailable, errorInfo.Code)
+	}
+
+	assert.Nil(t, s.checkPlacement(ng, 2))
+
+	s.Configuration.Hosts = nil
+
+	assert.Nil(t, s.newHostPlacement(ng))
+	assert.Nil(t, s.checkPlacement(ng, 3))
+}
+
+func Test_HostPlacement_validate(t *testing.T) {
+	assert.NoError(t, (*HostPlacement)(nil).validate(testHosts))
+	assert.NoError(t, (&HostPlacement{Strategy: placementSpread}).validate(testHosts))
+	assert.Error(t, (&HostPlacement{Strategy: "random"}).validate(testHosts))
+	assert.Error(t, (&HostPlacement{AntiAffinity: map[string][]string{testGroupID: {"host-4"}}}).validate(testHosts))
+}
+
+func Test_server_TemplateNodeInfoZone(t *testing.T) {
+	s, ng := newTestPlacementServer(t, nil)
+
+	ng.Nodes["existing"] = &MultipassNode{Host: "host-1", Memory: 4096, State: MultipassNodeStateRunning}
+
+	reply, err := s.TemplateNodeInfo(context.Background(), &apigrpc.NodeGroupServiceRequest{
+		ProviderID:  testProviderID,
+		NodeGroupID: testGroupID,
+	})
+
+	if assert.NoError(t, err) {
+		var node apiv1.Node
+
+		if assert.NoError(t, json.Unmarshal([]byte(reply.GetNodeInfo().GetNode()), &node)) {
+			assert.Equal(t, "host-2", node.Labels[nodeLabelZone])
+		}
+	}
+}
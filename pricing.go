@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+
+	apiv1 "k8s.io/api/core/v1"
+)
+
+const (
+	secondsPerHour = 3600
+	bytesPerGiB    = 1024 * 1024 * 1024
+)
+
+// PricingModel declare the unit costs used to compute node and pod prices
+// when the machine type doesn't declare its own price
+type PricingModel struct {
+	CPU    float64 `json:"cpu"`    // Price of one vcpu per hour
+	Memory float64 `json:"memory"` // Price of one GiB of memory per hour
+}
+
+// podFromJSON deserialize a string to apiv1.Pod
+func podFromJSON(s string) (*apiv1.Pod, error) {
+	data := &apiv1.Pod{}
+
+	err := json.Unmarshal([]byte(s), &data)
+
+	return data, err
+}
+
+// pricingDuration returns the number of hours between startTime and endTime expressed in unix seconds
+func pricingDuration(startTime, endTime int64) (float64, error) {
+	if endTime < startTime {
+		return 0, newError(kindInvalidArgument, errPricingWrongInterval, startTime, endTime)
+	}
+
+	return float64(endTime-startTime) / secondsPerHour, nil
+}
+
+// hourlyPrice returns the price per hour of a machine
+func (s *MultipassServer) hourlyPrice(machine *MachineCharacteristic) float64 {
+	if machine != nil {
+		if machine.Price > 0 {
+			return machine.Price
+		}
+
+		if model := s.Configuration.PricingModel; model != nil {
+			return float64(machine.Vcpu)*model.CPU + float64(machine.Memory)/1024*model.Memory
+		}
+	}
+
+	return s.Configuration.NodePrice
+}
+
+// nodePrice returns the price of running the node between startTime and endTime
+func (s *MultipassServer) nodePrice(node *apiv1.Node, startTime, endTime int64) (float64, error) {
+	hours, err := pricingDuration(startTime, endTime)
+
+	if err != nil {
+		return 0, err
+	}
+
+	_, machine := s.machineForNode(node)
+
+	return s.hourlyPrice(machine) * hours, nil
+}
+
+// podRequests returns the effective cpu and memory requests of a pod
+func podRequests(pod *apiv1.Pod) (cpu float64, memory float64) {
+	for _, container := range pod.Spec.Containers {
+		cpu += float64(container.Resources.Requests.Cpu().MilliValue()) / 1000
+		memory += float64(container.Resources.Requests.Memory().Value())
+	}
+
+	// Init containers run sequentially, the pod needs at least the biggest one
+	for _, container := range pod.Spec.InitContainers {
+		if initCPU := float64(container.Resources.Requests.Cpu().MilliValue()) / 1000; initCPU > cpu {
+			cpu = initCPU
+		}
+
+		if initMemory := float64(container.Resources.Requests.Memory().Value()); initMemory > memory {
+			memory = initMemory
+		}
+	}
+
+	return cpu, memory
+}
+
+// podPrice returns the theoretical price of running the pod between startTime and endTime
+func (s *MultipassServer) podPrice(pod *apiv1.Pod, startTime, endTime int64) (float64, error) {
+	hours, err := pricingDuration(startTime, endTime)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if model := s.Configuration.PricingModel; model != nil {
+		cpu, memory := podRequests(pod)
+
+		return (cpu*model.CPU + memory/bytesPerGiB*model.Memory) * hours, nil
+	}
+
+	return s.Configuration.PodPrice * hours, nil
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apigrpc "github.com/Fred78290/kubernetes-multipass-autoscaler/grpc"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestPricingServer() (*MultipassServer, error) {
+	config := MultipassServerConfig{
+		ProviderID: testProviderID,
+		NodePrice:  0.01,
+		PodPrice:   0.001,
+		Machines:   testMachines,
+		PricingModel: &PricingModel{
+			CPU:    0.02,
+			Memory: 0.01,
+		},
+	}
+
+	s, _, err := newTestServerWithConfig(newTestNodeGroup("medium"), config)
+
+	return s, err
+}
+
+func TestMultipassServer_NodePriceByMachineType(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		request *apigrpc.NodePriceRequest
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "InstanceTypeLabel",
+			want: 0.2 * 2,
+			request: &apigrpc.NodePriceRequest{
+				ProviderID: testProviderID,
+				StartTime:  now.Unix(),
+				EndTime:    now.Add(2 * time.Hour).Unix(),
+				Node: toJSON(apiv1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							nodeLabelInstanceType: "large",
+						},
+					},
+				}),
+			},
+		},
+		{
+			name: "NodeGroupMachine",
+			want: 0.1 / 2,
+			request: &apigrpc.NodePriceRequest{
+				ProviderID: testProviderID,
+				StartTime:  now.Unix(),
+				EndTime:    now.Add(30 * time.Minute).Unix(),
+				Node: toJSON(apiv1.Node{
+					Spec: apiv1.NodeSpec{
+						ProviderID: fmt.Sprintf("%s://%s/object?type=node&name=%s", testProviderID, testGroupID, testNodeName),
+					},
+				}),
+			},
+		},
+		{
+			name: "UnknownNode",
+			want: 0.01,
+			request: &apigrpc.NodePriceRequest{
+				ProviderID: testProviderID,
+				StartTime:  now.Unix(),
+				EndTime:    now.Add(time.Hour).Unix(),
+				Node:       toJSON(apiv1.Node{}),
+			},
+		},
+		{
+			name:    "WrongInterval",
+			wantErr: true,
+			request: &apigrpc.NodePriceRequest{
+				ProviderID: testProviderID,
+				StartTime:  now.Unix(),
+				EndTime:    now.Add(-time.Hour).Unix(),
+				Node:       toJSON(apiv1.Node{}),
+			},
+		},
+	}
+
+	s, err := newTestPricingServer()
+
+	if assert.NoError(t, err) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := s.NodePrice(nil, tt.request)
+
+				if err != nil {
+					t.Errorf("MultipassServer.NodePrice() error = %v", err)
+				} else if (got.GetError() != nil) != tt.wantErr {
+					t.Errorf("MultipassServer.NodePrice() return an error = %v, wantErr %v", got.GetError(), tt.wantErr)
+				} else if math.Abs(got.GetPrice()-tt.want) > 1e-9 {
+					t.Errorf("MultipassServer.NodePrice() = %v, want %v", got.GetPrice(), tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestMultipassServer_PodPriceByRequests(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		request *apigrpc.PodPriceRequest
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "ContainersRequests",
+			// 1.5 cpu * 0.02 + 2 GiB * 0.01, for 2 hours
+			want: (1.5*0.02 + 2*0.01) * 2,
+			request: &apigrpc.PodPriceRequest{
+				ProviderID: testProviderID,
+				StartTime:  now.Unix(),
+				EndTime:    now.Add(2 * time.Hour).Unix(),
+				Pod: toJSON(apiv1.Pod{
+					Spec: apiv1.PodSpec{
+						Containers: []apiv1.Container{
+							{
+								Resources: apiv1.ResourceRequirements{
+									Requests: apiv1.ResourceList{
+										apiv1.ResourceCPU:    resource.MustParse("1"),
+										apiv1.ResourceMemory: resource.MustParse("1Gi"),
+									},
+								},
+							},
+							{
+								Resources: apiv1.ResourceRequirements{
+									Requests: apiv1.ResourceList{
+										apiv1.ResourceCPU:    resource.MustParse("500m"),
+										apiv1.ResourceMemory: resource.MustParse("1Gi"),
+									},
+								},
+							},
+						},
+					},
+				}),
+			},
+		},
+		{
+			name: "InitContainerBigger",
+			want: 4*0.02 + 0.5*0.01,
+			request: &apigrpc.PodPriceRequest{
+				ProviderID: testProviderID,
+				StartTime:  now.Unix(),
+				EndTime:    now.Add(time.Hour).Unix(),
+				Pod: toJSON(apiv1.Pod{
+					Spec: apiv1.PodSpec{
+						InitContainers: []apiv1.Container{
+							{
+								Resources: apiv1.ResourceRequirements{
+									Requests: apiv1.ResourceList{
+										apiv1.ResourceCPU: resource.MustParse("4"),
+									},
+								},
+							},
+						},
+						Containers: []apiv1.Container{
+							{
+								Resources: apiv1.ResourceRequirements{
+									Requests: apiv1.ResourceList{
+										apiv1.ResourceCPU:    resource.MustParse("1"),
+										apiv1.ResourceMemory: resource.MustParse("512Mi"),
+									},
+								},
+							},
+						},
+					},
+				}),
+			},
+		},
+		{
+			name:    "WrongPod",
+			wantErr: true,
+			request: &apigrpc.PodPriceRequest{
+				ProviderID: testProviderID,
+				StartTime:  now.Unix(),
+				EndTime:    now.Add(time.Hour).Unix(),
+				Pod:        "{",
+			},
+		},
+	}
+
+	s, err := newTestPricingServer()
+
+	if assert.NoError(t, err) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := s.PodPrice(nil, tt.request)
+
+				if err != nil {
+					t.Errorf("MultipassServer.PodPrice() error = %v", err)
+				} else if (got.GetError() != nil) != tt.wantErr {
+					t.Errorf("MultipassServer.PodPrice() return an error = %v, wantErr %v", got.GetError(), tt.wantErr)
+				} else if math.Abs(got.GetPrice()-tt.want) > 1e-9 {
+					t.Errorf("MultipassServer.PodPrice() = %v, want %v", got.GetPrice(), tt.want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	apigrpc "github.com/Fred78290/kubernetes-multipass-autoscaler/grpc"
+	"github.com/golang/glog"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+const (
+	defaultReadinessGateTimeout = 300
+
+	errorCodeReadinessGates = "ReadinessGatesFailed"
+
+	podsArgument          string = "pods"
+	fieldSelectorArgument string = "--field-selector"
+)
+
+// ReadinessGate declare a condition checked once the node is Ready, before it's given to the autoscaler.
+// Only one of daemonset, condition or label is set
+type ReadinessGate struct {
+	DaemonSet string `json:"daemonset,omitempty"` // namespace/name of a DaemonSet with a pod running and ready on the node
+	Condition string `json:"condition,omitempty"` // node condition type, ie: NetworkUnavailable
+	Status    string `json:"status,omitempty"`    // expected status of the node condition, True by default
+	Label     string `json:"label,omitempty"`     // node label, ie: set by node-feature-discovery
+	Value     string `json:"value,omitempty"`     // expected value of the label, any value if empty
+	Timeout   int    `json:"timeout,omitempty"`   // seconds, 300 by default
+}
+
+func (g *ReadinessGate) validate(nodeGroupID string) error {
+	var kinds int
+
+	for _, value := range []string{g.DaemonSet, g.Condition, g.Label} {
+		if len(value) > 0 {
+			kinds++
+		}
+	}
+
+	if kinds != 1 {
+		return newError(kindInvalidArgument, errInvalidReadinessGate, g, nodeGroupID, "one of daemonset, condition or label must be set")
+	}
+
+	if len(g.DaemonSet) > 0 && len(strings.Split(g.DaemonSet, "/")) != 2 {
+		return newError(kindInvalidArgument, errInvalidReadinessGate, g, nodeGroupID, "daemonset is namespace/name")
+	}
+
+	switch apiv1.ConditionStatus(g.Status) {
+	case "", apiv1.ConditionTrue, apiv1.ConditionFalse, apiv1.ConditionUnknown:
+	default:
+		return newError(kindInvalidArgument, errInvalidReadinessGate, g, nodeGroupID, "unknown status "+g.Status)
+	}
+
+	if g.Timeout < 0 {
+		return newError(kindInvalidArgument, errInvalidReadinessGate, g, nodeGroupID, "timeout is negative")
+	}
+
+	return nil
+}
+
+func (g *ReadinessGate) String() string {
+	if len(g.DaemonSet) > 0 {
+		return "daemonset " + g.DaemonSet
+	}
+
+	if len(g.Condition) > 0 {
+		return fmt.Sprintf("condition %s=%s", g.Condition, g.status())
+	}
+
+	if len(g.Value) > 0 {
+		return fmt.Sprintf("label %s=%s", g.Label, g.Value)
+	}
+
+	return "label " + g.Label
+}
+
+func (g *ReadinessGate) status() apiv1.ConditionStatus {
+	if len(g.Status) == 0 {
+		return apiv1.ConditionTrue
+	}
+
+	return apiv1.ConditionStatus(g.Status)
+}
+
+func (g *ReadinessGate) timeout() time.Duration {
+	return timeoutOrDefault(g.Timeout, defaultReadinessGateTimeout)
+}
+
+// nodeSatisfied tells if the node has the expected condition or label
+func (g *ReadinessGate) nodeSatisfied(node *apiv1.Node) bool {
+	if len(g.Condition) > 0 {
+		for _, condition := range node.Status.Conditions {
+			if string(condition.Type) == g.Condition {
+				return condition.Status == g.status()
+			}
+		}
+
+		return false
+	}
+
+	value, found := node.Labels[g.Label]
+
+	return found && (len(g.Value) == 0 || value == g.Value)
+}
+
+// podsSatisfied tells if a pod of the DaemonSet is running and ready in the node pods
+func (g *ReadinessGate) podsSatisfied(pods *apiv1.PodList) bool {
+	name := strings.Split(g.DaemonSet, "/")[1]
+
+	for _, pod := range pods.Items {
+		owned := false
+
+		for _, owner := range pod.OwnerReferences {
+			if owner.Kind == "DaemonSet" && owner.Name == name {
+				owned = true
+			}
+		}
+
+		if !owned || pod.Status.Phase != apiv1.PodRunning {
+			continue
+		}
+
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == apiv1.PodReady && condition.Status == apiv1.ConditionTrue {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// satisfied check the gate against the cluster
+func (g *ReadinessGate) satisfied(ctx context.Context, vm *MultipassNode, extras *nodeCreationExtra) (bool, error) {
+	var args []string
+
+	if len(g.DaemonSet) > 0 {
+		args = []string{
+			kubectlCommandLine,
+			getArgument,
+			podsArgument,
+			namespaceArgument,
+			strings.Split(g.DaemonSet, "/")[0],
+			fieldSelectorArgument,
+			"spec.nodeName=" + vm.NodeName,
+			outputArgument,
+			jsonArgument,
+			kubeConfigArgument,
+			extras.kubeConfig,
+		}
+	} else {
+		args = []string{
+			kubectlCommandLine,
+			getArgument,
+			nodesArgument,
+			vm.NodeName,
+			outputArgument,
+			jsonArgument,
+			kubeConfigArgument,
+			extras.kubeConfig,
+		}
+	}
+
+	infoCtx, cancel := context.WithTimeout(ctx, extras.timeouts.info())
+
+	defer cancel()
+
+	out, err := pipe(infoCtx, args...)
+
+	if err != nil {
+		return false, err
+	}
+
+	if len(g.DaemonSet) > 0 {
+		var pods apiv1.PodList
+
+		if err = json.Unmarshal([]byte(out), &pods); err != nil {
+			return false, err
+		}
+
+		return g.podsSatisfied(&pods), nil
+	}
+
+	var node apiv1.Node
+
+	if err = json.Unmarshal([]byte(out), &node); err != nil {
+		return false, err
+	}
+
+	return g.nodeSatisfied(&node), nil
+}
+
+// wait poll the gate until it's satisfied or its timeout expires
+func (g *ReadinessGate) wait(ctx context.Context, vm *MultipassNode, extras *nodeCreationExtra) error {
+	gateCtx, cancel := context.WithTimeout(ctx, g.timeout())
+
+	defer cancel()
+
+	for {
+		ok, err := g.satisfied(gateCtx, vm, extras)
+
+		if ok {
+			glog.Infof("The kubernetes node:%s passed the readiness gate: %s", vm.NodeName, g)
+			return nil
+		}
+
+		// The cluster could be unreachable for a while, keep polling until the timeout
+		if err != nil {
+			glog.Warningf("Unable to check the readiness gate: %s of node:%s, reason: %v", g, vm.NodeName, err)
+		}
+
+		select {
+		case <-gateCtx.Done():
+			return newError(kindNotReady, errReadinessGateFailed, vm.NodeName, g)
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// waitReadinessGates wait until the ready node passed all its readiness gates
+func (vm *MultipassNode) waitReadinessGates(ctx context.Context, extras *nodeCreationExtra) error {
+	for _, gate := range extras.readinessGates {
+		if err := gate.wait(ctx, vm, extras); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// failReadinessGates delete the VM of a node failing its readiness gates, the node is kept as a failed instance
+func (g *MultipassNodeGroup) failReadinessGates(ctx context.Context, extras *nodeCreationExtra, node *MultipassNode, reason error) {
+	if e := node.deleteVM(ctx, extras); e != nil {
+		glog.Errorf(errUnableToDeleteVM, node.NodeName, e)
+	}
+
+	node.State = MultipassNodeStateNotCreated
+	node.ErrorInfo = &MultipassNodeErrorInfo{
+		Class:   apigrpc.InstanceErrorClass_ERROR_OTHER,
+		Code:    errorCodeReadinessGates,
+		Message: reason.Error(),
+	}
+
+	g.Nodes[node.NodeName] = node
+}
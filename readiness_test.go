@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apigrpc "github.com/Fred78290/kubernetes-multipass-autoscaler/grpc"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_readinessGate_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		gate    *ReadinessGate
+		wantErr bool
+	}{
+		{name: "DaemonSet", gate: &ReadinessGate{DaemonSet: "kube-system/calico-node", Timeout: 120}},
+		{name: "Condition", gate: &ReadinessGate{Condition: "NetworkUnavailable", Status: "False"}},
+		{name: "Label", gate: &ReadinessGate{Label: "feature.node.kubernetes.io/pci-10de.present", Value: "true"}},
+		{name: "Empty", gate: &ReadinessGate{}, wantErr: true},
+		{name: "Two kinds", gate: &ReadinessGate{DaemonSet: "kube-system/calico-node", Label: "gpu"}, wantErr: true},
+		{name: "DaemonSet without namespace", gate: &ReadinessGate{DaemonSet: "calico-node"}, wantErr: true},
+		{name: "Unknown status", gate: &ReadinessGate{Condition: "NetworkUnavailable", Status: "No"}, wantErr: true},
+		{name: "Negative timeout", gate: &ReadinessGate{Label: "gpu", Timeout: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.gate.validate(testGroupID)
+
+			if tt.wantErr {
+				if assert.Error(t, err) {
+					assert.Equal(t, codes.InvalidArgument, kindOf(err).code)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_readinessGate_nodeSatisfied(t *testing.T) {
+	node := &apiv1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"feature.node.kubernetes.io/cpu-cpuid.AVX2": "true"},
+		},
+		Status: apiv1.NodeStatus{
+			Conditions: []apiv1.NodeCondition{
+				{Type: apiv1.NodeReady, Status: apiv1.ConditionTrue},
+				{Type: apiv1.NodeNetworkUnavailable, Status: apiv1.ConditionFalse},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		gate *ReadinessGate
+		want bool
+	}{
+		{name: "Condition default status", gate: &ReadinessGate{Condition: "Ready"}, want: true},
+		{name: "Condition status", gate: &ReadinessGate{Condition: "NetworkUnavailable", Status: "False"}, want: true},
+		{name: "Condition wrong status", gate: &ReadinessGate{Condition: "NetworkUnavailable"}, want: false},
+		{name: "Missing condition", gate: &ReadinessGate{Condition: "GPUReady"}, want: false},
+		{name: "Label any value", gate: &ReadinessGate{Label: "feature.node.kubernetes.io/cpu-cpuid.AVX2"}, want: true},
+		{name: "Label value", gate: &ReadinessGate{Label: "feature.node.kubernetes.io/cpu-cpuid.AVX2", Value: "false"}, want: false},
+		{name: "Missing label", gate: &ReadinessGate{Label: "feature.node.kubernetes.io/pci-10de.present"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.gate.nodeSatisfied(node))
+		})
+	}
+}
+
+func Test_readinessGate_podsSatisfied(t *testing.T) {
+	newPod := func(owner string, phase apiv1.PodPhase, ready apiv1.ConditionStatus) apiv1.Pod {
+		return apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Name: owner}},
+			},
+			Status: apiv1.PodStatus{
+				Phase:      phase,
+				Conditions: []apiv1.PodCondition{{Type: apiv1.PodReady, Status: ready}},
+			},
+		}
+	}
+
+	gate := &ReadinessGate{DaemonSet: "kube-system/calico-node"}
+
+	tests := []struct {
+		name string
+		pods []apiv1.Pod
+		want bool
+	}{
+		{name: "No pod", want: false},
+		{name: "Ready", pods: []apiv1.Pod{newPod("kube-proxy", apiv1.PodRunning, apiv1.ConditionTrue), newPod("calico-node", apiv1.PodRunning, apiv1.ConditionTrue)}, want: true},
+		{name: "Not ready", pods: []apiv1.Pod{newPod("calico-node", apiv1.PodRunning, apiv1.ConditionFalse)}, want: false},
+		{name: "Pending", pods: []apiv1.Pod{newPod("calico-node", apiv1.PodPending, apiv1.ConditionTrue)}, want: false},
+		{name: "Other DaemonSet", pods: []apiv1.Pod{newPod("kube-proxy", apiv1.PodRunning, apiv1.ConditionTrue)}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, gate.podsSatisfied(&apiv1.PodList{Items: tt.pods}))
+		})
+	}
+}
+
+func Test_readinessGate_timeout(t *testing.T) {
+	assert.Equal(t, defaultReadinessGateTimeout*time.Second, (&ReadinessGate{}).timeout())
+	assert.Equal(t, 30*time.Second, (&ReadinessGate{Timeout: 30}).timeout())
+	assert.Equal(t, "daemonset kube-system/calico-node", (&ReadinessGate{DaemonSet: "kube-system/calico-node"}).String())
+	assert.Equal(t, "condition NetworkUnavailable=False", (&ReadinessGate{Condition: "NetworkUnavailable", Status: "False"}).String())
+}
+
+func Test_multipassNodeGroup_failReadinessGates(t *testing.T) {
+	backend := newFakeBackend()
+	extras := newFakeExtras(backend)
+	ng := newTestNodeGroup("")
+
+	node := &MultipassNode{NodeName: testNodeName, AutoProvisionned: true}
+
+	backend.vms[testNodeName] = &VMStatus{State: MultipassNodeStateRunning}
+
+	assert.NoError(t, node.waitReadinessGates(context.Background(), extras), "no gate")
+
+	ng.failReadinessGates(context.Background(), extras, node, newError(kindNotReady, errReadinessGateFailed, testNodeName, "label gpu"))
+
+	assert.True(t, node.failed())
+	assert.Equal(t, apigrpc.InstanceErrorClass_ERROR_OTHER, node.ErrorInfo.Class)
+	assert.Equal(t, errorCodeReadinessGates, node.ErrorInfo.Code)
+	assert.Equal(t, node, ng.Nodes[testNodeName])
+	assert.Contains(t, backend.calls, "delete")
+	assert.Empty(t, backend.vms)
+}
+
+func Test_multipassNodeGroup_startStoppedNodesGatesFailed(t *testing.T) {
+	// The node is ready but never get the gpu label
+	newFakeKubectl(t, `#!/bin/sh
+case "$*" in
+"get nodes"*)
+	echo '{"status":{"conditions":[{"type":"Ready","status":"True"}]}}'
+	;;
+esac
+`)
+
+	backend := newFakeBackend()
+	extras := newFakeExtras(backend)
+	ng := newTestNodeGroup("")
+	node := &MultipassNode{NodeName: testNodeName, AutoProvisionned: true, State: MultipassNodeStateStopped}
+
+	extras.vmprovision = true
+	extras.readinessGates = []*ReadinessGate{{Label: "gpu", Timeout: 1}}
+
+	backend.vms[testNodeName] = &VMStatus{State: MultipassNodeStateStopped}
+	ng.HibernatedNodes = map[string]*MultipassNode{testNodeName: node}
+
+	assert.Equal(t, 1, ng.startStoppedNodes(context.Background(), 1, ng.HibernatedNodes, extras))
+	assert.Len(t, ng.HibernatedNodes, 0)
+	assert.NotContains(t, backend.vms, testNodeName, "the VM is deleted")
+
+	if assert.Contains(t, ng.Nodes, testNodeName, "reported as a failed instance") {
+		assert.True(t, node.failed())
+		assert.Equal(t, errorCodeReadinessGates, node.ErrorInfo.Code)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"github.com/golang/glog"
+)
+
+const bytesPerMegabyte = 1024 * 1024
+
+// nodeResources returns the cores and memory in bytes used by a node
+func (g *MultipassNodeGroup) nodeResources(node *MultipassNode) (int64, int64) {
+	cores := int64(node.CPU)
+	memory := int64(node.Memory)
+
+	// Discovered nodes don't declare their size, assume the node group machine
+	if g.Machine != nil {
+		if cores == 0 {
+			cores = int64(g.Machine.Vcpu)
+		}
+
+		if memory == 0 {
+			memory = int64(g.Machine.Memory)
+		}
+	}
+
+	return cores, memory * bytesPerMegabyte
+}
+
+// usedResources returns the cores and memory in bytes of all running and pending nodes in the node group,
+// the caller must not hold the node group lock
+func (g *MultipassNodeGroup) usedResources() map[string]int64 {
+	used := map[string]int64{
+		ResourceNameCores:  0,
+		ResourceNameMemory: 0,
+	}
+
+	g.Lock()
+	defer g.Unlock()
+
+	for _, nodes := range []map[string]*MultipassNode{g.Nodes, g.PendingNodes} {
+		for _, node := range nodes {
+			if node.State != MultipassNodeStateDeleted && !node.failed() {
+				cores, memory := g.nodeResources(node)
+
+				used[ResourceNameCores] += cores
+				used[ResourceNameMemory] += memory
+			}
+		}
+	}
+
+	return used
+}
+
+// usedResources returns the cores and memory in bytes of all running and pending nodes across every node group
+func (s *MultipassServer) usedResources() map[string]int64 {
+	used := map[string]int64{
+		ResourceNameCores:  0,
+		ResourceNameMemory: 0,
+	}
+
+	for _, nodeGroup := range s.Groups {
+		for name, value := range nodeGroup.usedResources() {
+			used[name] += value
+		}
+	}
+
+	return used
+}
+
+// checkScaleUp refuse to add delta nodes of machine type if the cluster will exceed the max limits
+func (s *MultipassServer) checkScaleUp(nodeGroup *MultipassNodeGroup, delta int) error {
+	if s.ResourceLimiter == nil || nodeGroup.Machine == nil {
+		return nil
+	}
+
+	used := s.usedResources()
+	requested := map[string]int64{
+		ResourceNameCores:  int64(delta * nodeGroup.Machine.Vcpu),
+		ResourceNameMemory: int64(delta*nodeGroup.Machine.Memory) * bytesPerMegabyte,
+	}
+
+	for name, value := range requested {
+		if max, found := s.ResourceLimiter.MaxLimits[name]; found && used[name]+value > max {
+			glog.Errorf(errResourceLimitExceeded, nodeGroup.NodeGroupIdentifier, name, used[name]+value, max)
+
+			return newError(kindFailedPrecondition, errResourceLimitExceeded, nodeGroup.NodeGroupIdentifier, name, used[name]+value, max)
+		}
+	}
+
+	return nil
+}
+
+// checkScaleDown refuse to delete the nodes if the cluster will go below the min limits
+func (s *MultipassServer) checkScaleDown(nodeGroup *MultipassNodeGroup, nodes []*MultipassNode) error {
+	if s.ResourceLimiter == nil {
+		return nil
+	}
+
+	used := s.usedResources()
+	released := map[string]int64{
+		ResourceNameCores:  0,
+		ResourceNameMemory: 0,
+	}
+
+	for _, node := range nodes {
+		cores, memory := nodeGroup.nodeResources(node)
+
+		released[ResourceNameCores] += cores
+		released[ResourceNameMemory] += memory
+	}
+
+	for name, value := range released {
+		if min, found := s.ResourceLimiter.MinLimits[name]; found && used[name]-value < min {
+			glog.Errorf(errResourceLimitBelowMin, nodeGroup.NodeGroupIdentifier, name, used[name]-value, min)
+
+			return newError(kindFailedPrecondition, errResourceLimitBelowMin, nodeGroup.NodeGroupIdentifier, name, used[name]-value, min)
+		}
+	}
+
+	return nil
+}
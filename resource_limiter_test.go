@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apigrpc "github.com/Fred78290/kubernetes-multipass-autoscaler/grpc"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+func newTestLimitedServer(limits *ResourceLimiter) (*MultipassServer, error) {
+	ng := newTestNodeGroup("large", withNodes(map[string]*MultipassNode{
+		testNodeName: {
+			NodeName:         testNodeName,
+			Memory:           8192,
+			CPU:              4,
+			State:            MultipassNodeStateRunning,
+			AutoProvisionned: true,
+		},
+		"ca-grpc-multipass-vm-01": {
+			NodeName:         "ca-grpc-multipass-vm-01",
+			State:            MultipassNodeStateRunning,
+			AutoProvisionned: false,
+		},
+	}))
+
+	s, _, err := newTestServerWithConfig(ng, MultipassServerConfig{
+		ProviderID: testProviderID,
+		Machines:   testMachines,
+	})
+
+	if err == nil {
+		s.ResourceLimiter = limits
+	}
+
+	return s, err
+}
+
+func TestMultipassServer_usedResources(t *testing.T) {
+	s, err := newTestLimitedServer(nil)
+
+	if assert.NoError(t, err) {
+		used := s.usedResources()
+
+		// The discovered node without size use the node group machine
+		assert.Equal(t, int64(8), used[ResourceNameCores])
+		assert.Equal(t, int64(2*8192*bytesPerMegabyte), used[ResourceNameMemory])
+
+		// A node group growing in background is summed under its lock
+		ng := s.Groups[testGroupID]
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			for index := 2; index < 50; index++ {
+				ng.Lock()
+				ng.PendingNodes[ng.nodeName(index)] = &MultipassNode{NodeName: ng.nodeName(index)}
+				ng.Unlock()
+			}
+		}()
+
+		for index := 0; index < 50; index++ {
+			s.usedResources()
+		}
+
+		<-done
+
+		assert.Equal(t, int64(50*4), s.usedResources()[ResourceNameCores])
+	}
+}
+
+func TestMultipassServer_IncreaseSizeLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  *ResourceLimiter
+		delta   int
+		wantErr bool
+	}{
+		{
+			name: "MaxCoresExceeded",
+			limits: &ResourceLimiter{
+				MinLimits: map[string]int64{},
+				MaxLimits: map[string]int64{ResourceNameCores: 10},
+			},
+			delta:   1,
+			wantErr: true,
+		},
+		{
+			name: "MaxMemoryExceeded",
+			limits: &ResourceLimiter{
+				MinLimits: map[string]int64{},
+				MaxLimits: map[string]int64{ResourceNameMemory: 20000 * bytesPerMegabyte},
+			},
+			delta:   1,
+			wantErr: true,
+		},
+		{
+			name: "InsideLimits",
+			limits: &ResourceLimiter{
+				MinLimits: map[string]int64{},
+				MaxLimits: map[string]int64{ResourceNameCores: 16, ResourceNameMemory: 32768 * bytesPerMegabyte},
+			},
+			delta:   2,
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := newTestLimitedServer(tt.limits)
+
+			if assert.NoError(t, err) {
+				if err := s.checkScaleUp(s.Groups[testGroupID], tt.delta); (err != nil) != tt.wantErr {
+					t.Errorf("MultipassServer.checkScaleUp() error = %v, wantErr %v", err, tt.wantErr)
+				}
+
+				if tt.wantErr {
+					got, err := s.IncreaseSize(context.Background(), &apigrpc.IncreaseSizeRequest{
+						ProviderID:  testProviderID,
+						NodeGroupID: testGroupID,
+						Delta:       int32(tt.delta),
+					})
+
+					if assert.NoError(t, err) && assert.NotNil(t, got.GetError()) {
+						assert.Equal(t, 2, s.Groups[testGroupID].targetSize())
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMultipassServer_DeleteNodesLimits(t *testing.T) {
+	s, err := newTestLimitedServer(&ResourceLimiter{
+		MinLimits: map[string]int64{ResourceNameCores: 6},
+		MaxLimits: map[string]int64{},
+	})
+
+	if assert.NoError(t, err) {
+		got, err := s.DeleteNodes(context.Background(), &apigrpc.DeleteNodesRequest{
+			ProviderID:  testProviderID,
+			NodeGroupID: testGroupID,
+			Node: []string{
+				toJSON(apiv1.Node{
+					Spec: apiv1.NodeSpec{
+						ProviderID: fmt.Sprintf("%s://%s/object?type=node&name=%s", testProviderID, testGroupID, testNodeName),
+					},
+				}),
+			},
+		})
+
+		if assert.NoError(t, err) && assert.NotNil(t, got.GetError()) {
+			assert.Equal(t, 2, len(s.Groups[testGroupID].Nodes))
+		}
+	}
+}
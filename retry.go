@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const (
+	defaultRetryMaxAttempts  = 5
+	defaultRetryInitialDelay = 500
+	defaultRetryMaxDelay     = 10000
+)
+
+// retryablePatterns are the multipass and kubectl stderr messages known to be transient, the request was not executed
+var retryablePatterns = []string{
+	"instance is busy",
+	"cannot connect to the multipass socket",
+	"failed to connect to the multipass daemon",
+	"connection refused",
+	"tls handshake timeout",
+	"unable to connect to the server",
+	"the object has been modified",
+	"too many requests",
+	"service unavailable",
+}
+
+// ambiguousPatterns are transient failures where the request could have been executed,
+// only idempotent commands are retried
+var ambiguousPatterns = []string{
+	"socket timed out",
+	"deadline exceeded",
+	"i/o timeout",
+	"connection reset by peer",
+	"etcdserver: request timed out",
+}
+
+// RetryPolicy declare how transient command failures are retried
+type RetryPolicy struct {
+	MaxAttempts  int `json:"max-attempts"`  // Number of attempts including the first one, 1 disable retry
+	InitialDelay int `json:"initial-delay"` // Delay in milliseconds before the first retry, doubled at each attempt
+	MaxDelay     int `json:"max-delay"`     // Max delay in milliseconds between two attempts
+}
+
+// commandRetryPolicy is the policy used by pipe and shell, nil use the default policy
+var commandRetryPolicy *RetryPolicy
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts <= 0 {
+		return defaultRetryMaxAttempts
+	}
+
+	return p.MaxAttempts
+}
+
+// backoff returns the jittered delay to wait before the attempt, attempt start at 1 for the first retry
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	initialDelay := defaultRetryInitialDelay
+	maxDelay := defaultRetryMaxDelay
+
+	if p != nil && p.InitialDelay > 0 {
+		initialDelay = p.InitialDelay
+	}
+
+	if p != nil && p.MaxDelay > 0 {
+		maxDelay = p.MaxDelay
+	}
+
+	delay := time.Duration(initialDelay) * time.Millisecond
+
+	for index := 1; index < attempt && delay < time.Duration(maxDelay)*time.Millisecond; index++ {
+		delay *= 2
+	}
+
+	if delay > time.Duration(maxDelay)*time.Millisecond {
+		delay = time.Duration(maxDelay) * time.Millisecond
+	}
+
+	// Wait between half and the full delay, so concurrent launches don't retry together
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// idempotent tells if the command could run twice, a second multipass launch or LXD POST create twice
+func idempotent(args []string) bool {
+	if len(args) > 0 && args[0] == http.MethodPost {
+		return false
+	}
+
+	for index := 0; index < len(args)-1; index++ {
+		if args[index] == multipassCommandLine && args[index+1] == launchArgument {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isRetryable tells if the command output match a transient failure
+func isRetryable(stderr string, idempotent bool) bool {
+	stderr = strings.ToLower(stderr)
+
+	patterns := retryablePatterns
+
+	if idempotent {
+		patterns = append(append([]string{}, retryablePatterns...), ambiguousPatterns...)
+	}
+
+	for _, pattern := range patterns {
+		if strings.Contains(stderr, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// retry call run until it succeed, fail with a non transient error, the budget is exhausted or the context is done.
+// run returns the stderr used to classify the failure
+func (p *RetryPolicy) retry(ctx context.Context, args []string, run func() (string, error)) error {
+	var history []string
+
+	maxAttempts := p.maxAttempts()
+	retryAmbiguous := idempotent(args)
+
+	for attempt := 1; ; attempt++ {
+		stderr, err := run()
+
+		if err == nil {
+			if attempt > 1 {
+				glog.Infof("Command: %s succeeded after %d attempts", args[0], attempt)
+			}
+
+			return nil
+		}
+
+		if ctx.Err() != nil || !isRetryable(stderr, retryAmbiguous) {
+			if len(history) == 0 {
+				return err
+			}
+
+			history = append(history, fmt.Sprintf("attempt %d: %v", attempt, err))
+
+			return wrapError(nil, err, errCommandRetryFailed, strings.Join(args, " "), attempt, strings.Join(history, "; "))
+		}
+
+		history = append(history, fmt.Sprintf("attempt %d: %v", attempt, err))
+
+		if attempt >= maxAttempts {
+			return wrapError(kindTransient, err, errCommandRetryFailed, strings.Join(args, " "), attempt, strings.Join(history, "; "))
+		}
+
+		delay := p.backoff(attempt)
+
+		glog.Warningf("Command: %s failed with transient error, retry in %v, reason: %v", args[0], delay, err)
+
+		select {
+		case <-ctx.Done():
+			history = append(history, fmt.Sprintf("attempt %d: %v", attempt+1, ctx.Err()))
+
+			return wrapError(kindTimeout, ctx.Err(), errCommandRetryFailed, strings.Join(args, " "), attempt, strings.Join(history, "; "))
+		case <-time.After(delay):
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testRetryPolicy = &RetryPolicy{
+	MaxAttempts:  3,
+	InitialDelay: 1,
+	MaxDelay:     4,
+}
+
+func Test_isRetryable(t *testing.T) {
+	tests := []struct {
+		stderr     string
+		idempotent bool
+		want       bool
+	}{
+		{"launch failed: instance is busy", false, true},
+		{"cannot connect to the multipass socket", true, true},
+		{"Unable to connect to the server: net/http: TLS handshake timeout", true, true},
+		{"Operation cannot be fulfilled on nodes \"vm-01\": the object has been modified; please apply your changes", true, true},
+		{"read: connection reset by peer", true, true},
+		{"read: connection reset by peer", false, false},
+		{"socket timed out", false, false},
+		{"instance \"vm-01\" already exists", true, false},
+		{"Error from server (NotFound): nodes \"vm-01\" not found", true, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, isRetryable(tt.stderr, tt.idempotent), tt.stderr)
+	}
+}
+
+func Test_idempotent(t *testing.T) {
+	assert.False(t, idempotent([]string{multipassCommandLine, launchArgument, "--name", "vm-01"}))
+	assert.False(t, idempotent([]string{sshCommandLine, "-p", "22", "ubuntu@host", multipassCommandLine, launchArgument}))
+	assert.False(t, idempotent([]string{http.MethodPost, "/1.0/instances"}))
+	assert.True(t, idempotent([]string{multipassCommandLine, "info", "vm-01"}))
+	assert.True(t, idempotent([]string{http.MethodDelete, "/1.0/instances/vm-01"}))
+	assert.True(t, idempotent([]string{kubectlCommandLine, "get", "nodes"}))
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := &RetryPolicy{
+		InitialDelay: 100,
+		MaxDelay:     1000,
+	}
+
+	for attempt, max := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		delay := policy.backoff(attempt + 1)
+
+		assert.True(t, delay >= max*time.Millisecond/2 && delay <= max*time.Millisecond, "attempt %d: %v", attempt+1, delay)
+	}
+}
+
+func TestRetryPolicy_retry(t *testing.T) {
+	args := []string{multipassCommandLine, launchArgument}
+
+	t.Run("TransientThenSuccess", func(t *testing.T) {
+		attempts := 0
+
+		err := testRetryPolicy.retry(context.Background(), args, func() (string, error) {
+			if attempts++; attempts < 3 {
+				return "instance is busy", errors.New("exit status 2")
+			}
+
+			return "", nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("NotRetryable", func(t *testing.T) {
+		attempts := 0
+		cause := errors.New("exit status 1, instance already exists")
+
+		err := testRetryPolicy.retry(context.Background(), args, func() (string, error) {
+			attempts++
+
+			return "instance already exists", cause
+		})
+
+		assert.Equal(t, cause, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("LaunchNotRetriedOnTimeout", func(t *testing.T) {
+		attempts := 0
+
+		err := testRetryPolicy.retry(context.Background(), args, func() (string, error) {
+			attempts++
+
+			return "socket timed out", errors.New("exit status 2")
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("BudgetExhausted", func(t *testing.T) {
+		attempts := 0
+
+		err := testRetryPolicy.retry(context.Background(), args, func() (string, error) {
+			attempts++
+
+			return "instance is busy", errors.New("exit status 2")
+		})
+
+		if assert.Error(t, err) {
+			assert.Equal(t, 3, attempts)
+			assert.True(t, strings.HasPrefix(err.Error(), "Command: multipass launch failed after 3 attempts"), err.Error())
+			assert.Contains(t, err.Error(), "attempt 1: exit status 2; attempt 2: exit status 2; attempt 3: exit status 2")
+		}
+	})
+
+	t.Run("ContextCanceled", func(t *testing.T) {
+		attempts := 0
+		ctx, cancel := context.WithCancel(context.Background())
+
+		policy := &RetryPolicy{
+			MaxAttempts:  10,
+			InitialDelay: 60000,
+			MaxDelay:     60000,
+		}
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+		}()
+
+		err := policy.retry(ctx, args, func() (string, error) {
+			attempts++
+
+			return "instance is busy", errors.New("exit status 2")
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+}
@@ -5,9 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
-	"github.com/Fred78290/kubernetes-multipass-autoscaler/constantes"
 	apigrpc "github.com/Fred78290/kubernetes-multipass-autoscaler/grpc"
 	"github.com/golang/glog"
 	apiv1 "k8s.io/api/core/v1"
@@ -28,7 +28,7 @@ const (
 	annotationScaleDownDisabled    = "cluster-autoscaler.kubernetes.io/scale-down-disabled"
 )
 
-// ResourceLimiter define limit, not really used
+// ResourceLimiter define the min and max cores and memory in bytes allowed for the whole cluster
 type ResourceLimiter struct {
 	MinLimits map[string]int64 `json:"min"`
 	MaxLimits map[string]int64 `json:"max"`
@@ -36,27 +36,25 @@ type ResourceLimiter struct {
 
 // MachineCharacteristic defines VM kind
 type MachineCharacteristic struct {
-	Memory int `json:"memsize"`  // VM Memory size in megabytes
-	Vcpu   int `json:"vcpus"`    // VM number of cpus
-	Disk   int `json:"disksize"` // VM disk size in megabytes
+	Memory       int                    `json:"memsize"`                   // VM Memory size in megabytes
+	Vcpu         int                    `json:"vcpus"`                     // VM number of cpus
+	Disk         int                    `json:"disksize"`                  // VM disk size in megabytes
+	Architecture string                 `json:"arch,omitempty"`            // Optional, VM architecture, default to host architecture
+	Price        float64                `json:"price,omitempty"`           // Optional, VM price per hour
+	Image        string                 `json:"image,omitempty"`           // Optional, override the image used to launch VM
+	Resources    map[string]int64       `json:"extra-resources,omitempty"` // Optional, extra scalar resources exposed by the VM
+	Labels       map[string]string      `json:"labels,omitempty"`          // Optional, labels added to the kubernetes node
+	CloudInit    map[string]interface{} `json:"cloud-init,omitempty"`      // Optional, cloud-init merged over the global one
+	Description  string                 `json:"description,omitempty"`     // Optional, human readable description
 }
 
 // KubeJoinConfig give element to join kube master
 type KubeJoinConfig struct {
-	Address        string   `json:"address,omitempty"`
-	Token          string   `json:"token,omitempty"`
-	CACert         string   `json:"ca,omitempty"`
-	ExtraArguments []string `json:"extras-args,omitempty"`
-}
-
-// MultipassServerOptionals declare wich features must be optional
-type MultipassServerOptionals struct {
-	Pricing                  bool `json:"pricing"`
-	GetAvailableMachineTypes bool `json:"getAvailableMachineTypes"`
-	NewNodeGroup             bool `json:"newNodeGroup"`
-	TemplateNodeInfo         bool `json:"templateNodeInfo"`
-	Create                   bool `json:"create"`
-	Delete                   bool `json:"delete"`
+	Address        string         `json:"address,omitempty"`
+	Token          string         `json:"token,omitempty"`
+	CACert         string         `json:"ca,omitempty"`
+	ExtraArguments []string       `json:"extras-args,omitempty"`
+	Rotation       *TokenRotation `json:"token-rotation,omitempty"` // Optional, the provider create and renew the token and the CA hash
 }
 
 // MultipassServerConfig is contains configuration
@@ -66,28 +64,58 @@ type MultipassServerConfig struct {
 	ProviderID         string                            `json:"secret"`                        // Mandatory, secret Identifier, client must match this
 	MinNode            int                               `json:"minNode"`                       // Mandatory, Min Multipass VM
 	MaxNode            int                               `json:"maxNode"`                       // Mandatory, Max Multipass VM
-	NodePrice          float64                           `json:"nodePrice"`                     // Optional, The VM price
-	PodPrice           float64                           `json:"podPrice"`                      // Optional, The pod price
+	NodePrice          float64                           `json:"nodePrice"`                     // Optional, The VM price per hour when the machine type has no price
+	PodPrice           float64                           `json:"podPrice"`                      // Optional, The pod price per hour when no pricing model is defined
+	PricingModel       *PricingModel                     `json:"pricing-model"`                 // Optional, unit costs used to compute node and pod prices
 	Image              string                            `json:"image"`                         // Optional, URL to multipass image or image name
 	KubeCtlConfig      string                            `default:"/etc/kubernetes/config" json:"kubeconfig"`
 	KubeAdm            KubeJoinConfig                    `json:"kubeadm"`
 	DefaultMachineType string                            `default:"{\"standard\": {}}" json:"default-machine"`
 	Machines           map[string]*MachineCharacteristic `default:"{\"standard\": {}}" json:"machines"` // Mandatory, Available machines
 	CloudInit          map[string]interface{}            `json:"cloud-init"`                            // Optional, The cloud init conf file
+	CloudInitTemplate  string                            `json:"cloud-init-template"`                   // Optional, file of the cloud-init go template, exclusive with cloud-init
+	CloudInitVariables map[string]string                 `json:"cloud-init-variables"`                  // Optional, variables given to the cloud-init templates
+	CloudInitScripts   []*CloudInitScript                `json:"cloud-init-scripts"`                    // Optional, shell scripts added to a multipart user data
+	NodeGroupCloudInit map[string]map[string]interface{} `json:"node-group-cloud-init"`                 // Optional, per node group, cloud-init merged over the machine type one
 	MountPoints        map[string]string                 `json:"mount-points"`                          // Optional, mount point between host and guest
 	VMProvision        bool                              `default:"true" json:"vm-provision"`
-	Optionals          *MultipassServerOptionals         `json:"optionals"`
+	Optionals          *MultipassServerOptionals         `json:"optionals"`        // Deprecated, use capabilities
+	Capabilities       *MultipassServerCapabilities      `json:"capabilities"`     // Optional, enable or disable optional RPC
+	Limits             *ResourceLimiter                  `json:"limits"`           // Optional, cluster limits until the autoscaler connect
+	StoragePath        string                            `json:"storage-path"`     // Optional, multipass storage path used to compute free disk
+	HostReservation    *HostReservation                  `json:"host-reservation"` // Optional, host resources never allocated to VMs
+	Timeouts           *MultipassServerTimeouts          `json:"timeouts"`         // Optional, per operation timeouts in seconds
+	Retry              *RetryPolicy                      `json:"retry"`            // Optional, retry policy for transient command failures
+	Backend            string                            `json:"backend"`          // Optional, hypervisor driving the VMs, multipass by default
+	LXD                *LXDConfig                        `json:"lxd"`              // Optional, LXD backend configuration
+	Libvirt            *LibvirtConfig                    `json:"libvirt"`          // Optional, libvirt backend configuration
+	VSphere            *VSphereConfig                    `json:"vsphere"`          // Optional, vSphere backend configuration
+	Multipassd         *MultipassdConfig                 `json:"multipassd"`       // Optional, multipassd backend configuration
+	Hosts              []*HypervisorHost                 `json:"hosts"`            // Optional, hypervisor hosts sharing the node groups, the local backend by default
+	Placement          *HostPlacement                    `json:"placement"`        // Optional, how new nodes are placed on the hosts
+	WarmPool           map[string]int                    `json:"warm-pool"`        // Optional, per node group, number of stopped VMs kept ready to start
+	ScaleDown          map[string]*ScaleDownPolicy       `json:"scale-down"`       // Optional, per node group, delete or hibernate the removed VMs
+	Bootstrap          *BootstrapConfig                  `json:"bootstrap"`        // Optional, how the new VMs join the cluster
+	ControlPlane       *ControlPlaneConfig               `json:"control-plane"`    // Optional, node groups joining as control plane members
+	Kubelet            map[string]*KubeletConfig         `json:"kubelet"`          // Optional, per node group, kubelet configuration
+	ReadinessGates     map[string][]*ReadinessGate       `json:"readiness-gates"`  // Optional, per node group, checked once the node is ready
 }
 
 // MultipassServer declare multipass grpc server
 type MultipassServer struct {
-	ResourceLimiter      *ResourceLimiter               `json:"limits"`
-	Groups               map[string]*MultipassNodeGroup `json:"groups"`
-	Configuration        MultipassServerConfig          `json:"config"`
-	KubeAdmConfiguration *apigrpc.KubeAdmConfig         `json:"kubeadm"`
-	NodesDefinition      []*apigrpc.NodeGroupDef        `json:"nodedefs"`
-	AutoProvision        bool                           `json:"auto"`
-	CacheDir             string                         `json:"cache"`
+	ResourceLimiter          *ResourceLimiter               `json:"limits"`
+	Groups                   map[string]*MultipassNodeGroup `json:"groups"`
+	Configuration            MultipassServerConfig          `json:"config"`
+	KubeAdmConfiguration     *apigrpc.KubeAdmConfig         `json:"kubeadm"`
+	NodesDefinition          []*apigrpc.NodeGroupDef        `json:"nodedefs"`
+	AutoProvision            bool                           `json:"auto"`
+	CacheDir                 string                         `json:"cache"`
+	Backend                  VMBackend                      `json:"-"`
+	Hosts                    *hypervisorHosts               `json:"-"`
+	KubeAdmTokenExpiration   *time.Time                     `json:"kubeadm-token-expiration,omitempty"`
+	CertificateKey           string                         `json:"certificate-key,omitempty"`
+	CertificateKeyExpiration *time.Time                     `json:"certificate-key-expiration,omitempty"`
+	tokenLock                sync.Mutex
 }
 
 func (s *MultipassServer) generateNodeGroupName() string {
@@ -104,18 +132,97 @@ type newNodeGroupArgument struct {
 	autoProvision bool
 }
 
+func (s *MultipassServer) newNodeCreationExtra(nodeGroup *MultipassNodeGroup) *nodeCreationExtra {
+	var machineCloudInit map[string]interface{}
+	var arch string
+
+	systemLabels := make(map[string]string)
+	image := s.Configuration.Image
+
+	if nodeGroup.Machine != nil {
+		if len(nodeGroup.Machine.Image) > 0 {
+			image = nodeGroup.Machine.Image
+		}
+
+		arch = nodeGroup.Machine.architecture()
+
+		machineCloudInit = nodeGroup.Machine.CloudInit
+
+		for k, v := range nodeGroup.Machine.nodeLabels(nodeGroup.MachineType) {
+			systemLabels[k] = v
+		}
+	}
+
+	for k, v := range nodeGroup.SystemLabels {
+		systemLabels[k] = v
+	}
+
+	s.tokenLock.Lock()
+	defer s.tokenLock.Unlock()
+
+	return &nodeCreationExtra{
+		kubeHost:           s.KubeAdmConfiguration.KubeAdmAddress,
+		kubeToken:          s.KubeAdmConfiguration.KubeAdmToken,
+		kubeCACert:         s.KubeAdmConfiguration.KubeAdmCACert,
+		kubeExtraArgs:      s.KubeAdmConfiguration.KubeAdmExtraArguments,
+		kubeConfig:         s.Configuration.KubeCtlConfig,
+		image:              image,
+		arch:               arch,
+		cloudInit:          s.Configuration.CloudInit,
+		cloudInitTemplate:  s.Configuration.CloudInitTemplate,
+		cloudInitVariables: s.Configuration.CloudInitVariables,
+		cloudInitScripts:   s.Configuration.CloudInitScripts,
+		machineCloudInit:   machineCloudInit,
+		nodeGroupCloudInit: s.Configuration.NodeGroupCloudInit[nodeGroup.NodeGroupIdentifier],
+		machineType:        nodeGroup.MachineType,
+		mountPoints:        s.Configuration.MountPoints,
+		nodegroupID:        nodeGroup.NodeGroupIdentifier,
+		nodeLabels:         nodeGroup.NodeLabels,
+		systemLabels:       systemLabels,
+		vmprovision:        s.Configuration.VMProvision,
+		cacheDir:           s.CacheDir,
+		timeouts:           s.Configuration.Timeouts,
+		backend:            s.vmBackend(),
+		hosts:              s.hypervisorHosts(),
+		placement:          s.newHostPlacement(nodeGroup),
+		bootstrap:          s.Configuration.Bootstrap,
+		bootstrapper:       s.Configuration.Bootstrap.newBootstrapper(nodeGroup.NodeGroupIdentifier),
+		controlPlane:       nodeGroup.ControlPlane,
+		certificateKey:     s.CertificateKey,
+		kubelet:            nodeGroup.Kubelet,
+		readinessGates:     s.Configuration.ReadinessGates[nodeGroup.NodeGroupIdentifier],
+	}
+}
+
+// machineForNode returns the machine type of a node, first from its instance-type label then from its node group
+func (s *MultipassServer) machineForNode(node *apiv1.Node) (string, *MachineCharacteristic) {
+	if machineType := node.Labels[nodeLabelInstanceType]; len(machineType) > 0 {
+		if machine := s.Configuration.Machines[machineType]; machine != nil {
+			return machineType, machine
+		}
+	}
+
+	if providerID := getNodeProviderID(s.Configuration.ProviderID, node); len(providerID) > 0 {
+		if nodeGroup, err := s.nodeGroupForNode(providerID); err == nil && nodeGroup != nil {
+			return nodeGroup.MachineType, nodeGroup.Machine
+		}
+	}
+
+	return "", nil
+}
+
 func (s *MultipassServer) newNodeGroup(arg newNodeGroupArgument) (*MultipassNodeGroup, error) {
 
 	machine := s.Configuration.Machines[arg.machineType]
 
 	if machine == nil {
-		return nil, fmt.Errorf(errMachineTypeNotFound, arg.machineType)
+		return nil, newError(kindNotFound, errMachineTypeNotFound, arg.machineType)
 	}
 
 	if nodeGroup := s.Groups[arg.nodeGroupID]; nodeGroup != nil {
 		glog.Errorf(errNodeGroupAlreadyExists, arg.nodeGroupID)
 
-		return nil, fmt.Errorf(errNodeGroupAlreadyExists, arg.nodeGroupID)
+		return nil, newError(kindAlreadyExists, errNodeGroupAlreadyExists, arg.nodeGroupID)
 	}
 
 	glog.Infof("New node group, ID:%s minSize:%d, maxSize:%d, machineType:%s, node lables:%v, %v", arg.nodeGroupID, arg.minNodeSize, arg.maxNodeSize, arg.machineType, arg.labels, arg.systemLabels)
@@ -123,10 +230,17 @@ func (s *MultipassServer) newNodeGroup(arg newNodeGroupArgument) (*MultipassNode
 	nodeGroup := &MultipassNodeGroup{
 		ServiceIdentifier:   s.Configuration.ProviderID,
 		NodeGroupIdentifier: arg.nodeGroupID,
+		MachineType:         arg.machineType,
 		Machine:             machine,
 		Status:              NodegroupNotCreated,
 		PendingNodes:        make(map[string]*MultipassNode),
 		Nodes:               make(map[string]*MultipassNode),
+		WarmPoolSize:        s.Configuration.WarmPool[arg.nodeGroupID],
+		WarmNodes:           make(map[string]*MultipassNode),
+		ScaleDown:           s.Configuration.ScaleDown[arg.nodeGroupID],
+		HibernatedNodes:     make(map[string]*MultipassNode),
+		ControlPlane:        s.Configuration.ControlPlane.controlPlane(arg.nodeGroupID),
+		Kubelet:             s.Configuration.Kubelet[arg.nodeGroupID],
 		MinNodeSize:         int(arg.minNodeSize),
 		MaxNodeSize:         int(arg.maxNodeSize),
 		NodeLabels:          arg.labels,
@@ -139,17 +253,17 @@ func (s *MultipassServer) newNodeGroup(arg newNodeGroupArgument) (*MultipassNode
 	return nodeGroup, nil
 }
 
-func (s *MultipassServer) deleteNodeGroup(nodeGroupID string) error {
+func (s *MultipassServer) deleteNodeGroup(ctx context.Context, nodeGroupID string) error {
 	nodeGroup := s.Groups[nodeGroupID]
 
 	if nodeGroup == nil {
 		glog.Errorf(errNodeGroupNotFound, nodeGroupID)
-		return fmt.Errorf(errNodeGroupNotFound, nodeGroupID)
+		return newError(kindNotFound, errNodeGroupNotFound, nodeGroupID)
 	}
 
 	glog.Infof("Delete node group, ID:%s", nodeGroupID)
 
-	if err := nodeGroup.deleteNodeGroup(s.Configuration.KubeCtlConfig); err != nil {
+	if err := nodeGroup.deleteNodeGroup(ctx, s.newNodeCreationExtra(nodeGroup)); err != nil {
 		glog.Errorf(errUnableToDeleteNodeGroup, nodeGroupID, err)
 		return err
 	}
@@ -159,12 +273,12 @@ func (s *MultipassServer) deleteNodeGroup(nodeGroupID string) error {
 	return nil
 }
 
-func (s *MultipassServer) createNodeGroup(nodeGroupID string) (*MultipassNodeGroup, error) {
+func (s *MultipassServer) createNodeGroup(ctx context.Context, nodeGroupID string) (*MultipassNodeGroup, error) {
 	nodeGroup := s.Groups[nodeGroupID]
 
 	if nodeGroup == nil {
 		glog.Errorf(errNodeGroupNotFound, nodeGroupID)
-		return nil, fmt.Errorf(errNodeGroupNotFound, nodeGroupID)
+		return nil, newError(kindNotFound, errNodeGroupNotFound, nodeGroupID)
 	}
 
 	if nodeGroup.Status == NodegroupNotCreated {
@@ -173,23 +287,15 @@ func (s *MultipassServer) createNodeGroup(nodeGroupID string) (*MultipassNodeGro
 
 			glog.Infof("Create node group, ID:%s", nodeGroupID)
 
-			extras := &nodeCreationExtra{
-				kubeHost:      s.KubeAdmConfiguration.KubeAdmAddress,
-				kubeToken:     s.KubeAdmConfiguration.KubeAdmToken,
-				kubeCACert:    s.KubeAdmConfiguration.KubeAdmCACert,
-				kubeExtraArgs: s.KubeAdmConfiguration.KubeAdmExtraArguments,
-				kubeConfig:    s.Configuration.KubeCtlConfig,
-				image:         s.Configuration.Image,
-				cloudInit:     s.Configuration.CloudInit,
-				mountPoints:   s.Configuration.MountPoints,
-				nodegroupID:   nodeGroupID,
-				nodeLabels:    nodeGroup.NodeLabels,
-				systemLabels:  nodeGroup.SystemLabels,
-				vmprovision:   s.Configuration.VMProvision,
-				cacheDir:      s.CacheDir,
+			if err := s.checkScaleUp(nodeGroup, nodeGroup.MinNodeSize); err != nil {
+				return nil, err
 			}
 
-			if err := nodeGroup.addNodes(nodeGroup.MinNodeSize, extras); err != nil {
+			s.refreshJoinParameters(ctx)
+
+			extras := s.newNodeCreationExtra(nodeGroup)
+
+			if err := nodeGroup.addNodes(ctx, nodeGroup.MinNodeSize, extras); err != nil {
 				glog.Errorf(err.Error())
 
 				return nil, err
@@ -197,12 +303,14 @@ func (s *MultipassServer) createNodeGroup(nodeGroupID string) (*MultipassNodeGro
 		}
 
 		nodeGroup.Status = NodegroupCreated
+
+		s.refillWarmPool(nodeGroup)
 	}
 
 	return nodeGroup, nil
 }
 
-func (s *MultipassServer) doAutoProvision() error {
+func (s *MultipassServer) doAutoProvision(ctx context.Context) error {
 	glog.V(5).Info("Call server doAutoProvision")
 
 	var ng *MultipassNodeGroup
@@ -236,8 +344,8 @@ func (s *MultipassServer) doAutoProvision() error {
 				}
 
 				if ng, err = s.newNodeGroup(arg); err == nil {
-					if ng, err = s.createNodeGroup(nodeGroupIdentifier); err == nil && node.GetIncludeExistingNode() {
-						if err = ng.autoDiscoveryNodes(true, s.Configuration.KubeCtlConfig); err == nil {
+					if ng, err = s.createNodeGroup(ctx, nodeGroupIdentifier); err == nil && node.GetIncludeExistingNode() {
+						if err = ng.autoDiscoveryNodes(ctx, true, s.newNodeCreationExtra(ng)); err == nil {
 							return err
 						}
 					}
@@ -249,7 +357,7 @@ func (s *MultipassServer) doAutoProvision() error {
 			} else {
 				// If the nodegroup already exists, reparse nodes
 				if node.GetIncludeExistingNode() {
-					if err = ng.autoDiscoveryNodes(true, s.Configuration.KubeCtlConfig); err == nil {
+					if err = ng.autoDiscoveryNodes(ctx, true, s.newNodeCreationExtra(ng)); err == nil {
 						return err
 					}
 				}
@@ -266,7 +374,7 @@ func (s *MultipassServer) Connect(ctx context.Context, request *apigrpc.ConnectR
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	if request.GetResourceLimiter() != nil {
@@ -279,15 +387,25 @@ func (s *MultipassServer) Connect(ctx context.Context, request *apigrpc.ConnectR
 	s.NodesDefinition = request.GetNodes()
 	s.AutoProvision = request.GetAutoProvisionned()
 
-	if request.GetKubeAdmConfiguration() != nil {
-		s.KubeAdmConfiguration = request.GetKubeAdmConfiguration()
+	if kubeAdmConfig := request.GetKubeAdmConfiguration(); kubeAdmConfig != nil {
+		s.tokenLock.Lock()
+
+		// Keep the token and the CA hash rotated by the provider
+		if s.KubeAdmTokenExpiration != nil && s.Configuration.KubeAdm.Rotation != nil {
+			kubeAdmConfig.KubeAdmToken = s.KubeAdmConfiguration.KubeAdmToken
+			kubeAdmConfig.KubeAdmCACert = s.KubeAdmConfiguration.KubeAdmCACert
+		}
+
+		s.KubeAdmConfiguration = kubeAdmConfig
+
+		s.tokenLock.Unlock()
 	}
 
 	if s.AutoProvision {
-		if err := s.doAutoProvision(); err != nil {
+		if err := s.doAutoProvision(ctx); err != nil {
 			glog.Errorf(errUnableToAutoProvisionNodeGroup, err)
 
-			return nil, err
+			return nil, toStatusError(err)
 		}
 	}
 
@@ -304,7 +422,7 @@ func (s *MultipassServer) Name(ctx context.Context, request *apigrpc.CloudProvid
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	return &apigrpc.NameReply{
@@ -318,7 +436,7 @@ func (s *MultipassServer) NodeGroups(ctx context.Context, request *apigrpc.Cloud
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	nodeGroups := make([]*apigrpc.NodeGroup, 0, len(s.Groups))
@@ -343,13 +461,13 @@ func (s *MultipassServer) nodeGroupForNode(providerID string) (*MultipassNodeGro
 	if err != nil {
 		glog.Errorf(errCantDecodeNodeIDWithReason, providerID, err)
 
-		return nil, fmt.Errorf(errCantDecodeNodeIDWithReason, providerID, err)
+		return nil, wrapError(kindInvalidArgument, err, errCantDecodeNodeIDWithReason, providerID, err)
 	}
 
 	if len(nodeGroupID) == 0 {
 		glog.Errorf(errCantDecodeNodeID, providerID)
 
-		return nil, fmt.Errorf(errCantDecodeNodeID, providerID)
+		return nil, newError(kindInvalidArgument, errCantDecodeNodeID, providerID)
 	}
 
 	nodeGroup := s.Groups[nodeGroupID]
@@ -371,7 +489,7 @@ func (s *MultipassServer) NodeGroupForNode(ctx context.Context, request *apigrpc
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	node, err := nodeFromJSON(request.GetNode())
@@ -381,10 +499,7 @@ func (s *MultipassServer) NodeGroupForNode(ctx context.Context, request *apigrpc
 
 		return &apigrpc.NodeGroupForNodeReply{
 			Response: &apigrpc.NodeGroupForNodeReply_Error{
-				Error: &apigrpc.Error{
-					Code:   cloudProviderError,
-					Reason: err.Error(),
-				},
+				Error: toAPIError(wrapError(kindInvalidArgument, err, errCantUnmarshallNodeWithReason, request.GetNode(), err)),
 			},
 		}, nil
 	}
@@ -405,14 +520,17 @@ func (s *MultipassServer) NodeGroupForNode(ctx context.Context, request *apigrpc
 	if err != nil {
 		return &apigrpc.NodeGroupForNodeReply{
 			Response: &apigrpc.NodeGroupForNodeReply_Error{
-				Error: &apigrpc.Error{
-					Code:   cloudProviderError,
-					Reason: err.Error(),
-				},
+				Error: toAPIError(err),
 			},
 		}, nil
 	}
 
+	if nodeName, _ := nodeNameFromProviderID(s.Configuration.ProviderID, providerID); nodeGroup != nil && nodeGroup.standbyNode(nodeName) {
+		glog.V(5).Infof("Node:%s is a stopped VM of nodegroup:%s", nodeName, nodeGroup.NodeGroupIdentifier)
+
+		nodeGroup = nil
+	}
+
 	if nodeGroup == nil {
 		glog.Infof("Nodegroup not found for node.Spec.ProviderID:%s", providerID)
 
@@ -437,13 +555,13 @@ func (s *MultipassServer) NodeGroupForNode(ctx context.Context, request *apigrpc
 func (s *MultipassServer) Pricing(ctx context.Context, request *apigrpc.CloudProviderServiceRequest) (*apigrpc.PricingModelReply, error) {
 	glog.V(5).Infof("Call server Pricing: %v", request)
 
-	if s.Configuration.Optionals.Pricing {
-		return nil, fmt.Errorf(errNotImplemented)
+	if err := s.checkCapability(capabilityPricing); err != nil {
+		return nil, err
 	}
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	return &apigrpc.PricingModelReply{
@@ -460,19 +578,19 @@ func (s *MultipassServer) Pricing(ctx context.Context, request *apigrpc.CloudPro
 func (s *MultipassServer) GetAvailableMachineTypes(ctx context.Context, request *apigrpc.CloudProviderServiceRequest) (*apigrpc.AvailableMachineTypesReply, error) {
 	glog.V(5).Infof("Call server GetAvailableMachineTypes: %v", request)
 
-	if s.Configuration.Optionals.GetAvailableMachineTypes {
-		return nil, fmt.Errorf(errNotImplemented)
+	if err := s.checkCapability(capabilityGetAvailableMachineTypes); err != nil {
+		return nil, err
 	}
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
-	machineTypes := make([]string, 0, len(s.Configuration.Machines))
+	machineTypes := sortedMachineTypes(s.Configuration.Machines)
 
-	for n := range s.Configuration.Machines {
-		machineTypes = append(machineTypes, n)
+	for _, machineType := range machineTypes {
+		glog.V(5).Infof("Available machine type:%s, %v", machineType, s.Configuration.Machines[machineType])
 	}
 
 	return &apigrpc.AvailableMachineTypesReply{
@@ -490,13 +608,13 @@ func (s *MultipassServer) GetAvailableMachineTypes(ctx context.Context, request
 func (s *MultipassServer) NewNodeGroup(ctx context.Context, request *apigrpc.NewNodeGroupRequest) (*apigrpc.NewNodeGroupReply, error) {
 	glog.V(5).Infof("Call server NewNodeGroup: %v", request)
 
-	if s.Configuration.Optionals.NewNodeGroup {
-		return nil, fmt.Errorf(errNotImplemented)
+	if err := s.checkCapability(capabilityNewNodeGroup); err != nil {
+		return nil, err
 	}
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	machineType := s.Configuration.Machines[request.GetMachineType()]
@@ -506,10 +624,7 @@ func (s *MultipassServer) NewNodeGroup(ctx context.Context, request *apigrpc.New
 
 		return &apigrpc.NewNodeGroupReply{
 			Response: &apigrpc.NewNodeGroupReply_Error{
-				Error: &apigrpc.Error{
-					Code:   cloudProviderError,
-					Reason: fmt.Sprintf(errMachineTypeNotFound, request.GetMachineType()),
-				},
+				Error: toAPIError(newError(kindNotFound, errMachineTypeNotFound, request.GetMachineType())),
 			},
 		}, nil
 	}
@@ -556,10 +671,7 @@ func (s *MultipassServer) NewNodeGroup(ctx context.Context, request *apigrpc.New
 
 		return &apigrpc.NewNodeGroupReply{
 			Response: &apigrpc.NewNodeGroupReply_Error{
-				Error: &apigrpc.Error{
-					Code:   cloudProviderError,
-					Reason: fmt.Sprintf(errUnableToCreateNodeGroup, nodeGroupIdentifier, err),
-				},
+				Error: toAPIError(wrapError(kindVM, err, errUnableToCreateNodeGroup, nodeGroupIdentifier, err)),
 			},
 		}, nil
 	}
@@ -579,7 +691,7 @@ func (s *MultipassServer) GetResourceLimiter(ctx context.Context, request *apigr
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	return &apigrpc.ResourceLimiterReply{
@@ -597,8 +709,8 @@ func (s *MultipassServer) GPULabel(ctx context.Context, request *apigrpc.CloudPr
 	glog.V(5).Infof("Call server GPULabel: %v", request)
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
-		glog.Errorf(constantes.ErrMismatchingProvider)
-		return nil, fmt.Errorf(constantes.ErrMismatchingProvider)
+		glog.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	return &apigrpc.GPULabelReply{
@@ -613,8 +725,8 @@ func (s *MultipassServer) GetAvailableGPUTypes(ctx context.Context, request *api
 	glog.V(5).Infof("Call server GetAvailableGPUTypes: %v", request)
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
-		glog.Errorf(constantes.ErrMismatchingProvider)
-		return nil, fmt.Errorf(constantes.ErrMismatchingProvider)
+		glog.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	gpus := make(map[string]string)
@@ -632,15 +744,12 @@ func (s *MultipassServer) Cleanup(ctx context.Context, request *apigrpc.CloudPro
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	for _, nodeGroup := range s.Groups {
-		if err := nodeGroup.cleanup(s.Configuration.KubeCtlConfig); err != nil {
-			lastError = &apigrpc.Error{
-				Code:   cloudProviderError,
-				Reason: err.Error(),
-			}
+		if err := nodeGroup.cleanup(ctx, s.newNodeCreationExtra(nodeGroup)); err != nil {
+			lastError = toAPIError(err)
 		}
 	}
 
@@ -660,11 +769,15 @@ func (s *MultipassServer) Refresh(ctx context.Context, request *apigrpc.CloudPro
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
+	s.refreshJoinParameters(ctx)
+
 	for _, ng := range s.Groups {
-		ng.refresh()
+		ng.refresh(ctx, s.newNodeCreationExtra(ng))
+
+		s.refillWarmPool(ng)
 	}
 
 	if phSaveState {
@@ -686,7 +799,7 @@ func (s *MultipassServer) MaxSize(ctx context.Context, request *apigrpc.NodeGrou
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	nodeGroup := s.Groups[request.GetNodeGroupID()]
@@ -709,7 +822,7 @@ func (s *MultipassServer) MinSize(ctx context.Context, request *apigrpc.NodeGrou
 	var minSize int
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	nodeGroup := s.Groups[request.GetNodeGroupID()]
@@ -734,7 +847,7 @@ func (s *MultipassServer) TargetSize(ctx context.Context, request *apigrpc.NodeG
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	nodeGroup := s.Groups[request.GetNodeGroupID()]
@@ -744,10 +857,7 @@ func (s *MultipassServer) TargetSize(ctx context.Context, request *apigrpc.NodeG
 
 		return &apigrpc.TargetSizeReply{
 			Response: &apigrpc.TargetSizeReply_Error{
-				Error: &apigrpc.Error{
-					Code:   cloudProviderError,
-					Reason: fmt.Sprintf(errNodeGroupNotFound, request.GetNodeGroupID()),
-				},
+				Error: toAPIError(newError(kindNotFound, errNodeGroupNotFound, request.GetNodeGroupID())),
 			},
 		}, nil
 	}
@@ -767,7 +877,7 @@ func (s *MultipassServer) IncreaseSize(ctx context.Context, request *apigrpc.Inc
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	nodeGroup := s.Groups[request.GetNodeGroupID()]
@@ -776,10 +886,7 @@ func (s *MultipassServer) IncreaseSize(ctx context.Context, request *apigrpc.Inc
 		glog.Errorf(errNodeGroupNotFound, request.GetNodeGroupID())
 
 		return &apigrpc.IncreaseSizeReply{
-			Error: &apigrpc.Error{
-				Code:   cloudProviderError,
-				Reason: fmt.Sprintf(errNodeGroupNotFound, request.GetNodeGroupID()),
-			},
+			Error: toAPIError(newError(kindNotFound, errNodeGroupNotFound, request.GetNodeGroupID())),
 		}, nil
 	}
 
@@ -787,10 +894,7 @@ func (s *MultipassServer) IncreaseSize(ctx context.Context, request *apigrpc.Inc
 		glog.Errorf(errIncreaseSizeMustBePositive)
 
 		return &apigrpc.IncreaseSizeReply{
-			Error: &apigrpc.Error{
-				Code:   cloudProviderError,
-				Reason: errIncreaseSizeMustBePositive,
-			},
+			Error: toAPIError(newError(kindInvalidArgument, errIncreaseSizeMustBePositive)),
 		}, nil
 	}
 
@@ -800,37 +904,46 @@ func (s *MultipassServer) IncreaseSize(ctx context.Context, request *apigrpc.Inc
 		glog.Errorf(errIncreaseSizeTooLarge, newSize, nodeGroup.MaxNodeSize)
 
 		return &apigrpc.IncreaseSizeReply{
-			Error: &apigrpc.Error{
-				Code:   cloudProviderError,
-				Reason: fmt.Sprintf(errIncreaseSizeTooLarge, newSize, nodeGroup.MaxNodeSize),
-			},
+			Error: toAPIError(newError(kindFailedPrecondition, errIncreaseSizeTooLarge, newSize, nodeGroup.MaxNodeSize)),
 		}, nil
 	}
 
-	extras := &nodeCreationExtra{
-		kubeHost:      s.KubeAdmConfiguration.KubeAdmAddress,
-		kubeToken:     s.KubeAdmConfiguration.KubeAdmToken,
-		kubeCACert:    s.KubeAdmConfiguration.KubeAdmCACert,
-		kubeExtraArgs: s.KubeAdmConfiguration.KubeAdmExtraArguments,
-		kubeConfig:    s.Configuration.KubeCtlConfig,
-		image:         s.Configuration.Image,
-		cloudInit:     s.Configuration.CloudInit,
-		mountPoints:   s.Configuration.MountPoints,
-		nodegroupID:   nodeGroup.NodeGroupIdentifier,
-		nodeLabels:    nodeGroup.NodeLabels,
-		systemLabels:  nodeGroup.SystemLabels,
-		vmprovision:   s.Configuration.VMProvision,
-		cacheDir:      s.CacheDir,
+	if err := s.checkScaleUp(nodeGroup, int(request.GetDelta())); err != nil {
+		return &apigrpc.IncreaseSizeReply{
+			Error: toAPIError(err),
+		}, nil
+	}
+
+	// Fail fast, the autoscaler will see the failed instances as out of resources
+	if errorInfo := s.checkPlacement(nodeGroup, int(request.GetDelta())); errorInfo != nil {
+		nodeGroup.addFailedNodes(int(request.GetDelta()), errorInfo)
+
+		return &apigrpc.IncreaseSizeReply{
+			Error: nil,
+		}, nil
+	}
+
+	if errorInfo := s.checkHostCapacity(nodeGroup, int(request.GetDelta())); errorInfo != nil {
+		nodeGroup.addFailedNodes(int(request.GetDelta()), errorInfo)
+
+		return &apigrpc.IncreaseSizeReply{
+			Error: nil,
+		}, nil
 	}
 
-	err := nodeGroup.setNodeGroupSize(newSize, extras)
+	// Never join with an expired token
+	s.refreshJoinParameters(ctx)
+
+	extras := s.newNodeCreationExtra(nodeGroup)
+
+	err := nodeGroup.setNodeGroupSize(ctx, newSize, extras)
+
+	// The warm VMs used are replaced in background
+	s.refillWarmPool(nodeGroup)
 
 	if err != nil {
 		return &apigrpc.IncreaseSizeReply{
-			Error: &apigrpc.Error{
-				Code:   cloudProviderError,
-				Reason: err.Error(),
-			},
+			Error: toAPIError(err),
 		}, nil
 	}
 
@@ -847,7 +960,7 @@ func (s *MultipassServer) DeleteNodes(ctx context.Context, request *apigrpc.Dele
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	nodeGroup := s.Groups[request.GetNodeGroupID()]
@@ -856,19 +969,28 @@ func (s *MultipassServer) DeleteNodes(ctx context.Context, request *apigrpc.Dele
 		glog.Errorf(errNodeGroupNotFound, request.GetNodeGroupID())
 
 		return &apigrpc.DeleteNodesReply{
-			Error: &apigrpc.Error{
-				Code:   cloudProviderError,
-				Reason: fmt.Sprintf(errNodeGroupNotFound, request.GetNodeGroupID()),
-			},
+			Error: toAPIError(newError(kindNotFound, errNodeGroupNotFound, request.GetNodeGroupID())),
 		}, nil
 	}
 
 	if nodeGroup.targetSize()-len(request.GetNode()) < nodeGroup.MinNodeSize {
 		return &apigrpc.DeleteNodesReply{
-			Error: &apigrpc.Error{
-				Code:   cloudProviderError,
-				Reason: fmt.Sprintf(errMinSizeReached, request.GetNodeGroupID()),
-			},
+			Error: toAPIError(newError(kindFailedPrecondition, errMinSizeReached, request.GetNodeGroupID())),
+		}, nil
+	}
+
+	nodesToDelete := s.nodesToDelete(nodeGroup, request.GetNode())
+
+	if err := s.checkScaleDown(nodeGroup, nodesToDelete); err != nil {
+		return &apigrpc.DeleteNodesReply{
+			Error: toAPIError(err),
+		}, nil
+	}
+
+	// Never break the etcd quorum of a control plane node group
+	if err := s.checkQuorum(ctx, nodeGroup, nodesToDelete); err != nil {
+		return &apigrpc.DeleteNodesReply{
+			Error: toAPIError(err),
 		}, nil
 	}
 
@@ -881,10 +1003,7 @@ func (s *MultipassServer) DeleteNodes(ctx context.Context, request *apigrpc.Dele
 			glog.Errorf(errCantUnmarshallNodeWithReason, sNode, err)
 
 			return &apigrpc.DeleteNodesReply{
-				Error: &apigrpc.Error{
-					Code:   cloudProviderError,
-					Reason: fmt.Sprintf(errCantUnmarshallNode, idx, request.GetNodeGroupID()),
-				},
+				Error: toAPIError(newError(kindInvalidArgument, errCantUnmarshallNode, idx, request.GetNodeGroupID())),
 			}, nil
 		}
 
@@ -897,34 +1016,25 @@ func (s *MultipassServer) DeleteNodes(ctx context.Context, request *apigrpc.Dele
 			glog.Errorf(errNodeGroupNotFound, nodeName)
 
 			return &apigrpc.DeleteNodesReply{
-				Error: &apigrpc.Error{
-					Code:   cloudProviderError,
-					Reason: err.Error(),
-				},
+				Error: toAPIError(err),
 			}, nil
 		}
 
 		// Not in the same group
 		if nodeGroupForNode.NodeGroupIdentifier != nodeGroup.NodeGroupIdentifier {
 			return &apigrpc.DeleteNodesReply{
-				Error: &apigrpc.Error{
-					Code:   cloudProviderError,
-					Reason: fmt.Sprintf(errUnableToDeleteNode, nodeName, nodeGroup.NodeGroupIdentifier),
-				},
+				Error: toAPIError(newError(kindInvalidArgument, errUnableToDeleteNode, nodeName, nodeGroup.NodeGroupIdentifier)),
 			}, nil
 		}
 
 		// Delete the node in the group
 		nodeName, err = nodeNameFromProviderID(s.Configuration.ProviderID, nodeName)
 
-		err = nodeGroup.deleteNodeByName(s.Configuration.KubeCtlConfig, nodeName)
+		err = nodeGroup.deleteNodeByName(ctx, s.newNodeCreationExtra(nodeGroup), nodeName)
 
 		if err != nil {
 			return &apigrpc.DeleteNodesReply{
-				Error: &apigrpc.Error{
-					Code:   cloudProviderError,
-					Reason: err.Error(),
-				},
+				Error: toAPIError(err),
 			}, nil
 		}
 	}
@@ -934,6 +1044,25 @@ func (s *MultipassServer) DeleteNodes(ctx context.Context, request *apigrpc.Dele
 	}, nil
 }
 
+// nodesToDelete returns the nodes owned by the node group matching the serialized nodes
+func (s *MultipassServer) nodesToDelete(nodeGroup *MultipassNodeGroup, sNodes []string) []*MultipassNode {
+	nodes := make([]*MultipassNode, 0, len(sNodes))
+
+	for _, sNode := range sNodes {
+		if node, err := nodeFromJSON(sNode); err == nil {
+			providerID := getNodeProviderID(s.Configuration.ProviderID, node)
+
+			if nodeName, err := nodeNameFromProviderID(s.Configuration.ProviderID, providerID); err == nil {
+				if vm := nodeGroup.Nodes[nodeName]; vm != nil {
+					nodes = append(nodes, vm)
+				}
+			}
+		}
+	}
+
+	return nodes
+}
+
 // DecreaseTargetSize decreases the target size of the node group. This function
 // doesn't permit to delete any existing node and can be used only to reduce the
 // request for new nodes that have not been yet fulfilled. Delta should be negative.
@@ -944,7 +1073,7 @@ func (s *MultipassServer) DecreaseTargetSize(ctx context.Context, request *apigr
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	nodeGroup := s.Groups[request.GetNodeGroupID()]
@@ -953,10 +1082,7 @@ func (s *MultipassServer) DecreaseTargetSize(ctx context.Context, request *apigr
 		glog.Errorf(errNodeGroupNotFound, request.GetNodeGroupID())
 
 		return &apigrpc.DecreaseTargetSizeReply{
-			Error: &apigrpc.Error{
-				Code:   cloudProviderError,
-				Reason: fmt.Sprintf(errNodeGroupNotFound, request.GetNodeGroupID()),
-			},
+			Error: toAPIError(newError(kindNotFound, errNodeGroupNotFound, request.GetNodeGroupID())),
 		}, nil
 	}
 
@@ -964,10 +1090,7 @@ func (s *MultipassServer) DecreaseTargetSize(ctx context.Context, request *apigr
 		glog.Errorf(errDecreaseSizeMustBeNegative)
 
 		return &apigrpc.DecreaseTargetSizeReply{
-			Error: &apigrpc.Error{
-				Code:   cloudProviderError,
-				Reason: errDecreaseSizeMustBeNegative,
-			},
+			Error: toAPIError(newError(kindInvalidArgument, errDecreaseSizeMustBeNegative)),
 		}, nil
 	}
 
@@ -977,37 +1100,23 @@ func (s *MultipassServer) DecreaseTargetSize(ctx context.Context, request *apigr
 		glog.Errorf(errDecreaseSizeAttemptDeleteNodes, nodeGroup.targetSize(), request.GetDelta(), newSize)
 
 		return &apigrpc.DecreaseTargetSizeReply{
-			Error: &apigrpc.Error{
-				Code:   cloudProviderError,
-				Reason: fmt.Sprintf(errDecreaseSizeAttemptDeleteNodes, nodeGroup.targetSize(), request.GetDelta(), newSize),
-			},
+			Error: toAPIError(newError(kindFailedPrecondition, errDecreaseSizeAttemptDeleteNodes, nodeGroup.targetSize(), request.GetDelta(), newSize)),
 		}, nil
 	}
 
-	extras := &nodeCreationExtra{
-		kubeHost:      s.KubeAdmConfiguration.KubeAdmAddress,
-		kubeToken:     s.KubeAdmConfiguration.KubeAdmToken,
-		kubeCACert:    s.KubeAdmConfiguration.KubeAdmCACert,
-		kubeExtraArgs: s.KubeAdmConfiguration.KubeAdmExtraArguments,
-		kubeConfig:    s.Configuration.KubeCtlConfig,
-		image:         s.Configuration.Image,
-		cloudInit:     s.Configuration.CloudInit,
-		mountPoints:   s.Configuration.MountPoints,
-		nodegroupID:   nodeGroup.NodeGroupIdentifier,
-		nodeLabels:    nodeGroup.NodeLabels,
-		systemLabels:  nodeGroup.SystemLabels,
-		vmprovision:   s.Configuration.VMProvision,
-		cacheDir:      s.CacheDir,
+	if err := s.checkQuorum(ctx, nodeGroup, nodeGroup.lastNodes(newSize-nodeGroup.targetSize())); err != nil {
+		return &apigrpc.DecreaseTargetSizeReply{
+			Error: toAPIError(err),
+		}, nil
 	}
 
-	err := nodeGroup.setNodeGroupSize(newSize, extras)
+	extras := s.newNodeCreationExtra(nodeGroup)
+
+	err := nodeGroup.setNodeGroupSize(ctx, newSize, extras)
 
 	if err != nil {
 		return &apigrpc.DecreaseTargetSizeReply{
-			Error: &apigrpc.Error{
-				Code:   cloudProviderError,
-				Reason: err.Error(),
-			},
+			Error: toAPIError(err),
 		}, nil
 	}
 
@@ -1022,7 +1131,7 @@ func (s *MultipassServer) Id(ctx context.Context, request *apigrpc.NodeGroupServ
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	nodeGroup := s.Groups[request.GetNodeGroupID()]
@@ -1030,7 +1139,7 @@ func (s *MultipassServer) Id(ctx context.Context, request *apigrpc.NodeGroupServ
 	if nodeGroup == nil {
 		glog.Errorf(errNodeGroupNotFound, request.GetNodeGroupID())
 
-		return nil, fmt.Errorf(errNodeGroupNotFound, request.GetNodeGroupID())
+		return nil, toStatusError(newError(kindNotFound, errNodeGroupNotFound, request.GetNodeGroupID()))
 	}
 
 	return &apigrpc.IdReply{
@@ -1044,7 +1153,7 @@ func (s *MultipassServer) Debug(ctx context.Context, request *apigrpc.NodeGroupS
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	nodeGroup := s.Groups[request.GetNodeGroupID()]
@@ -1052,11 +1161,17 @@ func (s *MultipassServer) Debug(ctx context.Context, request *apigrpc.NodeGroupS
 	if nodeGroup == nil {
 		glog.Errorf(errNodeGroupNotFound, request.GetNodeGroupID())
 
-		return nil, fmt.Errorf(errNodeGroupNotFound, request.GetNodeGroupID())
+		return nil, toStatusError(newError(kindNotFound, errNodeGroupNotFound, request.GetNodeGroupID()))
+	}
+
+	response := fmt.Sprintf("%s-%s", request.GetProviderID(), nodeGroup.NodeGroupIdentifier)
+
+	if capacity, err := s.hostCapacity(); err == nil {
+		response = fmt.Sprintf("%s, host capacity: %s", response, capacity)
 	}
 
 	return &apigrpc.DebugReply{
-		Response: fmt.Sprintf("%s-%s", request.GetProviderID(), nodeGroup.NodeGroupIdentifier),
+		Response: response,
 	}, nil
 }
 
@@ -1068,7 +1183,7 @@ func (s *MultipassServer) Nodes(ctx context.Context, request *apigrpc.NodeGroupS
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	nodeGroup := s.Groups[request.GetNodeGroupID()]
@@ -1078,10 +1193,7 @@ func (s *MultipassServer) Nodes(ctx context.Context, request *apigrpc.NodeGroupS
 
 		return &apigrpc.NodesReply{
 			Response: &apigrpc.NodesReply_Error{
-				Error: &apigrpc.Error{
-					Code:   cloudProviderError,
-					Reason: fmt.Sprintf(errNodeGroupNotFound, request.GetNodeGroupID()),
-				},
+				Error: toAPIError(newError(kindNotFound, errNodeGroupNotFound, request.GetNodeGroupID())),
 			},
 		}, nil
 	}
@@ -1089,12 +1201,23 @@ func (s *MultipassServer) Nodes(ctx context.Context, request *apigrpc.NodeGroupS
 	instances := make([]*apigrpc.Instance, 0, len(nodeGroup.Nodes))
 
 	for nodeName, node := range nodeGroup.Nodes {
+		status := &apigrpc.InstanceStatus{
+			State:     apigrpc.InstanceState(node.State),
+			ErrorInfo: nil,
+		}
+
+		if node.failed() {
+			status.State = apigrpc.InstanceState_STATE_BEING_CREATED
+			status.ErrorInfo = &apigrpc.InstanceErrorInfo{
+				ErrorClass:   node.ErrorInfo.Class,
+				ErrorCode:    node.ErrorInfo.Code,
+				ErrorMessage: node.ErrorInfo.Message,
+			}
+		}
+
 		instances = append(instances, &apigrpc.Instance{
-			Id: nodeGroup.providerIDForNode(nodeName),
-			Status: &apigrpc.InstanceStatus{
-				State:     apigrpc.InstanceState(node.State),
-				ErrorInfo: nil,
-			},
+			Id:     nodeGroup.providerIDForNode(nodeName),
+			Status: status,
 		})
 	}
 
@@ -1116,13 +1239,13 @@ func (s *MultipassServer) Nodes(ctx context.Context, request *apigrpc.NodeGroupS
 func (s *MultipassServer) TemplateNodeInfo(ctx context.Context, request *apigrpc.NodeGroupServiceRequest) (*apigrpc.TemplateNodeInfoReply, error) {
 	glog.V(5).Infof("Call server TemplateNodeInfo: %v", request)
 
-	if s.Configuration.Optionals.TemplateNodeInfo {
-		return nil, fmt.Errorf(errNotImplemented)
+	if err := s.checkCapability(capabilityTemplateNodeInfo); err != nil {
+		return nil, err
 	}
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	nodeGroup := s.Groups[request.GetNodeGroupID()]
@@ -1132,19 +1255,25 @@ func (s *MultipassServer) TemplateNodeInfo(ctx context.Context, request *apigrpc
 
 		return &apigrpc.TemplateNodeInfoReply{
 			Response: &apigrpc.TemplateNodeInfoReply_Error{
-				Error: &apigrpc.Error{
-					Code:   cloudProviderError,
-					Reason: fmt.Sprintf(errNodeGroupNotFound, request.GetNodeGroupID()),
-				},
+				Error: toAPIError(newError(kindNotFound, errNodeGroupNotFound, request.GetNodeGroupID())),
 			},
 		}, nil
 	}
 
-	node := &apiv1.Node{
-		Spec: apiv1.NodeSpec{
-			ProviderID:    nodeGroup.providerID(),
-			Unschedulable: false,
-		},
+	node := nodeGroup.templateNode()
+
+	// The zone of the host the next node would be placed on
+	if host, err := s.newHostPlacement(nodeGroup).place(nodeGroup.Machine); err == nil && len(host) > 0 {
+		node.Labels[nodeLabelZone] = host
+	}
+
+	node.Spec.Taints = append(node.Spec.Taints, s.Configuration.Bootstrap.nodeTaints(nodeGroup.NodeGroupIdentifier)...)
+	node.Spec.Taints = append(node.Spec.Taints, nodeGroup.Kubelet.registerTaints()...)
+
+	if nodeGroup.ControlPlane {
+		node.Labels[nodeLabelMaster] = ""
+		node.Labels[nodeLabelControlPlane] = ""
+		node.Spec.Taints = append(node.Spec.Taints, controlPlaneTaints()...)
 	}
 
 	return &apigrpc.TemplateNodeInfoReply{
@@ -1161,7 +1290,7 @@ func (s *MultipassServer) Exist(ctx context.Context, request *apigrpc.NodeGroupS
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	nodeGroup := s.Groups[request.GetNodeGroupID()]
@@ -1175,26 +1304,23 @@ func (s *MultipassServer) Exist(ctx context.Context, request *apigrpc.NodeGroupS
 func (s *MultipassServer) Create(ctx context.Context, request *apigrpc.NodeGroupServiceRequest) (*apigrpc.CreateReply, error) {
 	glog.V(5).Infof("Call server Create: %v", request)
 
-	if s.Configuration.Optionals.Create {
-		return nil, fmt.Errorf(errNotImplemented)
+	if err := s.checkCapability(capabilityCreate); err != nil {
+		return nil, err
 	}
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
-	nodeGroup, err := s.createNodeGroup(request.GetNodeGroupID())
+	nodeGroup, err := s.createNodeGroup(ctx, request.GetNodeGroupID())
 
 	if err != nil {
 		glog.Errorf(errUnableToCreateNodeGroup, request.GetNodeGroupID(), err)
 
 		return &apigrpc.CreateReply{
 			Response: &apigrpc.CreateReply_Error{
-				Error: &apigrpc.Error{
-					Code:   cloudProviderError,
-					Reason: err.Error(),
-				},
+				Error: toAPIError(err),
 			},
 		}, nil
 	}
@@ -1214,24 +1340,21 @@ func (s *MultipassServer) Create(ctx context.Context, request *apigrpc.NodeGroup
 func (s *MultipassServer) Delete(ctx context.Context, request *apigrpc.NodeGroupServiceRequest) (*apigrpc.DeleteReply, error) {
 	glog.V(5).Infof("Call server Delete: %v", request)
 
-	if s.Configuration.Optionals.Delete {
-		return nil, fmt.Errorf(errNotImplemented)
+	if err := s.checkCapability(capabilityDelete); err != nil {
+		return nil, err
 	}
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
-	err := s.deleteNodeGroup(request.GetNodeGroupID())
+	err := s.deleteNodeGroup(ctx, request.GetNodeGroupID())
 
 	if err != nil {
 		glog.Errorf(errUnableToDeleteNodeGroup, request.GetNodeGroupID(), err)
 		return &apigrpc.DeleteReply{
-			Error: &apigrpc.Error{
-				Code:   cloudProviderError,
-				Reason: err.Error(),
-			},
+			Error: toAPIError(err),
 		}, nil
 	}
 
@@ -1249,7 +1372,7 @@ func (s *MultipassServer) Autoprovisioned(ctx context.Context, request *apigrpc.
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	ng := s.Groups[request.GetNodeGroupID()]
@@ -1269,7 +1392,7 @@ func (s *MultipassServer) Belongs(ctx context.Context, request *apigrpc.BelongsR
 
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
 	}
 
 	node, err := nodeFromJSON(request.GetNode())
@@ -1279,10 +1402,7 @@ func (s *MultipassServer) Belongs(ctx context.Context, request *apigrpc.BelongsR
 
 		return &apigrpc.BelongsReply{
 			Response: &apigrpc.BelongsReply_Error{
-				Error: &apigrpc.Error{
-					Code:   cloudProviderError,
-					Reason: err.Error(),
-				},
+				Error: toAPIError(wrapError(kindInvalidArgument, err, errCantUnmarshallNodeWithReason, request.GetNode(), err)),
 			},
 		}, nil
 	}
@@ -1298,10 +1418,7 @@ func (s *MultipassServer) Belongs(ctx context.Context, request *apigrpc.BelongsR
 		if err != nil {
 			return &apigrpc.BelongsReply{
 				Response: &apigrpc.BelongsReply_Error{
-					Error: &apigrpc.Error{
-						Code:   cloudProviderError,
-						Reason: err.Error(),
-					},
+					Error: toAPIError(err),
 				},
 			}, nil
 		}
@@ -1321,14 +1438,42 @@ func (s *MultipassServer) Belongs(ctx context.Context, request *apigrpc.BelongsR
 func (s *MultipassServer) NodePrice(ctx context.Context, request *apigrpc.NodePriceRequest) (*apigrpc.NodePriceReply, error) {
 	glog.V(5).Infof("Call server NodePrice: %v", request)
 
+	if err := s.checkCapability(capabilityPricing); err != nil {
+		return nil, err
+	}
+
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
+	}
+
+	node, err := nodeFromJSON(request.GetNode())
+
+	if err != nil {
+		glog.Errorf(errCantUnmarshallNodeWithReason, request.GetNode(), err)
+
+		return &apigrpc.NodePriceReply{
+			Response: &apigrpc.NodePriceReply_Error{
+				Error: toAPIError(wrapError(kindInvalidArgument, err, errCantUnmarshallNodeWithReason, request.GetNode(), err)),
+			},
+		}, nil
+	}
+
+	price, err := s.nodePrice(node, request.GetStartTime(), request.GetEndTime())
+
+	if err != nil {
+		glog.Errorf(err.Error())
+
+		return &apigrpc.NodePriceReply{
+			Response: &apigrpc.NodePriceReply_Error{
+				Error: toAPIError(err),
+			},
+		}, nil
 	}
 
 	return &apigrpc.NodePriceReply{
 		Response: &apigrpc.NodePriceReply_Price{
-			Price: s.Configuration.NodePrice,
+			Price: price,
 		},
 	}, nil
 }
@@ -1338,14 +1483,42 @@ func (s *MultipassServer) NodePrice(ctx context.Context, request *apigrpc.NodePr
 func (s *MultipassServer) PodPrice(ctx context.Context, request *apigrpc.PodPriceRequest) (*apigrpc.PodPriceReply, error) {
 	glog.V(5).Infof("Call server PodPrice: %v", request)
 
+	if err := s.checkCapability(capabilityPricing); err != nil {
+		return nil, err
+	}
+
 	if request.GetProviderID() != s.Configuration.ProviderID {
 		glog.Errorf(errMismatchingProvider)
-		return nil, fmt.Errorf(errMismatchingProvider)
+		return nil, toStatusError(newError(kindMismatchingProvider, errMismatchingProvider))
+	}
+
+	pod, err := podFromJSON(request.GetPod())
+
+	if err != nil {
+		glog.Errorf(errCantUnmarshallPodWithReason, request.GetPod(), err)
+
+		return &apigrpc.PodPriceReply{
+			Response: &apigrpc.PodPriceReply_Error{
+				Error: toAPIError(wrapError(kindInvalidArgument, err, errCantUnmarshallPodWithReason, request.GetPod(), err)),
+			},
+		}, nil
+	}
+
+	price, err := s.podPrice(pod, request.GetStartTime(), request.GetEndTime())
+
+	if err != nil {
+		glog.Errorf(err.Error())
+
+		return &apigrpc.PodPriceReply{
+			Response: &apigrpc.PodPriceReply_Error{
+				Error: toAPIError(err),
+			},
+		}, nil
 	}
 
 	return &apigrpc.PodPriceReply{
 		Response: &apigrpc.PodPriceReply_Price{
-			Price: s.Configuration.PodPrice,
+			Price: price,
 		},
 	}, nil
 }
@@ -1361,6 +1534,12 @@ func (s *MultipassServer) save(fileName string) error {
 
 	defer file.Close()
 
+	// The warm pools are filled in background
+	for _, nodeGroup := range s.Groups {
+		nodeGroup.standbyLock.RLock()
+		defer nodeGroup.standbyLock.RUnlock()
+	}
+
 	encoder := json.NewEncoder(file)
 	err = encoder.Encode(s)
 
@@ -1392,8 +1571,12 @@ func (s *MultipassServer) load(fileName string) error {
 		return err
 	}
 
+	for _, nodeGroup := range s.Groups {
+		nodeGroup.discardPendingWarmNodes(context.Background(), s.newNodeCreationExtra(nodeGroup))
+	}
+
 	if s.AutoProvision {
-		if err := s.doAutoProvision(); err != nil {
+		if err := s.doAutoProvision(context.Background()); err != nil {
 			glog.Errorf(errUnableToAutoProvisionNodeGroup, err)
 
 			return err
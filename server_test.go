@@ -39,6 +39,47 @@ var testNodeGroup = MultipassNodeGroup{
 	},
 }
 
+// testNodeGroupOption customize the node group returned by newTestNodeGroup
+type testNodeGroupOption func(ng *MultipassNodeGroup)
+
+func withMaxNodeSize(size int) testNodeGroupOption {
+	return func(ng *MultipassNodeGroup) {
+		ng.MaxNodeSize = size
+	}
+}
+
+func withMachine(machine *MachineCharacteristic) testNodeGroupOption {
+	return func(ng *MultipassNodeGroup) {
+		ng.Machine = machine
+	}
+}
+
+func withNodes(nodes map[string]*MultipassNode) testNodeGroupOption {
+	return func(ng *MultipassNodeGroup) {
+		ng.Nodes = nodes
+	}
+}
+
+// newTestNodeGroup returns a created node group of the machine type, without nodes
+func newTestNodeGroup(machineType string, opts ...testNodeGroupOption) *MultipassNodeGroup {
+	ng := &MultipassNodeGroup{
+		ServiceIdentifier:   testProviderID,
+		NodeGroupIdentifier: testGroupID,
+		MachineType:         machineType,
+		Machine:             testMachines[machineType],
+		Status:              NodegroupCreated,
+		MaxNodeSize:         5,
+		PendingNodes:        make(map[string]*MultipassNode),
+		Nodes:               make(map[string]*MultipassNode),
+	}
+
+	for _, opt := range opts {
+		opt(ng)
+	}
+
+	return ng
+}
+
 func newTestServer(nodeGroup *MultipassNodeGroup) (*MultipassServer, context.Context, error) {
 
 	var config MultipassServerConfig
@@ -51,6 +92,10 @@ func newTestServer(nodeGroup *MultipassNodeGroup) (*MultipassServer, context.Con
 		return nil, nil, err
 	}
 
+	return newTestServerWithConfig(nodeGroup, config)
+}
+
+func newTestServerWithConfig(nodeGroup *MultipassNodeGroup, config MultipassServerConfig) (*MultipassServer, context.Context, error) {
 	s := &MultipassServer{
 		ResourceLimiter: &ResourceLimiter{
 			map[string]int64{ResourceNameCores: 1, ResourceNameMemory: 10000000},
@@ -70,7 +115,7 @@ func newTestServer(nodeGroup *MultipassNodeGroup) (*MultipassServer, context.Con
 		s.Groups[nodeGroup.NodeGroupIdentifier] = nodeGroup
 	}
 
-	return s, nil, nil
+	return s, context.Background(), nil
 }
 
 func extractNodeGroup(nodeGroups []*apigrpc.NodeGroup) []string {
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	sshCommandLine = "ssh"
+	scpCommandLine = "scp"
+
+	defaultSSHUser = "ubuntu"
+
+	knownHostsFileName = "known_hosts"
+)
+
+// shellSafeArgument match the arguments passed as is to the remote shell
+var shellSafeArgument = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// sshRemote exec commands and copy files in a guest reachable by ssh
+type sshRemote struct {
+	user       string
+	key        string
+	port       int
+	knownHosts string // known_hosts file, the host keys of guest VMs are not checked when empty, their address is reused
+	acceptNew  bool   // unknown host keys are added to knownHosts, changed keys are still refused
+}
+
+// newHostSSHRemote returns the ssh access of a hypervisor host, without known-hosts the keys are trusted on first use
+func newHostSSHRemote(host *HypervisorHost, cacheDir string) *sshRemote {
+	remote := &sshRemote{
+		user:       host.SSHUser,
+		key:        host.SSHKey,
+		knownHosts: host.KnownHosts,
+	}
+
+	if len(remote.knownHosts) == 0 {
+		remote.knownHosts = path.Join(cacheDir, knownHostsFileName)
+		remote.acceptNew = true
+	}
+
+	return remote
+}
+
+// shellQuote quote the argument for the remote shell
+func shellQuote(arg string) string {
+	if shellSafeArgument.MatchString(arg) {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'"'"'`) + "'"
+}
+
+// options returns the ssh options, portFlag is -p for ssh and -P for scp
+func (r *sshRemote) options(portFlag string) []string {
+	var options []string
+
+	switch {
+	case len(r.knownHosts) == 0:
+		options = []string{"-o", "StrictHostKeyChecking=no", "-o", "UserKnownHostsFile=/dev/null"}
+	case r.acceptNew:
+		options = []string{"-o", "StrictHostKeyChecking=accept-new", "-o", "UserKnownHostsFile=" + r.knownHosts}
+	default:
+		options = []string{"-o", "StrictHostKeyChecking=yes", "-o", "UserKnownHostsFile=" + r.knownHosts}
+	}
+
+	options = append(options, "-o", "BatchMode=yes")
+
+	if len(r.key) > 0 {
+		options = append(options, "-i", r.key)
+	}
+
+	if r.port > 0 {
+		options = append(options, portFlag, strconv.Itoa(r.port))
+	}
+
+	return options
+}
+
+func (r *sshRemote) login(address string) string {
+	user := r.user
+
+	if len(user) == 0 {
+		user = defaultSSHUser
+	}
+
+	return fmt.Sprintf("%s@%s", user, address)
+}
+
+// command returns the ssh command line running args on address, the args are quoted for the remote shell
+func (r *sshRemote) command(address string, args ...string) []string {
+	command := append([]string{sshCommandLine}, r.options("-p")...)
+	command = append(command, r.login(address))
+
+	for _, arg := range args {
+		command = append(command, shellQuote(arg))
+	}
+
+	return command
+}
+
+func (r *sshRemote) exec(ctx context.Context, address string, args ...string) (string, error) {
+	return pipe(ctx, r.command(address, args...)...)
+}
+
+func (r *sshRemote) copy(ctx context.Context, address, src, dst string) error {
+	command := append([]string{scpCommandLine}, r.options("-P")...)
+
+	return shell(ctx, append(command, src, r.login(address)+":"+dst)...)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_shellQuote(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{arg: "multipass", want: "multipass"},
+		{arg: "/etc/default/kubelet", want: "/etc/default/kubelet"},
+		{arg: "--node-ip=10.0.0.2", want: "--node-ip=10.0.0.2"},
+		{arg: "", want: "''"},
+		{arg: "systemctl restart kubelet && echo done", want: "'systemctl restart kubelet && echo done'"},
+		{arg: "echo 'hello'", want: `'echo '"'"'hello'"'"''`},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, shellQuote(tt.arg), tt.arg)
+	}
+}
+
+func Test_sshRemote_command(t *testing.T) {
+	remote := &sshRemote{user: "admin"}
+
+	assert.Equal(t, []string{sshCommandLine, "admin@10.0.0.2", "sudo", "sh", "-c", "'cat /etc/hostname | tr a-z A-Z'"},
+		removeSSHOptions(remote.command("10.0.0.2", "sudo", "sh", "-c", "cat /etc/hostname | tr a-z A-Z")))
+
+	assert.Contains(t, remote.options("-p"), "StrictHostKeyChecking=no", "guest VM")
+
+	remote = newHostSSHRemote(&HypervisorHost{Name: "host-1", SSHUser: "admin"}, "/var/cache/autoscaler")
+
+	assert.Contains(t, remote.options("-p"), "StrictHostKeyChecking=accept-new")
+	assert.Contains(t, remote.options("-p"), "UserKnownHostsFile=/var/cache/autoscaler/known_hosts")
+
+	remote = newHostSSHRemote(&HypervisorHost{Name: "host-1", KnownHosts: "/etc/ssh/ssh_known_hosts"}, "/var/cache/autoscaler")
+
+	assert.Contains(t, remote.options("-p"), "StrictHostKeyChecking=yes")
+	assert.Contains(t, remote.options("-p"), "UserKnownHostsFile=/etc/ssh/ssh_known_hosts")
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"time"
+)
+
+const (
+	defaultLaunchTimeout = 600
+	defaultJoinTimeout   = 300
+	defaultDrainTimeout  = 300
+	defaultDeleteTimeout = 120
+	defaultInfoTimeout   = 30
+)
+
+// MultipassServerTimeouts declare in seconds how long each kind of operation is allowed to run.
+// A zero value use the default timeout
+type MultipassServerTimeouts struct {
+	Launch int `json:"launch"` // multipass launch, include mounts
+	Join   int `json:"join"`   // kubelet preparation, kubeadm join, wait node ready and labels
+	Drain  int `json:"drain"`  // kubectl cordon, uncordon and drain
+	Delete int `json:"delete"` // multipass stop and delete of a deleted node
+	Start  int `json:"start"`  // multipass start, the delete timeout by default
+	Stop   int `json:"stop"`   // multipass stop, the delete timeout by default
+	Info   int `json:"info"`   // multipass info, kubectl get and delete node
+}
+
+func timeoutOrDefault(seconds, defaultSeconds int) time.Duration {
+	if seconds <= 0 {
+		seconds = defaultSeconds
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+func (t *MultipassServerTimeouts) launch() time.Duration {
+	if t == nil {
+		return timeoutOrDefault(0, defaultLaunchTimeout)
+	}
+
+	return timeoutOrDefault(t.Launch, defaultLaunchTimeout)
+}
+
+func (t *MultipassServerTimeouts) join() time.Duration {
+	if t == nil {
+		return timeoutOrDefault(0, defaultJoinTimeout)
+	}
+
+	return timeoutOrDefault(t.Join, defaultJoinTimeout)
+}
+
+func (t *MultipassServerTimeouts) drain() time.Duration {
+	if t == nil {
+		return timeoutOrDefault(0, defaultDrainTimeout)
+	}
+
+	return timeoutOrDefault(t.Drain, defaultDrainTimeout)
+}
+
+func (t *MultipassServerTimeouts) delete() time.Duration {
+	if t == nil {
+		return timeoutOrDefault(0, defaultDeleteTimeout)
+	}
+
+	return timeoutOrDefault(t.Delete, defaultDeleteTimeout)
+}
+
+func (t *MultipassServerTimeouts) start() time.Duration {
+	if t == nil || t.Start <= 0 {
+		return t.delete()
+	}
+
+	return timeoutOrDefault(t.Start, defaultDeleteTimeout)
+}
+
+func (t *MultipassServerTimeouts) stop() time.Duration {
+	if t == nil || t.Stop <= 0 {
+		return t.delete()
+	}
+
+	return timeoutOrDefault(t.Stop, defaultDeleteTimeout)
+}
+
+func (t *MultipassServerTimeouts) info() time.Duration {
+	if t == nil {
+		return timeoutOrDefault(0, defaultInfoTimeout)
+	}
+
+	return timeoutOrDefault(t.Info, defaultInfoTimeout)
+}
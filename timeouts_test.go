@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultipassServerTimeouts_defaults(t *testing.T) {
+	var timeouts *MultipassServerTimeouts
+
+	assert.Equal(t, defaultLaunchTimeout*time.Second, timeouts.launch())
+	assert.Equal(t, defaultInfoTimeout*time.Second, timeouts.info())
+
+	timeouts = &MultipassServerTimeouts{
+		Drain: 10,
+	}
+
+	assert.Equal(t, 10*time.Second, timeouts.drain())
+	assert.Equal(t, defaultDeleteTimeout*time.Second, timeouts.delete())
+	assert.Equal(t, defaultJoinTimeout*time.Second, timeouts.join())
+	assert.Equal(t, defaultDeleteTimeout*time.Second, timeouts.start())
+
+	timeouts = &MultipassServerTimeouts{
+		Delete: 60,
+		Start:  300,
+	}
+
+	assert.Equal(t, 300*time.Second, timeouts.start())
+	assert.Equal(t, 60*time.Second, timeouts.stop(), "stop use the delete timeout")
+}
+
+func Test_shellTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+
+	defer cancel()
+
+	start := time.Now()
+	err := shell(ctx, "sleep", "10")
+
+	if assert.Error(t, err) {
+		assert.True(t, strings.HasPrefix(err.Error(), "Command: sleep 10 canceled"), err.Error())
+		assert.True(t, time.Since(start) < 5*time.Second)
+	}
+}
+
+func Test_pipeCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cancel()
+
+	_, err := pipe(ctx, "echo", "hello")
+
+	assert.Error(t, err)
+
+	out, err := pipe(context.Background(), "echo", "hello")
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "hello", out)
+	}
+}
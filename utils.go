@@ -53,15 +53,15 @@ func nodeGroupIDFromProviderID(serverIdentifier string, providerID string) (stri
 	}
 
 	if nodeIdentifier == nil {
-		return "", fmt.Errorf(errCantDecodeNodeID, providerID)
+		return "", newError(kindInvalidArgument, errCantDecodeNodeID, providerID)
 	}
 
 	if nodeIdentifier.Scheme != serverIdentifier {
-		return "", fmt.Errorf(errWrongSchemeInProviderID, providerID, nodeIdentifier.Scheme)
+		return "", newError(kindInvalidArgument, errWrongSchemeInProviderID, providerID, nodeIdentifier.Scheme)
 	}
 
 	if nodeIdentifier.Path != "object" && nodeIdentifier.Path != "/object" {
-		return "", fmt.Errorf(errWrongPathInProviderID, providerID, nodeIdentifier.Path)
+		return "", newError(kindInvalidArgument, errWrongPathInProviderID, providerID, nodeIdentifier.Path)
 	}
 
 	return nodeIdentifier.Hostname(), nil
@@ -76,15 +76,15 @@ func nodeNameFromProviderID(serverIdentifier string, providerID string) (string,
 	}
 
 	if nodeIdentifier == nil {
-		return "", fmt.Errorf(errCantDecodeNodeID, providerID)
+		return "", newError(kindInvalidArgument, errCantDecodeNodeID, providerID)
 	}
 
 	if nodeIdentifier.Scheme != serverIdentifier {
-		return "", fmt.Errorf(errWrongSchemeInProviderID, providerID, nodeIdentifier.Scheme)
+		return "", newError(kindInvalidArgument, errWrongSchemeInProviderID, providerID, nodeIdentifier.Scheme)
 	}
 
 	if nodeIdentifier.Path != "object" && nodeIdentifier.Path != "/object" {
-		return "", fmt.Errorf(errWrongPathInProviderID, providerID, nodeIdentifier.Path)
+		return "", newError(kindInvalidArgument, errWrongPathInProviderID, providerID, nodeIdentifier.Path)
 	}
 
 	return nodeIdentifier.Query().Get("name"), nil
@@ -0,0 +1,597 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	backendVSphere = "vsphere"
+
+	defaultVSphereNetworkAdapter = "vmxnet3"
+)
+
+// VSphereConfig declare how to reach vCenter and how VMs are cloned
+type VSphereConfig struct {
+	URL          string `json:"url"`           // Mandatory, vCenter SDK URL, https://vcenter/sdk
+	User         string `json:"user"`          // Optional, user, override the URL user
+	Password     string `json:"password"`      // Optional, password, override the URL password
+	Insecure     bool   `json:"insecure"`      // Optional, don't verify the vCenter certificate
+	Datacenter   string `json:"datacenter"`    // Optional, datacenter, the default datacenter when empty
+	Datastore    string `json:"datastore"`     // Optional, datastore of the clones, the template datastore when empty
+	ResourcePool string `json:"resource-pool"` // Optional, resource pool of the clones, the template pool when empty
+	Folder       string `json:"folder"`        // Optional, folder of the clones, the datacenter VM folder when empty
+	Template     string `json:"template"`      // Mandatory, template VM cloned for each VM, the machine type image override it
+	Network      string `json:"network"`       // Optional, network of the VM card, the template cards are kept when empty
+	SSHUser      string `json:"ssh-user"`      // Optional, user to exec commands in the VM, ubuntu by default
+	SSHKey       string `json:"ssh-key"`       // Optional, private key to exec commands in the VM
+}
+
+// vsphereBackend drive the VMs of a vCenter with govmomi
+type vsphereBackend struct {
+	sync.Mutex
+	config *VSphereConfig
+	client *govmomi.Client
+	ssh    *sshRemote
+}
+
+func newVSphereBackend(config *VSphereConfig) *vsphereBackend {
+	if config == nil {
+		config = &VSphereConfig{}
+	}
+
+	return &vsphereBackend{
+		config: config,
+		ssh: &sshRemote{
+			user: config.SSHUser,
+			key:  config.SSHKey,
+		},
+	}
+}
+
+// connect returns a logged client, the session is reopened when expired
+func (b *vsphereBackend) connect(ctx context.Context) (*govmomi.Client, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.client != nil {
+		if session, err := b.client.SessionManager.UserSession(ctx); err == nil && session != nil {
+			return b.client, nil
+		}
+	}
+
+	u, err := url.Parse(b.config.URL)
+
+	if err != nil {
+		return nil, wrapError(kindInvalidArgument, err, errVSphereConnectFailed, b.config.URL, err)
+	}
+
+	if len(b.config.User) > 0 {
+		u.User = url.UserPassword(b.config.User, b.config.Password)
+	}
+
+	if b.client, err = govmomi.NewClient(ctx, u, b.config.Insecure); err != nil {
+		return nil, wrapError(kindVM, err, errVSphereConnectFailed, u.Host, err)
+	}
+
+	return b.client, nil
+}
+
+// finder returns a finder set on the datacenter
+func (b *vsphereBackend) finder(ctx context.Context) (*find.Finder, *object.Datacenter, error) {
+	client, err := b.connect(ctx)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	finder := find.NewFinder(client.Client, true)
+	dc, err := finder.DatacenterOrDefault(ctx, b.config.Datacenter)
+
+	if err != nil {
+		return nil, nil, vsphereError(err, b.config.Datacenter)
+	}
+
+	return finder.SetDatacenter(dc), dc, nil
+}
+
+// vsphereError classify govmomi errors, missing objects are not found
+func vsphereError(err error, name string) error {
+	if _, ok := err.(*find.NotFoundError); ok {
+		return wrapError(kindNotFound, err, errVMNotFound, name)
+	}
+
+	return wrapError(kindVM, err, errVSphereOperationFailed, name, err)
+}
+
+func (b *vsphereBackend) vm(ctx context.Context, name string) (*object.VirtualMachine, error) {
+	finder, _, err := b.finder(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	vm, err := finder.VirtualMachine(ctx, name)
+
+	if err != nil {
+		return nil, vsphereError(err, name)
+	}
+
+	return vm, nil
+}
+
+// guestInfo returns the cloud-init user data and meta data as guestinfo
+func (b *vsphereBackend) guestInfo(spec *VMLaunchSpec) ([]types.BaseOptionValue, error) {
+	userData, err := spec.userData()
+
+	if err != nil {
+		return nil, err
+	}
+
+	metaData, err := yaml.Marshal(map[string]string{
+		"instance-id":    spec.Name,
+		"local-hostname": spec.Name,
+	})
+
+	if err != nil {
+		return nil, wrapError(kindInternal, err, errUnableToEncodeGuestInfo, "metadata", err)
+	}
+
+	encode := func(data []byte) string {
+		return base64.StdEncoding.EncodeToString(data)
+	}
+
+	return []types.BaseOptionValue{
+		&types.OptionValue{Key: "guestinfo.userdata", Value: encode(userData)},
+		&types.OptionValue{Key: "guestinfo.userdata.encoding", Value: "base64"},
+		&types.OptionValue{Key: "guestinfo.metadata", Value: encode(metaData)},
+		&types.OptionValue{Key: "guestinfo.metadata.encoding", Value: "base64"},
+	}, nil
+}
+
+// deviceChanges resize the first disk and replace the network cards
+func (b *vsphereBackend) deviceChanges(ctx context.Context, finder *find.Finder, vm *object.VirtualMachine, spec *VMLaunchSpec) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	var changes []types.BaseVirtualDeviceConfigSpec
+
+	devices, err := vm.Device(ctx)
+
+	if err != nil {
+		return nil, wrapError(kindVM, err, errUnableToCreateDeviceChangeOp, spec.Name, err)
+	}
+
+	if disks := devices.SelectByType((*types.VirtualDisk)(nil)); spec.Disk > 0 {
+		if len(disks) == 0 {
+			return nil, newError(kindVM, errUnableToAddHardDrive, spec.Name, "template has no disk")
+		}
+
+		disk := disks[0].(*types.VirtualDisk)
+		size := int64(spec.Disk) * 1024
+
+		if disk.CapacityInKB < size {
+			disk.CapacityInKB = size
+			disk.CapacityInBytes = size * 1024
+
+			changes = append(changes, &types.VirtualDeviceConfigSpec{
+				Operation: types.VirtualDeviceConfigSpecOperationEdit,
+				Device:    disk,
+			})
+		}
+	}
+
+	if len(b.config.Network) > 0 {
+		network, err := finder.Network(ctx, b.config.Network)
+
+		if err != nil {
+			return nil, wrapError(kindNotFound, err, errUnableToAddNetworkCard, spec.Name, err)
+		}
+
+		backing, err := network.EthernetCardBackingInfo(ctx)
+
+		if err != nil {
+			return nil, wrapError(kindVM, err, errUnableToAddNetworkCard, spec.Name, err)
+		}
+
+		card, err := object.EthernetCardTypes().CreateEthernetCard(defaultVSphereNetworkAdapter, backing)
+
+		if err != nil {
+			return nil, wrapError(kindVM, err, errUnableToAddNetworkCard, spec.Name, err)
+		}
+
+		for _, device := range devices.SelectByType((*types.VirtualEthernetCard)(nil)) {
+			changes = append(changes, &types.VirtualDeviceConfigSpec{
+				Operation: types.VirtualDeviceConfigSpecOperationRemove,
+				Device:    device,
+			})
+		}
+
+		changes = append(changes, &types.VirtualDeviceConfigSpec{
+			Operation: types.VirtualDeviceConfigSpecOperationAdd,
+			Device:    card,
+		})
+	}
+
+	return changes, nil
+}
+
+func (b *vsphereBackend) Launch(ctx context.Context, spec *VMLaunchSpec) error {
+	finder, dc, err := b.finder(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	templateName := spec.Image
+
+	if len(templateName) == 0 {
+		templateName = b.config.Template
+	}
+
+	if len(templateName) == 0 {
+		return newError(kindInvalidArgument, errVSphereTemplateNotDefined)
+	}
+
+	if _, err = finder.VirtualMachine(ctx, spec.Name); err == nil {
+		return newError(kindAlreadyExists, errVMAlreadyCreated, spec.Name)
+	}
+
+	template, err := finder.VirtualMachine(ctx, templateName)
+
+	if err != nil {
+		return vsphereError(err, templateName)
+	}
+
+	folder, err := b.folder(ctx, finder, dc)
+
+	if err != nil {
+		return err
+	}
+
+	location, err := b.location(ctx, finder, template)
+
+	if err != nil {
+		return err
+	}
+
+	task, err := template.Clone(ctx, folder, spec.Name, types.VirtualMachineCloneSpec{
+		Location: *location,
+	})
+
+	if err == nil {
+		err = task.Wait(ctx)
+	}
+
+	if err != nil {
+		return wrapError(kindVM, err, errUnableToLaunchVM, spec.Name, err)
+	}
+
+	vm, err := finder.VirtualMachine(ctx, spec.Name)
+
+	if err != nil {
+		return vsphereError(err, spec.Name)
+	}
+
+	if err = b.reconfigure(ctx, finder, vm, spec); err == nil {
+		if task, err = vm.PowerOn(ctx); err == nil {
+			err = task.Wait(ctx)
+		}
+	}
+
+	if err != nil {
+		if task, e := vm.Destroy(ctx); e == nil {
+			task.Wait(ctx)
+		}
+
+		return wrapError(kindVM, err, errUnableToLaunchVM, spec.Name, err)
+	}
+
+	if _, err = vm.WaitForIP(ctx, true); err != nil {
+		return wrapError(kindTimeout, err, errVMNoAddress, spec.Name, err)
+	}
+
+	return nil
+}
+
+// location returns the resource pool and datastore of the clone, the template ones when not configured
+func (b *vsphereBackend) location(ctx context.Context, finder *find.Finder, template *object.VirtualMachine) (*types.VirtualMachineRelocateSpec, error) {
+	var pool *object.ResourcePool
+	var err error
+
+	location := &types.VirtualMachineRelocateSpec{}
+
+	if len(b.config.ResourcePool) > 0 {
+		pool, err = finder.ResourcePool(ctx, b.config.ResourcePool)
+	} else if pool, err = template.ResourcePool(ctx); err != nil {
+		// A template has no resource pool
+		pool, err = finder.DefaultResourcePool(ctx)
+	}
+
+	if err != nil {
+		return nil, vsphereError(err, b.config.ResourcePool)
+	}
+
+	poolRef := pool.Reference()
+	location.Pool = &poolRef
+
+	if len(b.config.Datastore) > 0 {
+		datastore, err := finder.Datastore(ctx, b.config.Datastore)
+
+		if err != nil {
+			return nil, vsphereError(err, b.config.Datastore)
+		}
+
+		datastoreRef := datastore.Reference()
+		location.Datastore = &datastoreRef
+	}
+
+	return location, nil
+}
+
+func (b *vsphereBackend) folder(ctx context.Context, finder *find.Finder, dc *object.Datacenter) (*object.Folder, error) {
+	if len(b.config.Folder) > 0 {
+		folder, err := finder.Folder(ctx, b.config.Folder)
+
+		if err != nil {
+			return nil, vsphereError(err, b.config.Folder)
+		}
+
+		return folder, nil
+	}
+
+	folders, err := dc.Folders(ctx)
+
+	if err != nil {
+		return nil, vsphereError(err, dc.Name())
+	}
+
+	return folders.VmFolder, nil
+}
+
+// reconfigure set CPU, memory, disk, network cards and cloud-init guestinfo
+func (b *vsphereBackend) reconfigure(ctx context.Context, finder *find.Finder, vm *object.VirtualMachine, spec *VMLaunchSpec) error {
+	extraConfig, err := b.guestInfo(spec)
+
+	if err != nil {
+		return err
+	}
+
+	deviceChanges, err := b.deviceChanges(ctx, finder, vm, spec)
+
+	if err != nil {
+		return err
+	}
+
+	config := types.VirtualMachineConfigSpec{
+		ExtraConfig:  extraConfig,
+		DeviceChange: deviceChanges,
+	}
+
+	if spec.CPU > 0 {
+		config.NumCPUs = int32(spec.CPU)
+	}
+
+	if spec.Memory > 0 {
+		config.MemoryMB = int64(spec.Memory)
+	}
+
+	task, err := vm.Reconfigure(ctx, config)
+
+	if err == nil {
+		err = task.Wait(ctx)
+	}
+
+	if err != nil {
+		return wrapError(kindVM, err, errUnableToReconfigureVM, spec.Name, err)
+	}
+
+	return nil
+}
+
+func (b *vsphereBackend) Start(ctx context.Context, name string) error {
+	vm, err := b.vm(ctx, name)
+
+	if err != nil {
+		return err
+	}
+
+	task, err := vm.PowerOn(ctx)
+
+	if err == nil {
+		err = task.Wait(ctx)
+	}
+
+	if err != nil {
+		return wrapError(kindVM, err, errStartVMFailed, name, err)
+	}
+
+	return nil
+}
+
+// Stop shutdown the guest, the VM is powered off when the tools are not running
+func (b *vsphereBackend) Stop(ctx context.Context, name string) error {
+	vm, err := b.vm(ctx, name)
+
+	if err != nil {
+		return err
+	}
+
+	if err = vm.ShutdownGuest(ctx); err != nil {
+		glog.Warningf("Unable to shutdown guest of VM: %s, power off, reason: %v", name, err)
+
+		var task *object.Task
+
+		if task, err = vm.PowerOff(ctx); err == nil {
+			err = task.Wait(ctx)
+		}
+	}
+
+	if err == nil {
+		err = vm.WaitForPowerState(ctx, types.VirtualMachinePowerStatePoweredOff)
+	}
+
+	if err != nil {
+		return wrapError(kindVM, err, errStopVMFailed, name, err)
+	}
+
+	return nil
+}
+
+func (b *vsphereBackend) Delete(ctx context.Context, name string) error {
+	vm, err := b.vm(ctx, name)
+
+	if err != nil {
+		return err
+	}
+
+	state, err := vm.PowerState(ctx)
+
+	if err == nil && state == types.VirtualMachinePowerStatePoweredOn {
+		var task *object.Task
+
+		if task, err = vm.PowerOff(ctx); err == nil {
+			err = task.Wait(ctx)
+		}
+	}
+
+	if err == nil {
+		var task *object.Task
+
+		if task, err = vm.Destroy(ctx); err == nil {
+			err = task.Wait(ctx)
+		}
+	}
+
+	if err != nil {
+		return wrapError(kindVM, err, errDeleteVMFailed, name, err)
+	}
+
+	return nil
+}
+
+func (b *vsphereBackend) Status(ctx context.Context, name string) (*VMStatus, error) {
+	var mvm mo.VirtualMachine
+
+	vm, err := b.vm(ctx, name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = vm.Properties(ctx, vm.Reference(), []string{"runtime.powerState", "guest.net", "guest.ipAddress"}, &mvm); err != nil {
+		return nil, wrapError(kindVM, err, errGetVMInfoFailed, name, err)
+	}
+
+	status := &VMStatus{
+		Addresses: []string{},
+	}
+
+	switch mvm.Runtime.PowerState {
+	case types.VirtualMachinePowerStatePoweredOn:
+		status.State = MultipassNodeStateRunning
+	case types.VirtualMachinePowerStatePoweredOff:
+		status.State = MultipassNodeStateStopped
+	default:
+		glog.Infof(errVMStateUndefined, name, mvm.Runtime.PowerState)
+
+		status.State = MultipassNodeStateUndefined
+	}
+
+	if mvm.Guest != nil {
+		for _, nic := range mvm.Guest.Net {
+			for _, address := range nic.IpAddress {
+				if ip := net.ParseIP(address); ip != nil && ip.To4() != nil {
+					status.Addresses = append(status.Addresses, address)
+				}
+			}
+		}
+
+		if len(status.Addresses) == 0 && len(mvm.Guest.IpAddress) > 0 {
+			status.Addresses = append(status.Addresses, mvm.Guest.IpAddress)
+		}
+	}
+
+	return status, nil
+}
+
+// address returns the first address of a running VM
+func (b *vsphereBackend) address(ctx context.Context, name string) (string, error) {
+	status, err := b.Status(ctx, name)
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(status.Addresses) == 0 {
+		return "", newError(kindVM, errVMNoAddress, name, "guest has no address")
+	}
+
+	return status.Addresses[0], nil
+}
+
+func (b *vsphereBackend) Exec(ctx context.Context, name string, args ...string) (string, error) {
+	address, err := b.address(ctx, name)
+
+	if err != nil {
+		return "", err
+	}
+
+	return b.ssh.exec(ctx, address, args...)
+}
+
+func (b *vsphereBackend) CopyFile(ctx context.Context, name, src, dst string) error {
+	address, err := b.address(ctx, name)
+
+	if err != nil {
+		return err
+	}
+
+	return b.ssh.copy(ctx, address, src, dst)
+}
+
+// Mount is not supported, there is no host folder sharing with vSphere
+func (b *vsphereBackend) Mount(ctx context.Context, name, hostPath, guestPath string) error {
+	return newError(kindUnimplemented, errMountUnsupported, hostPath, name, backendVSphere)
+}
+
+func (b *vsphereBackend) List(ctx context.Context) ([]string, error) {
+	finder, dc, err := b.finder(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	folder, err := b.folder(ctx, finder, dc)
+
+	if err != nil {
+		return nil, err
+	}
+
+	vms, err := finder.VirtualMachineList(ctx, fmt.Sprintf("%s/*", folder.InventoryPath))
+
+	if err != nil {
+		if _, ok := err.(*find.NotFoundError); ok {
+			return []string{}, nil
+		}
+
+		return nil, vsphereError(err, folder.InventoryPath)
+	}
+
+	names := make([]string, 0, len(vms))
+
+	for _, vm := range vms {
+		names = append(names, vm.Name())
+	}
+
+	return names, nil
+}
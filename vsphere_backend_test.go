@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25/types"
+	"google.golang.org/grpc/codes"
+)
+
+// newTestVSphere returns a backend connected to an in process vCenter simulator
+func newTestVSphere(t *testing.T) *vsphereBackend {
+	model := simulator.VPX()
+
+	if err := model.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	server := model.Service.NewServer()
+
+	t.Cleanup(func() {
+		server.Close()
+		model.Remove()
+	})
+
+	return newVSphereBackend(&VSphereConfig{
+		URL:      server.URL.String(),
+		Insecure: true,
+		Template: "DC0_H0_VM0",
+		Network:  "DC0_DVPG0",
+	})
+}
+
+// simulatedVM returns the simulator VM, nil if not found
+func simulatedVM(name string) *simulator.VirtualMachine {
+	for _, entity := range simulator.Map.All("VirtualMachine") {
+		if vm := entity.(*simulator.VirtualMachine); vm.Name == name {
+			return vm
+		}
+	}
+
+	return nil
+}
+
+// assignGuestIP play the VMware tools, the simulator has no guest reporting an address
+func assignGuestIP(ctx context.Context, name, address string) {
+	for ctx.Err() == nil {
+		if vm := simulatedVM(name); vm != nil {
+			assigned := false
+
+			simulator.Map.WithLock(vm, func() {
+				if vm.Runtime.PowerState == types.VirtualMachinePowerStatePoweredOn {
+					simulator.Map.Update(vm, []types.PropertyChange{
+						{Name: "guest.ipAddress", Val: address},
+						{Name: "guest.net", Val: []types.GuestNicInfo{{IpAddress: []string{"fe80::1", address}}}},
+					})
+
+					assigned = true
+				}
+			})
+
+			if assigned {
+				return
+			}
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func Test_vsphereBackend(t *testing.T) {
+	b := newTestVSphere(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+
+	defer cancel()
+
+	spec := &VMLaunchSpec{
+		Name:   testNodeName,
+		Memory: 2048,
+		CPU:    2,
+		Disk:   5120,
+		CloudInit: map[string]interface{}{
+			"package_update": true,
+		},
+	}
+
+	go assignGuestIP(ctx, testNodeName, "10.0.0.3")
+
+	if !assert.NoError(t, b.Launch(ctx, spec)) {
+		return
+	}
+
+	vm := simulatedVM(testNodeName)
+
+	if assert.NotNil(t, vm) {
+		assert.Equal(t, int32(2), vm.Config.Hardware.NumCPU)
+		assert.Equal(t, int32(2048), vm.Config.Hardware.MemoryMB)
+
+		extraConfig := map[string]string{}
+
+		for _, option := range vm.Config.ExtraConfig {
+			value := option.GetOptionValue()
+			extraConfig[value.Key] = value.Value.(string)
+		}
+
+		userData, err := base64.StdEncoding.DecodeString(extraConfig["guestinfo.userdata"])
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(userData), "#cloud-config\npackage_update: true"))
+		assert.Equal(t, "base64", extraConfig["guestinfo.metadata.encoding"])
+
+		devices := object.VirtualDeviceList(vm.Config.Hardware.Device)
+		disk := devices.SelectByType((*types.VirtualDisk)(nil))[0].(*types.VirtualDisk)
+		cards := devices.SelectByType((*types.VirtualEthernetCard)(nil))
+
+		assert.Equal(t, int64(5120*1024), disk.CapacityInKB)
+
+		if assert.Len(t, cards, 1) {
+			assert.Contains(t, devices.Name(cards[0]), "ethernet")
+		}
+	}
+
+	assert.Equal(t, codes.AlreadyExists, kindOf(b.Launch(ctx, spec)).code)
+
+	status, err := b.Status(ctx, testNodeName)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, MultipassNodeStateRunning, status.State)
+		assert.Equal(t, []string{"10.0.0.3"}, status.Addresses)
+	}
+
+	assert.Equal(t, codes.Unimplemented, kindOf(b.Mount(ctx, testNodeName, "/home", "/mnt")).code)
+
+	names, err := b.List(ctx)
+
+	assert.NoError(t, err)
+	assert.Contains(t, names, testNodeName)
+
+	if assert.NoError(t, b.Stop(ctx, testNodeName)) {
+		status, _ = b.Status(ctx, testNodeName)
+
+		assert.Equal(t, MultipassNodeStateStopped, status.State)
+	}
+
+	if assert.NoError(t, b.Start(ctx, testNodeName)) {
+		status, _ = b.Status(ctx, testNodeName)
+
+		assert.Equal(t, MultipassNodeStateRunning, status.State)
+	}
+
+	assert.NoError(t, b.Delete(ctx, testNodeName))
+
+	_, err = b.Status(ctx, testNodeName)
+
+	assert.Equal(t, codes.NotFound, kindOf(err).code)
+}
+
+func Test_vsphereBackend_templateNotFound(t *testing.T) {
+	b := newTestVSphere(t)
+
+	err := b.Launch(context.Background(), &VMLaunchSpec{Name: testNodeName, Image: "missing"})
+
+	assert.Equal(t, codes.NotFound, kindOf(err).code)
+	assert.Nil(t, simulatedVM(testNodeName))
+}
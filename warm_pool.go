@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+
+	"github.com/golang/glog"
+)
+
+// fillWarmPool launch, join, cordon and stop VMs until the warm pool is full.
+// It takes the group lock only to book the nodes, so it could run in background
+func (g *MultipassNodeGroup) fillWarmPool(ctx context.Context, extras *nodeCreationExtra) {
+	glog.V(5).Infof("MultipassNodeGroup::fillWarmPool, nodeGroupID:%s", g.NodeGroupIdentifier)
+
+	g.Lock()
+	g.standbyLock.Lock()
+
+	if g.WarmNodes == nil {
+		g.WarmNodes = make(map[string]*MultipassNode)
+	}
+
+	if g.PendingWarmNodes == nil {
+		g.PendingWarmNodes = make(map[string]*MultipassNode)
+	}
+
+	missing := g.WarmPoolSize - len(g.WarmNodes) - len(g.PendingWarmNodes)
+	tempNodes := make([]*MultipassNode, 0, maxInt(missing, 0))
+
+	for index := 0; index < missing && g.Status == NodegroupCreated; index++ {
+		host, err := extras.placement.place(g.Machine)
+
+		if err != nil {
+			glog.Errorf(errUnableToFillWarmPool, g.NodeGroupIdentifier, err)
+			break
+		}
+
+		node := g.newNode(host)
+
+		g.PendingWarmNodes[node.NodeName] = node
+
+		tempNodes = append(tempNodes, node)
+	}
+
+	g.PendingNodesWG.Add(len(tempNodes))
+
+	g.standbyLock.Unlock()
+	g.Unlock()
+
+	for _, node := range tempNodes {
+		err := node.launchVM(ctx, extras)
+
+		if err == nil {
+			err = node.stopVM(ctx, extras)
+		}
+
+		if err != nil {
+			glog.Errorf(errUnableToFillWarmPool, g.NodeGroupIdentifier, err)
+
+			if status, _ := node.statusVM(ctx, extras); status != MultipassNodeStateNotCreated {
+				if e := node.deleteVM(ctx, extras); e != nil {
+					glog.Errorf(errUnableToDeleteVM, node.NodeName, e)
+				}
+			}
+		}
+
+		g.Lock()
+		g.standbyLock.Lock()
+
+		delete(g.PendingWarmNodes, node.NodeName)
+
+		if err == nil {
+			glog.Infof("Warm VM:%s ready for nodegroup: %s", node.NodeName, g.NodeGroupIdentifier)
+
+			g.WarmNodes[node.NodeName] = node
+		}
+
+		g.standbyLock.Unlock()
+		g.Unlock()
+		g.PendingNodesWG.Done()
+	}
+}
+
+// discardPendingWarmNodes delete the warm VMs still booting when the previous process stopped, they were never stopped
+func (g *MultipassNodeGroup) discardPendingWarmNodes(ctx context.Context, extras *nodeCreationExtra) {
+	g.standbyLock.RLock()
+
+	nodes := make([]*MultipassNode, 0, len(g.PendingWarmNodes))
+
+	for _, node := range g.PendingWarmNodes {
+		nodes = append(nodes, node)
+	}
+
+	g.standbyLock.RUnlock()
+
+	for _, node := range nodes {
+		glog.Infof("Delete warm VM:%s interrupted by a restart", node.NodeName)
+
+		g.standbyLock.Lock()
+		delete(g.PendingWarmNodes, node.NodeName)
+		g.standbyLock.Unlock()
+
+		if err := node.deleteVM(ctx, extras); err != nil {
+			glog.Errorf(errUnableToDeleteVM, node.NodeName, err)
+		}
+	}
+}
+
+// refillWarmPool fill the warm pool in background
+func (s *MultipassServer) refillWarmPool(nodeGroup *MultipassNodeGroup) {
+	if nodeGroup.WarmPoolSize > 0 {
+		go nodeGroup.fillWarmPool(context.Background(), s.newNodeCreationExtra(nodeGroup))
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestWarmPoolGroup(size int) *MultipassNodeGroup {
+	ng := newTestNodeGroup("", withMachine(&MachineCharacteristic{Memory: 1024, Vcpu: 1, Disk: 5120}))
+
+	ng.WarmPoolSize = size
+
+	return ng
+}
+
+// launched returns the number of VMs launched by the backend
+func (b *fakeBackend) launched() int {
+	count := 0
+
+	for _, call := range b.calls {
+		if call == "launch" {
+			count++
+		}
+	}
+
+	return count
+}
+
+func Test_nodeGroup_warmPool(t *testing.T) {
+	ctx := context.Background()
+	backend := newFakeBackend()
+	extras := newFakeExtras(backend)
+	ng := newTestWarmPoolGroup(2)
+
+	ng.fillWarmPool(ctx, extras)
+
+	if !assert.Len(t, ng.WarmNodes, 2) {
+		return
+	}
+
+	assert.Equal(t, 0, ng.targetSize(), "warm VMs are excluded from target size")
+
+	for nodeName, node := range ng.WarmNodes {
+		assert.Equal(t, MultipassNodeStateStopped, node.State)
+		assert.Equal(t, MultipassNodeStateStopped, backend.vms[nodeName].State)
+		assert.True(t, ng.standbyNode(nodeName))
+	}
+
+	// The pool is full
+	ng.fillWarmPool(ctx, extras)
+
+	assert.Equal(t, 2, backend.launched())
+
+	// Scale up start a warm VM without launching
+	if assert.NoError(t, ng.setNodeGroupSize(ctx, 1, extras)) {
+		assert.Equal(t, 1, ng.targetSize())
+		assert.Len(t, ng.WarmNodes, 1)
+		assert.Equal(t, 2, backend.launched())
+
+		for _, node := range ng.Nodes {
+			assert.Equal(t, MultipassNodeStateRunning, node.State)
+			assert.False(t, ng.standbyNode(node.NodeName))
+		}
+	}
+
+	// The last warm VM is started, then a new VM is launched
+	if assert.NoError(t, ng.setNodeGroupSize(ctx, 3, extras)) {
+		assert.Equal(t, 3, ng.targetSize())
+		assert.Len(t, ng.WarmNodes, 0)
+		assert.Equal(t, 3, backend.launched())
+	}
+
+	ng.fillWarmPool(ctx, extras)
+
+	assert.Len(t, ng.WarmNodes, 2)
+	assert.Equal(t, 5, backend.launched())
+	assert.Equal(t, 5, ng.LastCreatedNodeIndex)
+
+	// Warm VMs are saved with the node group
+	saved, _ := json.Marshal(ng)
+
+	var loaded MultipassNodeGroup
+
+	if assert.NoError(t, json.Unmarshal(saved, &loaded)) {
+		assert.Equal(t, 2, loaded.WarmPoolSize)
+		assert.Len(t, loaded.WarmNodes, 2)
+	}
+
+	if assert.NoError(t, ng.cleanup(ctx, extras)) {
+		assert.Len(t, ng.WarmNodes, 0)
+		assert.Len(t, backend.vms, 0)
+	}
+}
+
+func Test_nodeGroup_warmPoolStartFailed(t *testing.T) {
+	ctx := context.Background()
+	backend := newFakeBackend()
+	extras := newFakeExtras(backend)
+	ng := newTestWarmPoolGroup(1)
+
+	ng.fillWarmPool(ctx, extras)
+
+	// The warm VM vanished, a new one is launched instead
+	for nodeName := range ng.WarmNodes {
+		delete(backend.vms, nodeName)
+	}
+
+	if assert.NoError(t, ng.setNodeGroupSize(ctx, 1, extras)) {
+		assert.Equal(t, 1, ng.targetSize())
+		assert.Len(t, ng.WarmNodes, 0)
+		assert.Equal(t, 2, backend.launched())
+	}
+
+	// Failed launch don't fill the pool
+	backend.launchErr = newError(kindVM, "no space left")
+
+	ng.fillWarmPool(ctx, extras)
+
+	assert.Len(t, ng.WarmNodes, 0)
+	assert.Len(t, ng.PendingWarmNodes, 0)
+}
+
+func Test_nodeGroup_warmPoolInterrupted(t *testing.T) {
+	ctx := context.Background()
+	backend := newFakeBackend()
+	extras := newFakeExtras(backend)
+	ng := newTestWarmPoolGroup(2)
+
+	ng.fillWarmPool(ctx, extras)
+
+	// Simulate a restart while the warm VMs were booting
+	ng.PendingWarmNodes = ng.WarmNodes
+	ng.WarmNodes = make(map[string]*MultipassNode)
+
+	saved, _ := json.Marshal(ng)
+
+	var loaded MultipassNodeGroup
+
+	if assert.NoError(t, json.Unmarshal(saved, &loaded)) && assert.Len(t, loaded.PendingWarmNodes, 2) {
+		loaded.discardPendingWarmNodes(ctx, extras)
+
+		assert.Len(t, loaded.PendingWarmNodes, 0)
+		assert.Len(t, backend.vms, 0, "interrupted warm VMs are deleted")
+	}
+}
+
+func Test_nodeGroup_warmPoolConcurrentReaders(t *testing.T) {
+	ctx := context.Background()
+	backend := newFakeBackend()
+	extras := newFakeExtras(backend)
+	ng := newTestWarmPoolGroup(3)
+	done := make(chan bool)
+
+	go func() {
+		defer close(done)
+
+		ng.fillWarmPool(ctx, extras)
+	}()
+
+	for filled := false; !filled; {
+		select {
+		case <-done:
+			filled = true
+		default:
+			ng.standbyNode(testNodeName)
+			ng.standbyNodes()
+			json.Marshal(ng.standbyNodes())
+		}
+	}
+
+	assert.Len(t, ng.standbyNodes(), 3)
+}